@@ -1,15 +1,34 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"http_grpc/internal/api/grpc"
 	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"http_grpc/internal/api/http"
+	"http_grpc/internal/repository/audit"
+	"http_grpc/internal/repository/lockout"
+	"http_grpc/internal/repository/mfa"
 	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/outbox"
+	"http_grpc/internal/repository/password"
 	"http_grpc/internal/repository/session"
+	"http_grpc/internal/repository/token"
+	"http_grpc/internal/repository/userexport"
+	"http_grpc/internal/repository/userimport"
+	"http_grpc/internal/repository/usertoken"
+	"http_grpc/internal/repository/watch"
+	"http_grpc/internal/repository/webhook"
 	"http_grpc/pkg/config"
 	"http_grpc/pkg/database"
+	"http_grpc/pkg/health"
+	"http_grpc/pkg/mailer"
 	"http_grpc/pkg/pool"
 )
 
@@ -21,7 +40,7 @@ func initRedisMysql() {
 		panic("failed to connect database")
 	}
 	// 自动迁移表结构
-	err = database.DB.AutoMigrate(&model.User{})
+	err = database.DB.AutoMigrate(&model.User{}, &model.APIKey{}, &model.UserMFA{}, &model.RecoveryCode{}, &model.UserToken{}, &model.PasswordHistory{}, &model.AuditEvent{}, &model.OutboxEvent{}, &model.Webhook{}, &model.WebhookDelivery{}, &model.ImportJob{})
 	if err != nil {
 		panic("failed to migrating tables")
 	}
@@ -31,11 +50,193 @@ func initRedisMysql() {
 	if err != nil {
 		log.Fatalf("Redis连接失败: %v", err)
 	}
-	err = session.LoadSessionsFromRedis()
+	err = session.Setup(session.Options{
+		Backend: c.Session.Backend,
+		Timeouts: session.Timeouts{
+			Idle:             c.Session.IdleTimeout,
+			Absolute:         c.Session.AbsoluteTimeout,
+			RememberIdle:     c.Session.RememberMeIdleTimeout,
+			RememberAbsolute: c.Session.RememberMeAbsoluteTimeout,
+			RefreshInterval:  c.Session.RefreshInterval,
+		},
+		MigrateOnStart: c.Session.MigrateOnStart,
+		MaxSessions:    c.Session.MaxSessions,
+		MaxMemory:      c.Session.MaxMemoryMB << 20,
+		LazyCreate:     c.Session.LazyCreate,
+		Cookie: session.CookieOptions{
+			Domain:      c.Session.Cookie.Domain,
+			Secure:      c.Session.Cookie.Secure,
+			SameSite:    session.ParseSameSite(c.Session.Cookie.SameSite),
+			SigningKeys: signingKeys(c.Session.Cookie.SigningKeys),
+		},
+	})
 	if err != nil {
 		log.Fatalf("加载Session失败: %v", err)
 	}
-	session.StartGC(60 * 24 * time.Minute)
+	session.StartGC()
+
+	mfa.Setup(mfa.Options{
+		Client:          session.Client(),
+		Issuer:          c.Auth.MFA.Issuer,
+		ChallengeTTL:    c.Auth.MFA.ChallengeTTL,
+		MaxAttempts:     c.Auth.MFA.MaxAttempts,
+		RequireForAdmin: c.Auth.MFA.RequireForAdmin,
+	})
+	lockout.Setup(lockout.Options{
+		Client:              session.Client(),
+		Window:              c.Auth.Lockout.Window,
+		MaxAccountFailures:  c.Auth.Lockout.MaxAccountFailures,
+		MaxIPFailures:       c.Auth.Lockout.MaxIPFailures,
+		LockoutDuration:     c.Auth.Lockout.LockoutDuration,
+		BaseDelay:           c.Auth.Lockout.BaseDelay,
+		MaxDelay:            c.Auth.Lockout.MaxDelay,
+		MaxRecoveryPerEmail: c.Auth.Lockout.MaxRecoveryPerEmail,
+		MaxRecoveryPerIP:    c.Auth.Lockout.MaxRecoveryPerIP,
+	})
+
+	password.Setup(password.Policy{
+		MinLength:       c.Auth.Password.MinLength,
+		MaxLength:       c.Auth.Password.MaxLength,
+		MinClasses:      c.Auth.Password.MinClasses,
+		DisallowAccount: c.Auth.Password.DisallowAccount,
+		CheckCommon:     c.Auth.Password.CheckCommon,
+		HistorySize:     c.Auth.Password.HistorySize,
+		BcryptCost:      c.Auth.Password.BcryptCost,
+	})
+	usertoken.Setup(usertoken.Options{
+		ResetTTL:  c.Auth.Recovery.ResetTTL,
+		VerifyTTL: c.Auth.Recovery.VerifyTTL,
+		ResetURL:  c.Auth.Recovery.ResetURL,
+		VerifyURL: c.Auth.Recovery.VerifyURL,
+	})
+	webhook.Setup(webhook.Options{
+		Timeout:     c.Webhooks.Timeout,
+		MaxAttempts: c.Webhooks.MaxAttempts,
+		BaseDelay:   c.Webhooks.BaseDelay,
+		MaxDelay:    c.Webhooks.MaxDelay,
+		Workers:     c.Webhooks.Workers,
+		QueueSize:   c.Webhooks.QueueSize,
+	})
+	userimport.Setup(userimport.Options{
+		BatchSize:     c.Import.BatchSize,
+		MaxRows:       c.Import.MaxRows,
+		MaxBytes:      c.Import.MaxSizeMB << 20,
+		SyncMaxBytes:  c.Import.SyncMaxKB << 10,
+		MaxConcurrent: c.Import.MaxConcurrent,
+		TempDir:       c.Import.TempDir,
+	})
+	userexport.Setup(userexport.Options{
+		MaxConcurrent: c.Export.MaxConcurrent,
+		FlushRows:     c.Export.FlushRows,
+	})
+	err = watch.Setup(watch.Options{
+		Backend:     c.Watch.Backend,
+		Client:      session.Client(),
+		Stream:      c.Watch.Stream,
+		Size:        c.Watch.Size,
+		Heartbeat:   c.Watch.Heartbeat,
+		MaxWatchers: c.Watch.MaxWatchers,
+	})
+	if err != nil {
+		log.Fatalf("用户变更推送初始化失败: %v", err)
+	}
+	if err := setupMailer(c); err != nil {
+		log.Fatalf("邮件发送初始化失败: %v", err)
+	}
+
+	if c.Auth.JWT.Enabled {
+		if err := setupTokens(c); err != nil {
+			log.Fatalf("令牌认证初始化失败: %v", err)
+		}
+	}
+
+	// 注册依赖检查，HTTP 就绪探针与 gRPC 健康服务共用
+	health.Register("mysql", database.Ping)
+	health.Register("redis", session.Ping)
+	health.Register("session_load", session.CheckLoaded)
+	health.Register("handler_pool", pool.HandlerWorkerPool.SaturationCheck(c.Health.PoolSaturation))
+	health.Register("session_pool", pool.SessionPool.SaturationCheck(c.Health.PoolSaturation))
+}
+
+// setupTokens 加载 JWT 签名密钥，未配置时生成临时密钥
+func setupTokens(c *config.Config) error {
+	var keys []token.Key
+	for _, k := range c.Auth.JWT.Keys {
+		key, err := token.LoadKey(k.ID, k.File)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		log.Println("未配置 JWT 签名密钥，使用临时密钥：重启后令牌失效，且不能用于多实例部署")
+		key, err := token.GenerateKey("ephemeral")
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	return token.Setup(token.Options{
+		Keys:       keys,
+		Issuer:     c.Auth.JWT.Issuer,
+		Audience:   c.Auth.JWT.Audience,
+		AccessTTL:  c.Auth.JWT.AccessTTL,
+		RefreshTTL: c.Auth.JWT.RefreshTTL,
+		Client:     session.Client(),
+	})
+}
+
+// setupMailer 按配置选择邮件发送方式
+func setupMailer(c *config.Config) error {
+	mailer.SetTimeout(c.Mail.Timeout)
+	switch c.Mail.Driver {
+	case mailer.DriverSMTP:
+		m, err := mailer.NewSMTP(mailer.SMTPOptions{
+			Host:     c.Mail.SMTP.Host,
+			Port:     c.Mail.SMTP.Port,
+			Username: c.Mail.SMTP.Username,
+			Password: c.Mail.SMTP.Password,
+			From:     c.Mail.From,
+		})
+		if err != nil {
+			return err
+		}
+		mailer.Use(m)
+	case mailer.DriverFile, "":
+		m, err := mailer.NewFile(c.Mail.Dir, c.Mail.From)
+		if err != nil {
+			return err
+		}
+		mailer.Use(m)
+	case mailer.DriverMemory:
+		mailer.Use(mailer.NewMemory())
+	default:
+		return fmt.Errorf("unknown mail driver %q", c.Mail.Driver)
+	}
+	return nil
+}
+
+// eventPublisher 按配置选择领域事件的发布方式
+func eventPublisher(c *config.Config) (outbox.EventPublisher, error) {
+	switch c.Events.Publisher {
+	case outbox.PublisherRedis:
+		return outbox.NewRedisPublisher(session.Client(), c.Events.Stream, c.Events.StreamMaxLen), nil
+	case outbox.PublisherInProcess:
+		return outbox.Local(), nil
+	case outbox.PublisherLog, "":
+		return outbox.LogPublisher{}, nil
+	default:
+		return nil, fmt.Errorf("unknown event publisher %q", c.Events.Publisher)
+	}
+}
+
+// signingKeys 配置中的签名密钥转换为字节切片
+func signingKeys(keys []string) [][]byte {
+	result := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, []byte(key))
+	}
+	return result
 }
 
 func init() {
@@ -47,11 +248,61 @@ func main() {
 	defer func() {
 		pool.HandlerWorkerPool.Shutdown()
 		pool.SessionPool.Shutdown()
+		pool.MailPool.Shutdown()
 	}()
 
-	// 启动 http服务
-	go http.StartHttpServer()
+	// 收到停机信号前持续提供服务
+	signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	serveCtx, cancelServe := context.WithCancel(context.Background())
 
+	// 审计事件归档，多实例部署时通过 Redis 锁只由一个实例执行
+	c := config.AppConfig
+	audit.StartRetention(serveCtx, audit.RetentionOptions{
+		Client:     session.Client(),
+		Retention:  c.Audit.Retention,
+		ArchiveDir: c.Audit.ArchiveDir,
+		Interval:   c.Audit.Interval,
+		BatchSize:  c.Audit.BatchSize,
+	})
+	// 用户变更推送，停机时结束全部推送流
+	if err := watch.Start(serveCtx); err != nil {
+		log.Fatalf("用户变更推送启动失败: %v", err)
+	}
+	// Webhook 重试，到期时间保存在数据库中，重启后继续
+	webhook.StartRetries(serveCtx)
+	// 领域事件中继，同样只由一个实例发布以保证顺序；事件同时投递给订阅的 Webhook 与变更推送
+	publisher, err := eventPublisher(c)
+	if err != nil {
+		log.Fatalf("领域事件发布初始化失败: %v", err)
+	}
+	outbox.StartRelay(serveCtx, outbox.Options{
+		Publisher:   outbox.Multi(publisher, webhook.Dispatcher{}, watch.Publisher{}),
+		Client:      session.Client(),
+		Interval:    c.Events.RelayInterval,
+		BatchSize:   c.Events.BatchSize,
+		Retention:   c.Events.Retention,
+		MaxAttempts: c.Events.MaxAttempts,
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	// 启动 http服务
+	go func() {
+		defer wg.Done()
+		http.StartHttpServer(serveCtx)
+	}()
 	// 启动 grpc服务
-	grpc.StartGrpcServer()
+	go func() {
+		defer wg.Done()
+		grpc.StartGrpcServer(serveCtx)
+	}()
+
+	<-signalCtx.Done()
+	// 排空：先让就绪探针失败，等待负载均衡摘除流量后再停止服务
+	log.Println("收到停机信号，开始排空")
+	health.StartDraining()
+	time.Sleep(config.AppConfig.Health.DrainDelay)
+	cancelServe()
+	wg.Wait()
 }
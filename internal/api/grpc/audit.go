@@ -0,0 +1,47 @@
+package grpc
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"http_grpc/internal/repository/audit"
+	"http_grpc/internal/repository/token"
+)
+
+// maxRequestIDLength 调用方自带的请求 ID 超过该长度时重新生成
+const maxRequestIDLength = 64
+
+// withAuditMeta 把调用方与客户端信息写入 context，供审计记录使用，需在凭证校验之后调用。
+// 经网关转发的请求记为 http，请求 ID 与 User-Agent 取网关转发的值
+func withAuditMeta(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	meta := audit.Meta{
+		Transport: audit.TransportGRPC,
+		IP:        clientIP(ctx),
+		UserAgent: firstMetadata(md, "grpcgateway-user-agent", "user-agent"),
+		RequestID: firstMetadata(md, "x-request-id"),
+	}
+	if meta.RequestID == "" || len(meta.RequestID) > maxRequestIDLength {
+		meta.RequestID = audit.NewRequestID()
+	}
+	// 网关请求的请求 ID 已由 HTTP 端写入响应头
+	if viaGateway(ctx) {
+		meta.Transport = audit.TransportHTTP
+	} else {
+		_ = grpc.SetHeader(ctx, metadata.Pairs("x-request-id", meta.RequestID))
+	}
+	if id := token.FromContext(ctx); id != nil {
+		meta.ActorID, meta.ActorAccount = id.UserID, id.UserAccount
+	}
+	return audit.WithMeta(ctx, meta)
+}
+
+// firstMetadata 按顺序返回第一个非空的元数据值
+func firstMetadata(md metadata.MD, keys ...string) string {
+	for _, key := range keys {
+		if values := md.Get(key); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+package grpc
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"http_grpc/internal/repository/token"
+	"http_grpc/internal/service"
+	userpb "http_grpc/proto/user"
+	"net"
+	"path"
+	"strings"
+)
+
+// publicMethods 开启 requireAuth 后仍允许匿名调用的方法
+var publicMethods = map[string]bool{
+	userpb.UserService_CreateUser_FullMethodName:     true,
+	userpb.UserService_Login_FullMethodName:          true,
+	userpb.UserService_VerifyMfa_FullMethodName:      true,
+	userpb.UserService_ForgotPassword_FullMethodName: true,
+	userpb.UserService_ResetPassword_FullMethodName:  true,
+	userpb.UserService_VerifyEmail_FullMethodName:    true,
+	userpb.UserService_RefreshToken_FullMethodName:   true,
+}
+
+// authInterceptor 校验 authorization 元数据中的凭证（"Bearer <访问令牌>" 或 "ApiKey <密钥>"），
+// 通过后把调用方写入 context；requireAuth 为 true 时 UserService 的非公开方法必须携带有效凭证
+func authInterceptor(userService *service.UserService, requireAuth bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, userService, info.FullMethod, requireAuth)
+		if err != nil {
+			return nil, err
+		}
+		return handler(withAuditMeta(ctx), req)
+	}
+}
+
+// authStreamInterceptor 流式方法的令牌校验，规则与 authInterceptor 相同
+func authStreamInterceptor(userService *service.UserService, requireAuth bool) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), userService, info.FullMethod, requireAuth)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authedStream{ServerStream: ss, ctx: withAuditMeta(ctx)})
+	}
+}
+
+func authenticate(ctx context.Context, userService *service.UserService, method string, requireAuth bool) (context.Context, error) {
+	userMethod := strings.HasPrefix(method, "/"+userpb.UserService_ServiceDesc.ServiceName+"/")
+	scheme, raw, ok := credentialsFromMetadata(ctx)
+	if !ok {
+		if requireAuth && userMethod && !publicMethods[method] {
+			return nil, status.Error(codes.Unauthenticated, "access token required")
+		}
+		return ctx, nil
+	}
+
+	var id *token.Identity
+	switch scheme {
+	case "bearer":
+		verified, err := token.Verify(raw)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid access token")
+		}
+		id = verified
+	case "apikey":
+		authenticated, err := userService.AuthenticateAPIKey(ctx, raw)
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+		id = authenticated
+	default:
+		return nil, status.Error(codes.Unauthenticated, "unsupported authorization scheme")
+	}
+
+	// API Key 只能调用其权限范围覆盖的方法
+	if userMethod && !id.Allows(service.MethodScopes[path.Base(method)]) {
+		return nil, status.Error(codes.PermissionDenied, "api key scope does not allow this operation")
+	}
+	return token.WithIdentity(ctx, id), nil
+}
+
+// credentialsFromMetadata 读取 authorization 元数据，scheme 统一为小写
+func credentialsFromMetadata(ctx context.Context) (scheme, raw string, ok bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		scheme, raw, ok = strings.Cut(value, " ")
+		if ok && raw != "" {
+			return strings.ToLower(scheme), raw, true
+		}
+	}
+	return "", "", false
+}
+
+// clientIP 调用方 IP：经本机网关转发时从 x-forwarded-for 末尾取第一个非本机地址（即 HTTP 客户端地址），
+// 其余情况使用连接的对端地址，不信任外部客户端自带的转发头
+func clientIP(ctx context.Context) string {
+	host, loopback := peerHost(ctx)
+	if loopback {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if forwarded := md.Get("x-forwarded-for"); len(forwarded) > 0 {
+			// 与 HTTP 端信任的代理一致，跳过本机代理追加的地址
+			hops := strings.Split(forwarded[len(forwarded)-1], ",")
+			for i := len(hops) - 1; i >= 0; i-- {
+				hop := strings.TrimSpace(hops[i])
+				if ip := net.ParseIP(hop); i == 0 || ip == nil || !ip.IsLoopback() {
+					return hop
+				}
+			}
+		}
+	}
+	return host
+}
+
+// viaGateway 请求是否由本机 REST 网关转发（对端为本机且带有 x-forwarded-for）
+func viaGateway(ctx context.Context) bool {
+	if _, loopback := peerHost(ctx); !loopback {
+		return false
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return len(md.Get("x-forwarded-for")) > 0
+}
+
+// peerHost 连接对端的地址，以及是否为本机地址
+func peerHost(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "", false
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	ip := net.ParseIP(host)
+	return host, ip != nil && ip.IsLoopback()
+}
+
+// authedStream 替换流的 context
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authedStream) Context() context.Context {
+	return s.ctx
+}
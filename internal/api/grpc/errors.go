@@ -0,0 +1,10 @@
+package grpc
+
+import (
+	"http_grpc/pkg/errs"
+)
+
+// toStatusError 将 service 层错误转换为 gRPC status
+func toStatusError(err error) error {
+	return errs.GRPCStatus(err).Err()
+}
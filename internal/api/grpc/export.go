@@ -0,0 +1,46 @@
+package grpc
+
+import (
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/service"
+	userpb "http_grpc/proto/user"
+)
+
+// ExportUsers 按 id 顺序导出未删除的用户（管理员），客户端可通过 gzip 压缩减少传输量
+func (h *UserGrpcHandler) ExportUsers(req *userpb.ExportUsersRequest, stream userpb.UserService_ExportUsersServer) error {
+	ctx := stream.Context()
+	if err := service.AuthorizeAdmin(ctx); err != nil {
+		return toStatusError(err)
+	}
+	_, err := h.userService.ExportUsers(ctx, service.ExportRequest{Columns: req.Columns}, func(user *model.User) error {
+		return stream.Send(toPbExportedUser(user))
+	})
+	if err != nil && ctx.Err() == nil {
+		return toStatusError(err)
+	}
+	return nil
+}
+
+// toPbExportedUser 只查询了选择的列，其余字段为零值
+func toPbExportedUser(u *model.User) *userpb.ExportedUser {
+	res := &userpb.ExportedUser{
+		Id:            u.ID,
+		UserAccount:   u.UserAccount,
+		Username:      u.Username,
+		AvatarUrl:     u.AvatarUrl,
+		Gender:        int32(u.Gender),
+		Phone:         u.Phone,
+		Email:         u.Email,
+		EmailVerified: u.EmailVerified,
+		UserStatus:    int32(u.UserStatus),
+		UserRole:      int32(u.UserRole),
+		PlanetCode:    u.PlanetCode,
+	}
+	if !u.CreateTime.IsZero() {
+		res.CreateTime = u.CreateTime.UnixMilli()
+	}
+	if !u.UpdateTime.IsZero() {
+		res.UpdateTime = u.UpdateTime.UnixMilli()
+	}
+	return res
+}
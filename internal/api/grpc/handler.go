@@ -2,9 +2,14 @@ package grpc
 
 import (
 	"context"
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/token"
 	"http_grpc/internal/service"
+	"http_grpc/pkg/errs"
 	"http_grpc/pkg/pool"
 	userpb "http_grpc/proto/user"
+	"strings"
+	"time"
 )
 
 type UserGrpcHandler struct {
@@ -24,91 +29,177 @@ func (h *UserGrpcHandler) CreateUser(ctx context.Context, req *userpb.User) (*us
 	taskData.Reset()
 	taskData.UserData.UserAccount = req.UserAccount
 	taskData.UserData.UserPassword = req.UserPassword
+	taskData.UserData.Username = req.Username
+	taskData.UserData.AvatarUrl = req.AvatarUrl
+	taskData.UserData.Gender = int8(req.Gender)
+	taskData.UserData.Phone = req.Phone
+	taskData.UserData.Email = req.Email
 
-	err := h.userService.CreateUser(&taskData.UserData)
+	err := h.userService.CreateUser(ctx, &taskData.UserData)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
 	}
 	return &userpb.CommonResponse{Message: "User creation request accepted"}, nil
 }
 
 func (h *UserGrpcHandler) Login(ctx context.Context, req *userpb.LoginRequest) (*userpb.LoginResponse, error) {
-	id, account, err := h.userService.Login(req.UserAccount, req.UserPassword)
+	result, err := h.userService.Login(ctx, req.UserAccount, req.UserPassword, clientIP(ctx))
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
 	}
-	return &userpb.LoginResponse{
-		UserId:      id,
-		UserAccount: account,
-		Message:     "Login successful",
-	}, nil
+	if result.Challenge != "" {
+		return &userpb.LoginResponse{
+			UserId:         result.UserID,
+			Message:        "Two-factor authentication required",
+			MfaRequired:    true,
+			ChallengeToken: result.Challenge,
+		}, nil
+	}
+	return h.completeLogin(ctx, result)
+}
+
+func (h *UserGrpcHandler) VerifyMfa(ctx context.Context, req *userpb.VerifyMfaRequest) (*userpb.LoginResponse, error) {
+	result, err := h.userService.VerifyMFA(ctx, req.ChallengeToken, req.Code, clientIP(ctx))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return h.completeLogin(ctx, result)
+}
+
+func (h *UserGrpcHandler) ForgotPassword(ctx context.Context, req *userpb.ForgotPasswordRequest) (*userpb.CommonResponse, error) {
+	if err := h.userService.ForgotPassword(ctx, req.Email, clientIP(ctx)); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &userpb.CommonResponse{Message: "If the email belongs to an account, a reset link has been sent"}, nil
+}
+
+func (h *UserGrpcHandler) ResetPassword(ctx context.Context, req *userpb.ResetPasswordRequest) (*userpb.CommonResponse, error) {
+	if err := h.userService.ResetPassword(ctx, req.Token, req.NewPassword); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &userpb.CommonResponse{Message: "Password has been reset"}, nil
+}
+
+func (h *UserGrpcHandler) VerifyEmail(ctx context.Context, req *userpb.VerifyEmailRequest) (*userpb.CommonResponse, error) {
+	if err := h.userService.VerifyEmail(ctx, req.Token); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &userpb.CommonResponse{Message: "Email verified"}, nil
+}
+
+// completeLogin 登录完成后的响应
+func (h *UserGrpcHandler) completeLogin(ctx context.Context, result *service.LoginResult) (*userpb.LoginResponse, error) {
+	res := &userpb.LoginResponse{
+		UserId:                result.UserID,
+		UserAccount:           result.UserAccount,
+		Message:               "Login successful",
+		MfaEnrollmentRequired: result.MFAEnrollmentRequired,
+	}
+	// gRPC 客户端没有 Cookie，开启令牌认证时直接返回令牌
+	if token.Enabled() {
+		pair, err := h.userService.IssueTokens(ctx, result)
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+		res.Tokens = toPbTokens(pair)
+	}
+	return res, nil
+}
+
+func (h *UserGrpcHandler) RefreshToken(ctx context.Context, req *userpb.RefreshTokenRequest) (*userpb.TokenPair, error) {
+	pair, err := h.userService.RefreshTokens(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toPbTokens(pair), nil
 }
 
 func (h *UserGrpcHandler) GetUserByID(ctx context.Context, req *userpb.IdRequest) (*userpb.User, error) {
+	if err := service.AuthorizeCaller(ctx, req.Id); err != nil {
+		return nil, toStatusError(err)
+	}
 	taskData := pool.TaskDataPool.Get().(*pool.TaskData)
 	defer pool.TaskDataPool.Put(taskData)
 	taskData.Reset()
 
 	err := h.userService.GetUserByID(req.Id, &taskData.UserData)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
 	}
 
-	return &userpb.User{
-		Id:           taskData.UserData.ID,
-		UserAccount:  taskData.UserData.UserAccount,
-		UserPassword: taskData.UserData.UserPassword,
-	}, nil
+	return toPbUser(&taskData.UserData), nil
 }
 
 func (h *UserGrpcHandler) GetUserByAccount(ctx context.Context, req *userpb.AccountRequest) (*userpb.User, error) {
+	// 先确认已认证，未认证的调用方无法借此探测账号是否存在
+	if token.FromContext(ctx) == nil {
+		return nil, toStatusError(errs.New(errs.Unauthenticated, "Unauthorized"))
+	}
 	taskData := pool.TaskDataPool.Get().(*pool.TaskData)
 	defer pool.TaskDataPool.Put(taskData)
 	taskData.Reset()
 
 	err := h.userService.GetUserByAccount(req.UserAccount, &taskData.UserData)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
+	}
+	if err := service.AuthorizeCaller(ctx, taskData.UserData.ID); err != nil {
+		return nil, toStatusError(err)
 	}
 
-	return &userpb.User{
-		Id:           taskData.UserData.ID,
-		UserAccount:  taskData.UserData.UserAccount,
-		UserPassword: taskData.UserData.UserPassword,
-	}, nil
+	return toPbUser(&taskData.UserData), nil
 }
 
 func (h *UserGrpcHandler) UpdatePassword(ctx context.Context, req *userpb.UpdatePasswordRequest) (*userpb.CommonResponse, error) {
-	h.userService.UpdatePassword(req.Id, req.NewPassword)
+	if err := service.AuthorizeSelf(ctx, req.Id); err != nil {
+		return nil, toStatusError(err)
+	}
+	if err := h.userService.UpdatePassword(ctx, req.Id, req.CurrentPassword, req.NewPassword, ""); err != nil {
+		return nil, toStatusError(err)
+	}
 	return &userpb.CommonResponse{Message: "Password updated"}, nil
 }
 
+func (h *UserGrpcHandler) ForceResetPassword(ctx context.Context, req *userpb.ForceResetPasswordRequest) (*userpb.CommonResponse, error) {
+	if err := service.AuthorizeAdmin(ctx); err != nil {
+		return nil, toStatusError(err)
+	}
+	if err := h.userService.ForceResetPassword(ctx, req.Id, req.NewPassword); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &userpb.CommonResponse{Message: "Password has been reset"}, nil
+}
+
 func (h *UserGrpcHandler) ListUsers(ctx context.Context, req *userpb.ListUsersRequest) (*userpb.ListUsersResponse, error) {
+	if err := service.AuthorizeAdmin(ctx); err != nil {
+		return nil, toStatusError(err)
+	}
 	users, err := h.userService.ListUsers(int(req.Page), int(req.Size))
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
 	}
 
 	res := &userpb.ListUsersResponse{
 		Page: req.Page,
 		Size: req.Size,
 	}
-	for _, u := range users {
-		res.Users = append(res.Users, &userpb.User{
-			Id:           u.ID,
-			UserAccount:  u.UserAccount,
-			UserPassword: u.UserPassword,
-		})
+	for i := range users {
+		res.Users = append(res.Users, toPbUser(&users[i]))
 	}
 	return res, nil
 }
 
 func (h *UserGrpcHandler) DeleteUser(ctx context.Context, req *userpb.IdRequest) (*userpb.CommonResponse, error) {
-	h.userService.DeleteUser(req.Id)
+	if err := h.userService.DeleteUser(ctx, req.Id); err != nil {
+		return nil, toStatusError(err)
+	}
 	return &userpb.CommonResponse{Message: "User deletion request accepted"}, nil
 }
 
 func (h *UserGrpcHandler) UpdateUser(ctx context.Context, req *userpb.UpdateUserRequest) (*userpb.CommonResponse, error) {
+	if err := service.AuthorizeCaller(ctx, req.Id); err != nil {
+		return nil, toStatusError(err)
+	}
 	taskData := pool.TaskDataPool.Get().(*pool.TaskData)
 	defer pool.TaskDataPool.Put(taskData)
 	taskData.Reset()
@@ -132,8 +223,206 @@ func (h *UserGrpcHandler) UpdateUser(ctx context.Context, req *userpb.UpdateUser
 	}
 
 	// 2. 调用现有Service（保持您的协程池逻辑）
-	h.userService.UpdateUser(&taskData.UserData)
+	if err := h.userService.UpdateUser(ctx, &taskData.UserData); err != nil {
+		return nil, toStatusError(err)
+	}
 
 	// 3. 返回异步接受响应
 	return &userpb.CommonResponse{Message: "User update request accepted"}, nil
 }
+
+func (h *UserGrpcHandler) SuspendUser(ctx context.Context, req *userpb.IdRequest) (*userpb.CommonResponse, error) {
+	if err := h.userService.SuspendUser(ctx, req.Id); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &userpb.CommonResponse{Message: "User suspension request accepted"}, nil
+}
+
+func (h *UserGrpcHandler) UnlockUser(ctx context.Context, req *userpb.IdRequest) (*userpb.CommonResponse, error) {
+	if err := service.AuthorizeAdmin(ctx); err != nil {
+		return nil, toStatusError(err)
+	}
+	locked, err := h.userService.UnlockUser(ctx, req.Id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	if !locked {
+		return &userpb.CommonResponse{Message: "User was not locked"}, nil
+	}
+	return &userpb.CommonResponse{Message: "User unlocked"}, nil
+}
+
+func (h *UserGrpcHandler) ListSessions(ctx context.Context, req *userpb.IdRequest) (*userpb.ListSessionsResponse, error) {
+	if err := service.AuthorizeCaller(ctx, req.Id); err != nil {
+		return nil, toStatusError(err)
+	}
+	sessions, err := h.userService.ListSessions(ctx, req.Id, "")
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	res := &userpb.ListSessionsResponse{}
+	for _, s := range sessions {
+		res.Sessions = append(res.Sessions, &userpb.Session{
+			Id:        s.ID,
+			UserAgent: s.UserAgent,
+			Ip:        s.IP,
+			CreatedAt: s.CreatedAt.Unix(),
+			LastSeen:  s.LastSeen.Unix(),
+		})
+	}
+	return res, nil
+}
+
+func (h *UserGrpcHandler) RevokeSession(ctx context.Context, req *userpb.RevokeSessionRequest) (*userpb.CommonResponse, error) {
+	if err := service.AuthorizeCaller(ctx, req.UserId); err != nil {
+		return nil, toStatusError(err)
+	}
+	if err := h.userService.RevokeSession(ctx, req.UserId, req.SessionId); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &userpb.CommonResponse{Message: "Session revoked"}, nil
+}
+
+func (h *UserGrpcHandler) RevokeUserSessions(ctx context.Context, req *userpb.IdRequest) (*userpb.RevokeSessionsResponse, error) {
+	n, err := h.userService.RevokeUserSessions(ctx, req.Id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &userpb.RevokeSessionsResponse{Revoked: int32(n)}, nil
+}
+
+func (h *UserGrpcHandler) CreateApiKey(ctx context.Context, req *userpb.CreateApiKeyRequest) (*userpb.CreateApiKeyResponse, error) {
+	if err := service.AuthorizeCaller(ctx, req.UserId); err != nil {
+		return nil, toStatusError(err)
+	}
+	raw, key, err := h.userService.CreateAPIKey(ctx, req.UserId, token.FromContext(ctx).UserRole, service.CreateAPIKeyRequest{
+		Name:          req.Name,
+		Scopes:        req.Scopes,
+		ExpiresInDays: int(req.ExpiresInDays),
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &userpb.CreateApiKeyResponse{Key: raw, ApiKey: toPbAPIKey(key)}, nil
+}
+
+func (h *UserGrpcHandler) ListApiKeys(ctx context.Context, req *userpb.IdRequest) (*userpb.ListApiKeysResponse, error) {
+	if err := service.AuthorizeCaller(ctx, req.Id); err != nil {
+		return nil, toStatusError(err)
+	}
+	keys, err := h.userService.ListAPIKeys(req.Id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	res := &userpb.ListApiKeysResponse{}
+	for i := range keys {
+		res.ApiKeys = append(res.ApiKeys, toPbAPIKey(&keys[i]))
+	}
+	return res, nil
+}
+
+func (h *UserGrpcHandler) RevokeApiKey(ctx context.Context, req *userpb.RevokeApiKeyRequest) (*userpb.CommonResponse, error) {
+	if err := service.AuthorizeCaller(ctx, req.UserId); err != nil {
+		return nil, toStatusError(err)
+	}
+	if err := h.userService.RevokeAPIKey(ctx, req.UserId, req.KeyId); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &userpb.CommonResponse{Message: "API key revoked"}, nil
+}
+
+// toPbAPIKey API Key 转换为 gRPC 消息
+func toPbAPIKey(k *model.APIKey) *userpb.ApiKey {
+	res := &userpb.ApiKey{
+		Id:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		ExpiresAt: k.ExpiresAt.Unix(),
+		CreatedAt: k.CreateTime.Unix(),
+	}
+	if k.Scopes != "" {
+		res.Scopes = strings.Split(k.Scopes, ",")
+	}
+	if k.LastUsedAt != nil {
+		res.LastUsedAt = k.LastUsedAt.Unix()
+	}
+	return res
+}
+
+// ListAuditEvents 查询审计事件（管理员），page 与 size 为 0 时使用默认值
+func (h *UserGrpcHandler) ListAuditEvents(ctx context.Context, req *userpb.ListAuditEventsRequest) (*userpb.ListAuditEventsResponse, error) {
+	if err := service.AuthorizeAdmin(ctx); err != nil {
+		return nil, toStatusError(err)
+	}
+	page, size := req.Page, req.Size
+	if page == 0 {
+		page = 1
+	}
+	if size == 0 {
+		size = 20
+	}
+	filter := model.AuditFilter{
+		ActorID:   req.ActorId,
+		TargetID:  req.TargetId,
+		Action:    req.Action,
+		Transport: req.Transport,
+		RequestID: req.RequestId,
+	}
+	if req.From > 0 {
+		filter.From = time.Unix(req.From, 0)
+	}
+	if req.To > 0 {
+		filter.To = time.Unix(req.To, 0)
+	}
+	events, total, err := h.userService.ListAuditEvents(filter, int(page), int(size))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	res := &userpb.ListAuditEventsResponse{Page: page, Size: size, Total: total}
+	for i := range events {
+		res.Events = append(res.Events, toPbAuditEvent(&events[i]))
+	}
+	return res, nil
+}
+
+// toPbAuditEvent 审计事件转换为 gRPC 消息
+func toPbAuditEvent(e *model.AuditEvent) *userpb.AuditEvent {
+	return &userpb.AuditEvent{
+		Id:            e.ID,
+		CreateTime:    e.CreateTime.UnixMilli(),
+		Action:        e.Action,
+		ActorId:       e.ActorID,
+		ActorAccount:  e.ActorAccount,
+		TargetId:      e.TargetID,
+		TargetAccount: e.TargetAccount,
+		Transport:     e.Transport,
+		Ip:            e.IP,
+		UserAgent:     e.UserAgent,
+		RequestId:     e.RequestID,
+		Changes:       e.Changes,
+	}
+}
+
+// toPbTokens 令牌对转换为 gRPC 消息
+func toPbTokens(pair *token.Pair) *userpb.TokenPair {
+	return &userpb.TokenPair{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresIn:    int64(time.Until(pair.ExpiresAt).Seconds()),
+		TokenType:    pair.TokenType,
+	}
+}
+
+// toPbUser 模型对象转换为 gRPC 消息，不返回密码摘要
+func toPbUser(u *model.User) *userpb.User {
+	return &userpb.User{
+		Id:            u.ID,
+		UserAccount:   u.UserAccount,
+		Username:      u.Username,
+		AvatarUrl:     u.AvatarUrl,
+		Gender:        int32(u.Gender),
+		Phone:         u.Phone,
+		Email:         u.Email,
+		EmailVerified: u.EmailVerified,
+	}
+}
@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"context"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"http_grpc/pkg/health"
+	userpb "http_grpc/proto/user"
+	"log"
+	"time"
+)
+
+// serviceDependencies 各服务依赖的检查项，空字符串代表服务器整体状态（检查全部依赖）
+var serviceDependencies = map[string][]string{
+	"": nil,
+	userpb.UserService_ServiceDesc.ServiceName: {"mysql", "redis"},
+}
+
+const (
+	defaultHealthInterval = 10 * time.Second
+	maxCheckTimeout       = 5 * time.Second
+)
+
+// watchHealth 定期执行依赖检查，刷新 grpc.health.v1.Health 中各服务的状态
+func watchHealth(ctx context.Context, hs *grpchealth.Server, registry *health.Registry, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultHealthInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		refreshHealth(ctx, hs, registry, min(interval, maxCheckTimeout))
+		select {
+		case <-ctx.Done():
+			hs.Shutdown()
+			return
+		case <-health.DrainStarted():
+			// 排空期间所有服务均报告 NOT_SERVING，且不再更新
+			hs.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func refreshHealth(ctx context.Context, hs *grpchealth.Server, registry *health.Registry, timeout time.Duration) {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for service, deps := range serviceDependencies {
+		results, healthy := registry.Check(checkCtx, deps...)
+		status := healthpb.HealthCheckResponse_SERVING
+		if !healthy {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+			log.Printf("gRPC 健康检查失败 service=%q: %v", service, results)
+		}
+		hs.SetServingStatus(service, status)
+	}
+}
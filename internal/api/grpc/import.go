@@ -0,0 +1,120 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/userimport"
+	"http_grpc/internal/service"
+	userpb "http_grpc/proto/user"
+	"io"
+)
+
+// BulkCreateUsers 批量导入用户（管理员），每条消息一行，按批写入，流结束后返回逐行结果
+func (h *UserGrpcHandler) BulkCreateUsers(stream userpb.UserService_BulkCreateUsersServer) error {
+	ctx := stream.Context()
+	if err := service.AuthorizeAdmin(ctx); err != nil {
+		return toStatusError(err)
+	}
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return stream.SendAndClose(&userpb.BulkCreateUsersResponse{})
+	}
+	if err != nil {
+		return err
+	}
+	opts := service.ImportOptions{DryRun: first.DryRun, OnDuplicate: first.OnDuplicate}
+
+	row := 0
+	next := func() (userimport.Record, error) {
+		req := first
+		if req != nil {
+			first = nil
+		} else if req, err = stream.Recv(); err != nil {
+			return userimport.Record{}, err
+		}
+		row++
+		rec := userimport.Record{Row: row}
+		if u := req.User; u != nil {
+			rec.User = model.User{
+				UserAccount:  u.UserAccount,
+				UserPassword: u.UserPassword,
+				Username:     u.Username,
+				AvatarUrl:    u.AvatarUrl,
+				Gender:       int8(u.Gender),
+				Phone:        u.Phone,
+				Email:        u.Email,
+			}
+		}
+		return rec, nil
+	}
+
+	report, err := h.userService.ImportUsers(ctx, opts, next)
+	if report == nil || ctx.Err() != nil {
+		return toStatusError(err)
+	}
+	return stream.SendAndClose(toPbImportReport(report))
+}
+
+// GetImportJob 查询后台导入任务（管理员）
+func (h *UserGrpcHandler) GetImportJob(ctx context.Context, req *userpb.ImportJobRequest) (*userpb.ImportJob, error) {
+	if err := service.AuthorizeAdmin(ctx); err != nil {
+		return nil, toStatusError(err)
+	}
+	job, err := h.userService.GetImportJob(req.Id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	res := &userpb.ImportJob{
+		Id:          job.ID,
+		Status:      job.Status,
+		Format:      job.Format,
+		DryRun:      job.DryRun,
+		OnDuplicate: job.OnDuplicate,
+		Total:       int32(job.Total),
+		Created:     int32(job.Created),
+		Updated:     int32(job.Updated),
+		Skipped:     int32(job.Skipped),
+		Failed:      int32(job.Failed),
+		Error:       job.Error,
+		CreateTime:  job.CreateTime.UnixMilli(),
+		Rows:        toPbImportRows(job.Rows),
+	}
+	if job.FinishTime != nil {
+		res.FinishTime = job.FinishTime.UnixMilli()
+	}
+	return res, nil
+}
+
+// toPbImportReport 导入结果转换为 gRPC 消息
+func toPbImportReport(r *service.ImportReport) *userpb.BulkCreateUsersResponse {
+	return &userpb.BulkCreateUsersResponse{
+		DryRun:  r.DryRun,
+		Total:   int32(r.Total),
+		Created: int32(r.Created),
+		Updated: int32(r.Updated),
+		Skipped: int32(r.Skipped),
+		Failed:  int32(r.Failed),
+		Rows:    toPbImportRows(r.Rows),
+		Error:   r.Error,
+	}
+}
+
+func toPbImportRows(rows []service.ImportRowResult) []*userpb.ImportRowResult {
+	res := make([]*userpb.ImportRowResult, 0, len(rows))
+	for _, r := range rows {
+		row := &userpb.ImportRowResult{
+			Row:         int32(r.Row),
+			Line:        int32(r.Line),
+			UserAccount: r.UserAccount,
+			Status:      r.Status,
+			UserId:      r.UserID,
+			Reason:      r.Reason,
+		}
+		for _, v := range r.Violations {
+			row.Violations = append(row.Violations, &userpb.FieldViolation{Field: v.Field, Description: v.Description})
+		}
+		res = append(res, row)
+	}
+	return res
+}
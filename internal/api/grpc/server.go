@@ -1,16 +1,24 @@
 package grpc
 
 import (
+	"context"
 	"fmt"
 	"google.golang.org/grpc"
+	channelzsvc "google.golang.org/grpc/channelz/service"
+	_ "google.golang.org/grpc/encoding/gzip" // 注册 gzip 压缩，客户端可用于 ExportUsers 等大响应
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 	"http_grpc/pkg/config"
+	"http_grpc/pkg/health"
 	"http_grpc/pkg/pool"
 	userpb "http_grpc/proto/user"
 	"log"
 	"net"
 )
 
-func StartGrpcServer() {
+// StartGrpcServer 启动 gRPC 服务，ctx 结束后优雅停机
+func StartGrpcServer(ctx context.Context) {
 	c := config.AppConfig
 	port := c.Grpc.Port
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
@@ -18,17 +26,55 @@ func StartGrpcServer() {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	// 创建新的 gRPC 服务器实例
-	grpcServer := grpc.NewServer()
-
 	// 初始化你的 gRPC handler
 	handler := NewUserGrpcHandler(pool.HandlerWorkerPool) // 请根据你的需求传入合适的参数
 
+	// 创建新的 gRPC 服务器实例，凭证校验对所有方法生效
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(authInterceptor(handler.userService, c.Auth.RequireGRPC)),
+		grpc.StreamInterceptor(authStreamInterceptor(handler.userService, c.Auth.RequireGRPC)),
+	)
+
 	// 注册 UserService 服务
 	userpb.RegisterUserServiceServer(grpcServer, handler)
 
+	// 注册健康检查服务，状态由 MySQL、Redis 连通性驱动
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	go watchHealth(ctx, healthServer, health.DefaultRegistry, c.Grpc.HealthInterval)
+
+	// 注册反射服务，供 grpcurl 等工具发现接口
+	if c.Grpc.Reflection {
+		reflection.Register(grpcServer)
+	}
+
+	// channelz 管理服务单独监听本机端口
+	if c.Grpc.AdminPort > 0 {
+		go startAdminServer(c.Grpc.AdminPort)
+	}
+
+	go func() {
+		<-ctx.Done()
+		grpcServer.GracefulStop()
+	}()
+
 	// 启动 gRPC 服务器
 	if err := grpcServer.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
 	}
 }
+
+// startAdminServer 启动 channelz 管理端点
+func startAdminServer(port int) {
+	lis, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		log.Printf("admin server failed to listen: %v", err)
+		return
+	}
+	adminServer := grpc.NewServer()
+	channelzsvc.RegisterChannelzServiceToServer(adminServer)
+	reflection.Register(adminServer)
+	if err := adminServer.Serve(lis); err != nil {
+		log.Printf("admin server stopped: %v", err)
+	}
+}
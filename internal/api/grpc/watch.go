@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"http_grpc/internal/repository/watch"
+	"http_grpc/internal/service"
+	userpb "http_grpc/proto/user"
+)
+
+// WatchUsers 推送用户的创建、修改与删除（管理员），持续到客户端断开或服务停机
+func (h *UserGrpcHandler) WatchUsers(req *userpb.WatchUsersRequest, stream userpb.UserService_WatchUsersServer) error {
+	ctx := stream.Context()
+	if err := service.AuthorizeAdmin(ctx); err != nil {
+		return toStatusError(err)
+	}
+	err := h.userService.WatchUsers(ctx, service.WatchRequest{
+		Types:       req.Types,
+		UserIDs:     req.UserIds,
+		LastEventID: req.LastEventId,
+	}, func(m watch.Message) error {
+		return stream.Send(toPbUserChange(m))
+	})
+	if err != nil && ctx.Err() == nil {
+		return toStatusError(err)
+	}
+	return nil
+}
+
+// toPbUserChange 推送消息转换为 gRPC 消息
+func toPbUserChange(m watch.Message) *userpb.UserChange {
+	res := &userpb.UserChange{Kind: m.Kind, Id: m.ID}
+	if e := m.Event; e != nil {
+		res.EventId = e.ID
+		res.Type = e.Type
+		res.UserId = e.UserID
+		res.OccurredAt = e.OccurredAt.UnixMilli()
+		res.RequestId = e.RequestID
+		res.Data = string(e.Data)
+	}
+	return res
+}
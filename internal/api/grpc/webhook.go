@@ -0,0 +1,166 @@
+package grpc
+
+import (
+	"context"
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/service"
+	userpb "http_grpc/proto/user"
+	"strings"
+)
+
+// CreateWebhook 创建 Webhook（管理员），签名密钥只在本次响应中返回
+func (h *UserGrpcHandler) CreateWebhook(ctx context.Context, req *userpb.WebhookRequest) (*userpb.WebhookResponse, error) {
+	if err := service.AuthorizeAdmin(ctx); err != nil {
+		return nil, toStatusError(err)
+	}
+	secret, hook, err := h.userService.CreateWebhook(ctx, toWebhookRequest(req))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &userpb.WebhookResponse{Webhook: toPbWebhook(hook), Secret: secret}, nil
+}
+
+func (h *UserGrpcHandler) ListWebhooks(ctx context.Context, _ *userpb.ListWebhooksRequest) (*userpb.ListWebhooksResponse, error) {
+	if err := service.AuthorizeAdmin(ctx); err != nil {
+		return nil, toStatusError(err)
+	}
+	hooks, err := h.userService.ListWebhooks()
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	res := &userpb.ListWebhooksResponse{}
+	for i := range hooks {
+		res.Webhooks = append(res.Webhooks, toPbWebhook(&hooks[i]))
+	}
+	return res, nil
+}
+
+func (h *UserGrpcHandler) GetWebhook(ctx context.Context, req *userpb.IdRequest) (*userpb.Webhook, error) {
+	if err := service.AuthorizeAdmin(ctx); err != nil {
+		return nil, toStatusError(err)
+	}
+	hook, err := h.userService.GetWebhook(req.Id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toPbWebhook(hook), nil
+}
+
+// UpdateWebhook 更新 Webhook（管理员），rotateSecret 为 true 时返回新的签名密钥
+func (h *UserGrpcHandler) UpdateWebhook(ctx context.Context, req *userpb.WebhookRequest) (*userpb.WebhookResponse, error) {
+	if err := service.AuthorizeAdmin(ctx); err != nil {
+		return nil, toStatusError(err)
+	}
+	secret, hook, err := h.userService.UpdateWebhook(ctx, req.Id, toWebhookRequest(req))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &userpb.WebhookResponse{Webhook: toPbWebhook(hook), Secret: secret}, nil
+}
+
+func (h *UserGrpcHandler) DeleteWebhook(ctx context.Context, req *userpb.IdRequest) (*userpb.CommonResponse, error) {
+	if err := service.AuthorizeAdmin(ctx); err != nil {
+		return nil, toStatusError(err)
+	}
+	if err := h.userService.DeleteWebhook(ctx, req.Id); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &userpb.CommonResponse{Message: "Webhook deleted"}, nil
+}
+
+func (h *UserGrpcHandler) TestWebhook(ctx context.Context, req *userpb.IdRequest) (*userpb.WebhookDelivery, error) {
+	if err := service.AuthorizeAdmin(ctx); err != nil {
+		return nil, toStatusError(err)
+	}
+	d, err := h.userService.TestWebhook(req.Id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toPbWebhookDelivery(d), nil
+}
+
+// ListWebhookDeliveries 查询投递日志（管理员），page 与 size 为 0 时使用默认值
+func (h *UserGrpcHandler) ListWebhookDeliveries(ctx context.Context, req *userpb.ListWebhookDeliveriesRequest) (*userpb.ListWebhookDeliveriesResponse, error) {
+	if err := service.AuthorizeAdmin(ctx); err != nil {
+		return nil, toStatusError(err)
+	}
+	page, size := req.Page, req.Size
+	if page == 0 {
+		page = 1
+	}
+	if size == 0 {
+		size = 20
+	}
+	deliveries, total, err := h.userService.ListWebhookDeliveries(req.Id, int(page), int(size))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	res := &userpb.ListWebhookDeliveriesResponse{Page: page, Size: size, Total: total}
+	for i := range deliveries {
+		res.Deliveries = append(res.Deliveries, toPbWebhookDelivery(&deliveries[i]))
+	}
+	return res, nil
+}
+
+func (h *UserGrpcHandler) RedeliverWebhook(ctx context.Context, req *userpb.RedeliverWebhookRequest) (*userpb.WebhookDelivery, error) {
+	if err := service.AuthorizeAdmin(ctx); err != nil {
+		return nil, toStatusError(err)
+	}
+	d, err := h.userService.RedeliverWebhook(req.Id, req.DeliveryId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toPbWebhookDelivery(d), nil
+}
+
+// toWebhookRequest gRPC 请求转换为服务层参数，events 为空时不修改订阅
+func toWebhookRequest(req *userpb.WebhookRequest) service.WebhookRequest {
+	res := service.WebhookRequest{
+		URL:          req.Url,
+		Description:  req.Description,
+		RotateSecret: req.RotateSecret,
+	}
+	if len(req.Events) > 0 {
+		res.Events = req.Events
+	}
+	if req.Enabled != nil {
+		enabled := req.Enabled.Value
+		res.Enabled = &enabled
+	}
+	return res
+}
+
+// toPbWebhook Webhook 转换为 gRPC 消息，不含签名密钥
+func toPbWebhook(w *model.Webhook) *userpb.Webhook {
+	res := &userpb.Webhook{
+		Id:          w.ID,
+		Url:         w.URL,
+		Enabled:     w.Enabled,
+		Description: w.Description,
+		CreateTime:  w.CreateTime.UnixMilli(),
+		UpdateTime:  w.UpdateTime.UnixMilli(),
+	}
+	if w.Events != "" {
+		res.Events = strings.Split(w.Events, ",")
+	}
+	return res
+}
+
+// toPbWebhookDelivery 投递记录转换为 gRPC 消息
+func toPbWebhookDelivery(d *model.WebhookDelivery) *userpb.WebhookDelivery {
+	return &userpb.WebhookDelivery{
+		Id:           d.ID,
+		WebhookId:    d.WebhookID,
+		DeliveryId:   d.DeliveryID,
+		EventId:      d.EventID,
+		EventType:    d.EventType,
+		Attempt:      int32(d.Attempt),
+		Redelivery:   d.Redelivery,
+		Status:       d.Status,
+		ResponseCode: int32(d.ResponseCode),
+		ResponseBody: d.ResponseBody,
+		Error:        d.Error,
+		DurationMs:   d.DurationMs,
+		CreateTime:   d.CreateTime.UnixMilli(),
+	}
+}
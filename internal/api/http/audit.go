@@ -0,0 +1,42 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"http_grpc/internal/repository/audit"
+	"http_grpc/internal/repository/session"
+	"http_grpc/internal/repository/token"
+)
+
+// requestIDHeader 请求 ID 头，网关转发为 x-request-id 元数据
+const requestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength 客户端自带的请求 ID 超过该长度时重新生成
+const maxRequestIDLength = 64
+
+// AuditContext 把请求 ID、调用方与客户端信息写入请求 context，供审计记录使用；
+// 需注册在 Authenticate 之后。请求 ID 取自 X-Request-Id 头，没有时生成，并在响应头中返回
+func AuditContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = audit.NewRequestID()
+			c.Request.Header.Set(requestIDHeader, requestID)
+		}
+		c.Header(requestIDHeader, requestID)
+
+		meta := audit.Meta{
+			Transport: audit.TransportHTTP,
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+			RequestID: requestID,
+		}
+		if id := token.FromContext(c.Request.Context()); id != nil {
+			meta.ActorID, meta.ActorAccount = id.UserID, id.UserAccount
+		} else if s := session.Lookup(c); s != nil {
+			meta.ActorID, _ = s.Values[session.KeyUserID].(int64)
+			meta.ActorAccount, _ = s.Values[session.KeyUserAccount].(string)
+		}
+		c.Request = c.Request.WithContext(audit.WithMeta(c.Request.Context(), meta))
+		c.Next()
+	}
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+	"net/http"
+)
+
+// swaggerInitializer 覆盖 swagger-ui 默认的 petstore 地址，指向本服务的文档
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "/openapi.json",
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// SetupDocs 挂载 /openapi.json 与内嵌的 Swagger UI（/docs），需在其它路由注册完成后调用
+func SetupDocs(router *gin.Engine) error {
+	spec, err := BuildSpec(router.Routes())
+	if err != nil {
+		return err
+	}
+
+	router.GET("/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, spec)
+	})
+
+	fileServer := http.StripPrefix("/docs", http.FileServer(http.FS(swaggerFiles.FS)))
+	router.GET("/docs/*filepath", func(c *gin.Context) {
+		if c.Param("filepath") == "/swagger-initializer.js" {
+			c.Data(http.StatusOK, "application/javascript", []byte(swaggerInitializer))
+			return
+		}
+		fileServer.ServeHTTP(c.Writer, c.Request)
+	})
+	return nil
+}
@@ -0,0 +1,156 @@
+package http
+
+import (
+	"compress/gzip"
+	"github.com/gin-gonic/gin"
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/userexport"
+	"http_grpc/internal/service"
+	"http_grpc/pkg/utils"
+	"http_grpc/pkg/validator"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportUsers 导出未删除的用户（管理员），逐行从数据库读取并写出 CSV 或 NDJSON，不分页。
+// columns 为逗号分隔的列名，为空时导出除密码外的全部列；gzip=true 时下载 .gz 文件，
+// 否则在客户端支持时以 Content-Encoding: gzip 压缩传输
+func ExportUsers(c *gin.Context) {
+	if ok, _ := userService.CheckUserAuthorization(c, -1); !ok {
+		return
+	}
+
+	var v validator.Collector
+	format := strings.ToLower(c.DefaultQuery("format", userexport.FormatCSV))
+	if format != userexport.FormatCSV && format != userexport.FormatNDJSON {
+		v.Add("format", "must be csv or ndjson")
+	}
+	gzipFile := false
+	if raw := c.Query("gzip"); raw != "" {
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			v.Add("gzip", "must be a boolean")
+		}
+		gzipFile = b
+	}
+	if err := v.Err(); err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	req := service.ExportRequest{Format: format, Columns: splitQuery(c, "columns")}
+	columns, err := service.ExportColumns(req.Columns)
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+
+	// 第一行写出前出错时仍可返回普通的错误响应
+	var (
+		w       userexport.Writer
+		zw      *gzip.Writer
+		pending int
+	)
+	start := func() error {
+		h := c.Writer.Header()
+		name := "users-" + time.Now().Format("20060102-150405") + "." + format
+		if format == userexport.FormatCSV {
+			h.Set("Content-Type", "text/csv; charset=utf-8")
+		} else {
+			h.Set("Content-Type", "application/x-ndjson")
+		}
+		var out io.Writer = c.Writer
+		switch {
+		case gzipFile:
+			name += ".gz"
+			h.Set("Content-Type", "application/gzip")
+			zw = gzip.NewWriter(c.Writer)
+			out = zw
+		case acceptsGzip(c):
+			h.Set("Content-Encoding", "gzip")
+			zw = gzip.NewWriter(c.Writer)
+			out = zw
+		}
+		h.Add("Vary", "Accept-Encoding")
+		h.Set("Content-Disposition", `attachment; filename="`+name+`"`)
+		h.Set("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+		writer, err := userexport.NewWriter(format, out, columns)
+		w = writer
+		return err
+	}
+	flush := func() error {
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if zw != nil {
+			if err := zw.Flush(); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	}
+	emit := func(user *model.User) error {
+		if w == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := w.Write(user); err != nil {
+			return err
+		}
+		if pending++; pending >= userexport.FlushRows() {
+			pending = 0
+			return flush()
+		}
+		return nil
+	}
+
+	_, err = userService.ExportUsers(c.Request.Context(), req, emit)
+	if err == nil && w == nil {
+		// 没有用户时仍返回只有表头的文件
+		err = start()
+	}
+	if err != nil {
+		if w == nil && !c.Writer.Written() {
+			utils.FailErr(c, err)
+			return
+		}
+		log.Printf("User export aborted: %v", err)
+		abortStream(c)
+		return
+	}
+	if err := flush(); err != nil {
+		log.Printf("User export aborted: %v", err)
+		abortStream(c)
+		return
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			log.Printf("User export aborted: %v", err)
+		}
+	}
+}
+
+// acceptsGzip 客户端是否接受 gzip 压缩的响应
+func acceptsGzip(c *gin.Context) bool {
+	for _, part := range strings.Split(c.GetHeader("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			return strings.ReplaceAll(params, " ", "") != "q=0"
+		}
+	}
+	return false
+}
+
+// abortStream 响应已开始后出错时直接关闭连接，让客户端发现数据不完整，而不是收到一个看似正常结束的文件
+func abortStream(c *gin.Context) {
+	c.Abort()
+	if conn, _, err := c.Writer.Hijack(); err == nil {
+		conn.Close()
+	}
+}
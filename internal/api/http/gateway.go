@@ -0,0 +1,103 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"http_grpc/pkg/utils"
+	"http_grpc/pkg/validator"
+	userpb "http_grpc/proto/user"
+	"net/http"
+	"net/textproto"
+)
+
+// gatewayPrefix 网关路由前缀，与 user.proto 中 google.api.http 注解一致
+const gatewayPrefix = "/v1"
+
+// SetupGateway 将由 user.proto 生成的 REST 网关挂载到 gin 路由
+// 网关通过本地 gRPC 端口转发请求，HTTP 与 gRPC 经过相同的拦截器与 handler
+func SetupGateway(ctx context.Context, router *gin.Engine, grpcPort int) error {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				UseProtoNames:   true,
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		}),
+		runtime.WithErrorHandler(gatewayErrorHandler),
+		runtime.WithIncomingHeaderMatcher(gatewayHeaderMatcher),
+	)
+
+	endpoint := fmt.Sprintf("localhost:%d", grpcPort)
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if err := userpb.RegisterUserServiceHandlerFromEndpoint(ctx, mux, endpoint, opts); err != nil {
+		return err
+	}
+
+	router.Any(gatewayPrefix+"/*path", gin.WrapH(mux))
+	return nil
+}
+
+// gatewayHeaderMatcher 在默认规则之外把请求 ID 转发为 x-request-id 元数据，gRPC 端据此关联审计记录
+func gatewayHeaderMatcher(key string) (string, bool) {
+	if textproto.CanonicalMIMEHeaderKey(key) == requestIDHeader {
+		return "x-request-id", true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// gatewayErrorHandler 将 gRPC status 转换为与手写 handler 一致的错误响应
+func gatewayErrorHandler(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	st := status.Convert(err)
+	code := runtime.HTTPStatusFromCode(st.Code())
+
+	reason := st.Code().String()
+	var violations []validator.Violation
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			reason = d.GetReason()
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				violations = append(violations, validator.Violation{Field: v.GetField(), Description: v.GetDescription()})
+			}
+		case *errdetails.RetryInfo:
+			w.Header().Set("Retry-After", utils.RetryAfterSeconds(d.GetRetryDelay().AsDuration()))
+		}
+	}
+
+	var body interface{}
+	if utils.LegacyEnvelope {
+		w.Header().Set("Content-Type", "application/json")
+		var data interface{}
+		if len(violations) > 0 {
+			data = gin.H{"violations": violations}
+		}
+		body = utils.Response{Code: code, Msg: st.Message(), Data: data}
+		code = http.StatusOK
+	} else {
+		w.Header().Set("Content-Type", "application/problem+json")
+		body = utils.Problem{
+			Type:       "about:blank",
+			Title:      http.StatusText(code),
+			Status:     code,
+			Detail:     st.Message(),
+			Instance:   r.URL.Path,
+			Code:       reason,
+			Violations: violations,
+		}
+	}
+
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -1,15 +1,18 @@
 package http
 
 import (
-	"errors"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
+	"http_grpc/internal/repository/model"
 	"http_grpc/internal/repository/session"
+	"http_grpc/internal/repository/token"
 	"http_grpc/internal/service"
+	"http_grpc/pkg/errs"
 	"http_grpc/pkg/pool"
 	"http_grpc/pkg/utils"
+	"http_grpc/pkg/validator"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 var (
@@ -20,24 +23,39 @@ func InitUserHandler(routinePool *pool.RoutinePool) {
 	userService = service.NewUserService(routinePool)
 }
 
+// createUserRequest 注册请求，只包含客户端可以设置的字段，角色、状态等由服务端决定
+type createUserRequest struct {
+	UserAccount  string `json:"userAccount"`
+	UserPassword string `json:"userPassword"`
+	Username     string `json:"username"`
+	AvatarUrl    string `json:"avatarUrl"`
+	Gender       int8   `json:"gender"`
+	Phone        string `json:"phone"`
+	Email        string `json:"email"`
+}
+
 // CreateUser 创建用户
 func CreateUser(c *gin.Context) {
-	taskData := pool.TaskDataPool.Get().(*pool.TaskData)
-	defer pool.TaskDataPool.Put(taskData)
-	taskData.Reset()
-
+	var req createUserRequest
 	c.Request.Header.Set("Content-Type", "application/json")
-	if err := c.ShouldBind(&taskData.UserData); err != nil {
+	if err := c.ShouldBind(&req); err != nil {
 		utils.Fail(c, utils.BadRequestCode, "Invalid request payload")
 		return
 	}
 
-	if err := userService.CreateUser(&taskData.UserData); err != nil {
-		statusCode := utils.ServerErrorCode
-		if errors.Is(err, gorm.ErrDuplicatedKey) {
-			statusCode = utils.DuplicateCode
-		}
-		utils.Fail(c, statusCode, err.Error())
+	taskData := pool.TaskDataPool.Get().(*pool.TaskData)
+	defer pool.TaskDataPool.Put(taskData)
+	taskData.Reset()
+	taskData.UserData.UserAccount = req.UserAccount
+	taskData.UserData.UserPassword = req.UserPassword
+	taskData.UserData.Username = req.Username
+	taskData.UserData.AvatarUrl = req.AvatarUrl
+	taskData.UserData.Gender = req.Gender
+	taskData.UserData.Phone = req.Phone
+	taskData.UserData.Email = req.Email
+
+	if err := userService.CreateUser(c.Request.Context(), &taskData.UserData); err != nil {
+		utils.FailErr(c, err)
 		return
 	}
 
@@ -47,32 +65,134 @@ func CreateUser(c *gin.Context) {
 // Login 用户登录
 func Login(c *gin.Context) {
 	var loginReq struct {
-		Account  string `json:"userAccount"`
-		Password string `json:"userPassword"`
+		Account     string `json:"userAccount"`
+		Password    string `json:"userPassword"`
+		RememberMe  bool   `json:"rememberMe"`
+		IssueTokens bool   `json:"issueTokens"` // 同时签发访问令牌与刷新令牌（移动端、服务间调用）
 	}
 	if err := c.ShouldBindJSON(&loginReq); err != nil {
 		utils.Fail(c, utils.BadRequestCode, "Invalid request payload")
 		return
 	}
 
-	id, account, err := userService.Login(loginReq.Account, loginReq.Password)
+	result, err := userService.Login(c.Request.Context(), loginReq.Account, loginReq.Password, c.ClientIP())
 	if err != nil {
-		statusCode := utils.UnauthorizedCode
-		if !errors.Is(err, gorm.ErrRecordNotFound) {
-			statusCode = utils.ServerErrorCode
-		}
-		utils.Fail(c, statusCode, err.Error())
+		utils.FailErr(c, err)
 		return
 	}
 
-	store := session.GetSession(c)
-	store.Values["userID"] = id
-	store.Values["userAccount"] = account
+	// 已启用两步验证：返回挑战令牌，由 /users/login/mfa 完成登录
+	if result.Challenge != "" {
+		utils.Success(c, gin.H{
+			"message":        "Two-factor authentication required",
+			"mfaRequired":    true,
+			"challengeToken": result.Challenge,
+		})
+		return
+	}
+	completeLogin(c, result, loginReq.RememberMe, loginReq.IssueTokens)
+}
 
-	utils.Success(c, gin.H{
+// VerifyMfa 两步验证登录的第二步
+func VerifyMfa(c *gin.Context) {
+	var req struct {
+		ChallengeToken string `json:"challengeToken"`
+		Code           string `json:"code"` // TOTP 验证码或恢复码
+		RememberMe     bool   `json:"rememberMe"`
+		IssueTokens    bool   `json:"issueTokens"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid request payload")
+		return
+	}
+
+	result, err := userService.VerifyMFA(c.Request.Context(), req.ChallengeToken, req.Code, c.ClientIP())
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	completeLogin(c, result, req.RememberMe, req.IssueTokens)
+}
+
+// ForgotPassword 发送重置密码邮件，邮箱是否存在都返回相同结果
+func ForgotPassword(c *gin.Context) {
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid request payload")
+		return
+	}
+	if err := userService.ForgotPassword(c.Request.Context(), req.Email, c.ClientIP()); err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"message": "If the email belongs to an account, a reset link has been sent"})
+}
+
+// ResetPassword 使用邮件中的令牌设置新密码
+func ResetPassword(c *gin.Context) {
+	var req struct {
+		Token       string `json:"token"`
+		NewPassword string `json:"newPassword"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid request payload")
+		return
+	}
+	if err := userService.ResetPassword(c.Request.Context(), req.Token, req.NewPassword); err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"message": "Password has been reset"})
+}
+
+// VerifyEmail 使用邮件中的令牌验证邮箱
+func VerifyEmail(c *gin.Context) {
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid request payload")
+		return
+	}
+	if err := userService.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"message": "Email verified"})
+}
+
+// completeLogin 写入会话（按需签发令牌）并返回登录结果
+func completeLogin(c *gin.Context, result *service.LoginResult, rememberMe, issueTokens bool) {
+	// 登录成功后轮换会话 ID，不沿用客户端登录前携带的会话
+	store, err := session.Elevate(c, map[string]interface{}{
+		session.KeyUserID:      result.UserID,
+		session.KeyUserAccount: result.UserAccount,
+		session.KeyUserRole:    result.UserRole,
+		session.KeyRememberMe:  rememberMe,
+	})
+	if err != nil {
+		utils.FailErr(c, errs.Wrap(errs.Unavailable, "failed to save session", err))
+		return
+	}
+
+	data := gin.H{
 		"message":   "Login successful",
 		"sessionID": store.ID,
-	})
+	}
+	if result.MFAEnrollmentRequired {
+		data["mfaEnrollmentRequired"] = true
+	}
+	if issueTokens {
+		tokens, err := userService.IssueTokens(c.Request.Context(), result)
+		if err != nil {
+			utils.FailErr(c, err)
+			return
+		}
+		data["tokens"] = tokens
+	}
+	utils.Success(c, data)
 }
 
 // GetUserByID 根据ID获取用户
@@ -92,22 +212,19 @@ func GetUserByID(c *gin.Context) {
 	taskData.Reset()
 	err = userService.GetUserByID(id, &taskData.UserData)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			utils.Fail(c, utils.NotFoundCode, "User not found")
-		} else {
-			utils.Fail(c, utils.ServerErrorCode, "Database error")
-		}
+		utils.FailErr(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"data": taskData.UserData})
 }
 
-// GetUserByAccount 根据账号获取用户
+// GetUserByAccount 根据账号获取用户（本人或管理员）
 func GetUserByAccount(c *gin.Context) {
 	account := c.Query("userAccount")
-	if account == "" {
-		utils.Fail(c, utils.BadRequestCode, "Account is required")
+
+	// 先确认已登录，未登录的调用方无法借此探测账号是否存在
+	if _, ok := requireLogin(c); !ok {
 		return
 	}
 
@@ -117,18 +234,17 @@ func GetUserByAccount(c *gin.Context) {
 
 	err := userService.GetUserByAccount(account, &taskData.UserData)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			utils.Fail(c, utils.NotFoundCode, "User not found")
-		} else {
-			utils.Fail(c, utils.ServerErrorCode, "Database error")
-		}
+		utils.FailErr(c, err)
+		return
+	}
+	if ok, _ := userService.CheckUserAuthorization(c, taskData.UserData.ID); !ok {
 		return
 	}
 
 	utils.Success(c, gin.H{"data": taskData.UserData})
 }
 
-// UpdateUserPassword 更新用户密码
+// UpdateUserPassword 本人修改密码，需要提供当前密码；管理员重置他人密码使用 ForceResetPassword
 func UpdateUserPassword(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -136,23 +252,61 @@ func UpdateUserPassword(c *gin.Context) {
 		return
 	}
 
-	if ok, _ := userService.CheckUserAuthorization(c, id); !ok {
+	current, ok := requireLogin(c)
+	if !ok {
+		return
+	}
+	if current != id {
+		utils.FailErr(c, errs.New(errs.PermissionDenied, "Forbidden: use force-reset to set another user's password"))
 		return
 	}
 
 	var req struct {
-		NewPassword string `json:"newPassword"`
+		CurrentPassword string `json:"currentPassword"`
+		NewPassword     string `json:"newPassword"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.Fail(c, utils.BadRequestCode, "Invalid request payload")
 		return
 	}
 
-	userService.UpdatePassword(id, req.NewPassword)
+	// 保留当前会话，其余会话全部撤销
+	err = userService.UpdatePassword(c.Request.Context(), id, req.CurrentPassword, req.NewPassword, session.CurrentID(c))
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
 
 	utils.Success(c, gin.H{"message": "Password updated"})
 }
 
+// ForceResetPassword 管理员为用户设置新密码，并撤销该用户的全部会话
+func ForceResetPassword(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid user ID")
+		return
+	}
+
+	if ok, _ := userService.CheckUserAuthorization(c, -1); !ok {
+		return
+	}
+
+	var req struct {
+		NewPassword string `json:"newPassword"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid request payload")
+		return
+	}
+	if err := userService.ForceResetPassword(c.Request.Context(), id, req.NewPassword); err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "Password has been reset"})
+}
+
 // ListUsers 获取用户列表
 func ListUsers(c *gin.Context) {
 	if ok, _ := userService.CheckUserAuthorization(c, -1); !ok {
@@ -164,7 +318,7 @@ func ListUsers(c *gin.Context) {
 
 	users, err := userService.ListUsers(page, size)
 	if err != nil {
-		utils.Fail(c, utils.ServerErrorCode, "Failed to fetch users")
+		utils.FailErr(c, err)
 		return
 	}
 
@@ -175,7 +329,7 @@ func ListUsers(c *gin.Context) {
 	})
 }
 
-// DeleteUser 删除用户
+// DeleteUser 删除用户（管理员）
 func DeleteUser(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -183,33 +337,384 @@ func DeleteUser(c *gin.Context) {
 		return
 	}
 
-	if ok, _ := userService.CheckUserAuthorization(c, id); !ok {
+	// 仅管理员，鉴权在 service 中与 gRPC 共用
+	if err := userService.DeleteUser(service.CallerContext(c), id); err != nil {
+		utils.FailErr(c, err)
 		return
 	}
 
-	userService.DeleteUser(id)
-
 	utils.Success(c, gin.H{"message": "User deletion request accepted"})
 }
 
+// updateUserRequest 修改资料请求，只包含允许更新的字段，与 gRPC UpdateUserRequest 一致
+type updateUserRequest struct {
+	ID        int64  `json:"id"`
+	Username  string `json:"username"`
+	AvatarUrl string `json:"avatarUrl"`
+	Gender    int8   `json:"gender"`
+	Phone     string `json:"phone"`
+	Email     string `json:"email"`
+}
+
 // UpdateUser 更新用户信息
 func UpdateUser(c *gin.Context) {
+	var req updateUserRequest
+	c.Request.Header.Set("Content-Type", "application/json")
+	if err := c.ShouldBind(&req); err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid request payload")
+		return
+	}
+
 	taskData := pool.TaskDataPool.Get().(*pool.TaskData)
 	defer pool.TaskDataPool.Put(taskData)
 	taskData.Reset()
+	taskData.UserData.ID = req.ID
+	taskData.UserData.Username = req.Username
+	taskData.UserData.AvatarUrl = req.AvatarUrl
+	taskData.UserData.Gender = req.Gender
+	taskData.UserData.Phone = req.Phone
+	taskData.UserData.Email = req.Email
 
-	c.Request.Header.Set("Content-Type", "application/json")
-	if err := c.ShouldBind(&taskData.UserData); err != nil {
+	if ok, _ := userService.CheckUserAuthorization(c, taskData.UserData.ID); !ok {
+		return
+	}
+
+	if err := userService.UpdateUser(c.Request.Context(), &taskData.UserData); err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "User update request accepted"})
+}
+
+// Logout 退出登录，删除当前会话；请求体带 refreshToken 时同时撤销该令牌族
+func Logout(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+	// 请求体可以为空
+	_ = c.ShouldBindJSON(&req)
+	if err := userService.RevokeRefreshToken(c.Request.Context(), req.RefreshToken); err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	if err := session.Logout(c); err != nil {
+		utils.FailErr(c, errs.Wrap(errs.Unavailable, "failed to delete session", err))
+		return
+	}
+	utils.Success(c, gin.H{"message": "Logout successful"})
+}
+
+// ListMySessions 列出当前用户的活跃会话
+func ListMySessions(c *gin.Context) {
+	userID, ok := requireLogin(c)
+	if !ok {
+		return
+	}
+	sessions, err := userService.ListSessions(c.Request.Context(), userID, session.CurrentID(c))
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"data": sessions})
+}
+
+// RevokeMySession 撤销当前用户的某个会话（例如在其他设备上登出）
+func RevokeMySession(c *gin.Context) {
+	userID, ok := requireLogin(c)
+	if !ok {
+		return
+	}
+	if err := userService.RevokeSession(c.Request.Context(), userID, c.Param("id")); err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"message": "Session revoked"})
+}
+
+// RevokeUserSessions 撤销指定用户的全部会话（管理员）
+func RevokeUserSessions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid user ID")
+		return
+	}
+
+	n, err := userService.RevokeUserSessions(service.CallerContext(c), id)
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"message": "Sessions revoked", "revoked": n})
+}
+
+// SuspendUser 停用用户（管理员），同时撤销其全部会话
+func SuspendUser(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid user ID")
+		return
+	}
+
+	if err := userService.SuspendUser(service.CallerContext(c), id); err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "User suspension request accepted"})
+}
+
+// UnlockUser 解除账号的登录锁定（管理员）
+func UnlockUser(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid user ID")
+		return
+	}
+
+	if ok, _ := userService.CheckUserAuthorization(c, -1); !ok {
+		return
+	}
+
+	locked, err := userService.UnlockUser(c.Request.Context(), id)
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"message": "User unlocked", "wasLocked": locked})
+}
+
+// currentUserID 当前登录用户的 ID，访问令牌优先，不创建会话
+func currentUserID(c *gin.Context) (int64, bool) {
+	if id := token.FromContext(c.Request.Context()); id != nil {
+		return id.UserID, true
+	}
+	s := session.Lookup(c)
+	if s == nil {
+		return 0, false
+	}
+	id, ok := s.Values[session.KeyUserID].(int64)
+	return id, ok
+}
+
+// RefreshToken 用刷新令牌换发新的令牌对
+func RefreshToken(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" {
 		utils.Fail(c, utils.BadRequestCode, "Invalid request payload")
 		return
 	}
+	tokens, err := userService.RefreshTokens(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, tokens)
+}
+
+// requireLogin 获取当前登录用户，未登录时写回 401
+func requireLogin(c *gin.Context) (int64, bool) {
+	id, ok := currentUserID(c)
+	if !ok {
+		utils.FailErr(c, errs.New(errs.Unauthenticated, "Unauthorized"))
+	}
+	return id, ok
+}
 
-	if ok, _ := userService.CheckUserAuthorization(c, taskData.UserData.ID); !ok {
-		utils.Fail(c, utils.UnauthorizedCode, "Invalid user ID")
+// CreateAPIKey 为用户创建 API Key，明文密钥只在本次响应中返回
+func CreateAPIKey(c *gin.Context) {
+	id, ok := apiKeyOwner(c)
+	if !ok {
 		return
 	}
 
-	userService.UpdateUser(&taskData.UserData)
+	var req service.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid request payload")
+		return
+	}
 
-	utils.Success(c, gin.H{"message": "User update request accepted"})
+	_, callerRole, _ := service.CurrentIdentity(c)
+	raw, key, err := userService.CreateAPIKey(c.Request.Context(), id, callerRole, req)
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"key": raw, "data": key})
+}
+
+// ListAPIKeys 列出用户的 API Key
+func ListAPIKeys(c *gin.Context) {
+	id, ok := apiKeyOwner(c)
+	if !ok {
+		return
+	}
+	keys, err := userService.ListAPIKeys(id)
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"data": keys})
+}
+
+// RevokeAPIKey 撤销用户的某个 API Key
+func RevokeAPIKey(c *gin.Context) {
+	id, ok := apiKeyOwner(c)
+	if !ok {
+		return
+	}
+	keyID, err := strconv.ParseInt(c.Param("keyId"), 10, 64)
+	if err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid API key ID")
+		return
+	}
+	if err := userService.RevokeAPIKey(c.Request.Context(), id, keyID); err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"message": "API key revoked"})
+}
+
+// apiKeyOwner 解析路径中的用户 ID 并检查权限（本人或管理员）
+func apiKeyOwner(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid user ID")
+		return 0, false
+	}
+	if ok, _ := userService.CheckUserAuthorization(c, id); !ok {
+		return 0, false
+	}
+	return id, true
+}
+
+// EnrollMfa 登记两步验证（仅本人），返回密钥与 otpauth:// 地址
+func EnrollMfa(c *gin.Context) {
+	id, ok := mfaOwner(c)
+	if !ok {
+		return
+	}
+	enrollment, err := userService.EnrollMFA(id)
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, enrollment)
+}
+
+// ConfirmMfa 用验证码确认登记并启用两步验证，恢复码只在本次响应中返回
+func ConfirmMfa(c *gin.Context) {
+	id, ok := mfaOwner(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid request payload")
+		return
+	}
+	codes, err := userService.ConfirmMFA(c.Request.Context(), id, req.Code)
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"message": "Two-factor authentication enabled", "recoveryCodes": codes})
+}
+
+// DisableMfa 关闭两步验证：本人需提供验证码或恢复码，管理员可直接重置其他用户
+func DisableMfa(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid user ID")
+		return
+	}
+	if ok, _ := userService.CheckUserAuthorization(c, id); !ok {
+		return
+	}
+	var req struct {
+		Code string `json:"code"`
+	}
+	_ = c.ShouldBindJSON(&req)
+
+	current, _ := currentUserID(c)
+	if err := userService.DisableMFA(c.Request.Context(), id, req.Code, current != id); err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// mfaOwner 两步验证的登记只能由本人操作
+func mfaOwner(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid user ID")
+		return 0, false
+	}
+	current, ok := requireLogin(c)
+	if !ok {
+		return 0, false
+	}
+	if current != id {
+		utils.FailErr(c, errs.New(errs.PermissionDenied, "Forbidden"))
+		return 0, false
+	}
+	return id, true
+}
+
+// ListAuditEvents 查询审计事件（管理员）
+func ListAuditEvents(c *gin.Context) {
+	if ok, _ := userService.CheckUserAuthorization(c, -1); !ok {
+		return
+	}
+
+	var v validator.Collector
+	queryInt := func(name, def string) int64 {
+		n, err := strconv.ParseInt(c.DefaultQuery(name, def), 10, 64)
+		if err != nil {
+			v.Add(name, "must be an integer")
+		}
+		return n
+	}
+	queryTime := func(name string) time.Time {
+		raw := c.Query(name)
+		if raw == "" {
+			return time.Time{}
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			v.Add(name, "must be an RFC 3339 time")
+		}
+		return t
+	}
+	filter := model.AuditFilter{
+		ActorID:   queryInt("actorId", "0"),
+		TargetID:  queryInt("targetId", "0"),
+		Action:    c.Query("action"),
+		Transport: c.Query("transport"),
+		RequestID: c.Query("requestId"),
+		From:      queryTime("from"),
+		To:        queryTime("to"),
+	}
+	page, size := int(queryInt("page", "1")), int(queryInt("size", "20"))
+	if err := v.Err(); err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+
+	events, total, err := userService.ListAuditEvents(filter, page, size)
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  events,
+		"page":  page,
+		"size":  size,
+		"total": total,
+	})
 }
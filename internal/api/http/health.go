@@ -0,0 +1,52 @@
+package http
+
+import (
+	"context"
+	"expvar"
+	"github.com/gin-gonic/gin"
+	"http_grpc/pkg/health"
+	"net/http"
+	"time"
+)
+
+// readinessTimeout 就绪探针中依赖检查的超时时间
+const readinessTimeout = 2 * time.Second
+
+// SetupHealthRoutes 注册存活、就绪探针与运行指标，不受 legacy_handlers 开关影响
+func SetupHealthRoutes(router *gin.Engine) {
+	router.GET("/healthz", Healthz)
+	router.GET("/readyz", Readyz)
+	router.GET("/debug/vars", DebugVars)
+}
+
+// DebugVars expvar 运行指标，包含内存统计与启动参数，只允许管理员访问
+func DebugVars(c *gin.Context) {
+	if ok, _ := userService.CheckUserAuthorization(c, -1); !ok {
+		return
+	}
+	expvar.Handler().ServeHTTP(c.Writer, c.Request)
+}
+
+// Healthz 存活探针：进程能处理请求即返回 200
+func Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusUp})
+}
+
+// Readyz 就绪探针：检查 MySQL、Redis、协程池与 Session 恢复状态，排空期间返回 503
+func Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	results, healthy := health.DefaultRegistry.Check(ctx)
+	draining := health.Draining()
+
+	code, status := http.StatusOK, health.StatusUp
+	if !healthy || draining {
+		code, status = http.StatusServiceUnavailable, health.StatusDown
+	}
+	c.JSON(code, gin.H{
+		"status":   status,
+		"draining": draining,
+		"checks":   results,
+	})
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"http_grpc/internal/repository/userimport"
+	"http_grpc/internal/service"
+	"http_grpc/pkg/errs"
+	"http_grpc/pkg/utils"
+	"http_grpc/pkg/validator"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ImportUsers 批量导入用户（管理员）。请求体为 CSV 或 NDJSON 文件，也可以用 multipart/form-data 的 file 字段上传；
+// 不超过同步上限的文件直接返回逐行结果，更大的文件或 async=true 时返回 202 与后台任务
+func ImportUsers(c *gin.Context) {
+	if ok, _ := userService.CheckUserAuthorization(c, -1); !ok {
+		return
+	}
+
+	var v validator.Collector
+	queryBool := func(name string) bool {
+		raw := c.Query(name)
+		if raw == "" {
+			return false
+		}
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			v.Add(name, "must be a boolean")
+		}
+		return b
+	}
+	opts := service.ImportOptions{
+		DryRun:      queryBool("dryRun"),
+		OnDuplicate: c.Query("onDuplicate"),
+	}
+	async := queryBool("async")
+
+	body, contentType, filename := io.Reader(c.Request.Body), c.ContentType(), ""
+	if strings.HasPrefix(contentType, "multipart/") {
+		file, header, err := c.Request.FormFile("file")
+		if err != nil {
+			utils.Fail(c, utils.BadRequestCode, "multipart upload must include a file field")
+			return
+		}
+		defer file.Close()
+		body, contentType, filename = file, header.Header.Get("Content-Type"), header.Filename
+	}
+	format := userimport.DetectFormat(c.Query("format"), contentType, filename)
+	if format == "" {
+		v.Add("format", "must be csv or ndjson (set the format parameter or Content-Type)")
+	}
+	if err := v.Err(); err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+
+	path, size, err := userimport.Spool(body)
+	if errors.Is(err, userimport.ErrTooLarge) {
+		utils.Fail(c, http.StatusRequestEntityTooLarge, "import file is too large")
+		return
+	}
+	if err != nil {
+		utils.FailErr(c, errs.Wrap(errs.Internal, "failed to store import file", err))
+		return
+	}
+
+	if async || size > userimport.SyncMaxBytes() {
+		job, err := userService.StartImportJob(c.Request.Context(), opts, format, path)
+		if err != nil {
+			utils.FailErr(c, err)
+			return
+		}
+		c.Header("Location", "/users/import/jobs/"+job.ID)
+		c.JSON(http.StatusAccepted, utils.Response{Code: utils.SuccessCode, Msg: "Accepted", Data: gin.H{"data": job}})
+		return
+	}
+
+	defer os.Remove(path)
+	f, err := os.Open(path)
+	if err != nil {
+		utils.FailErr(c, errs.Wrap(errs.Internal, "failed to open import file", err))
+		return
+	}
+	defer f.Close()
+	reader, err := userimport.NewReader(format, f)
+	if err != nil {
+		utils.FailErr(c, errs.New(errs.InvalidArgument, err.Error()))
+		return
+	}
+	report, err := userService.ImportUsers(c.Request.Context(), opts, reader.Next)
+	if report == nil {
+		utils.FailErr(c, err)
+		return
+	}
+	// 文件在读到任何数据前就无法解析时按请求错误处理；否则已处理的行仍返回报告，原因见 error 字段
+	if err != nil && report.Total == 0 {
+		utils.FailErr(c, errs.New(errs.InvalidArgument, report.Error))
+		return
+	}
+	utils.Success(c, gin.H{"data": report})
+}
+
+// GetImportJob 查询后台导入任务（管理员），结束后附带逐行结果
+func GetImportJob(c *gin.Context) {
+	if ok, _ := userService.CheckUserAuthorization(c, -1); !ok {
+		return
+	}
+	job, err := userService.GetImportJob(c.Param("id"))
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"data": job})
+}
@@ -0,0 +1,204 @@
+package http
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/service"
+	"http_grpc/pkg/openapi"
+	"http_grpc/pkg/utils"
+	userpb "http_grpc/proto/user"
+	"sort"
+	"strings"
+)
+
+// undocumentedPrefixes 不需要出现在文档中的路由（文档自身与网关转发入口）
+var undocumentedPrefixes = []string{"/openapi.json", "/docs", gatewayPrefix + "/*"}
+
+// routeDocs 手写路由的 OpenAPI 描述，键为 "METHOD gin路径"
+// 在 SetupRoutes 中新增路由时必须在此登记，否则 BuildSpec 会报错
+func routeDocs(doc *openapi.Document) map[string]*openapi.Operation {
+	doc.Register("User", model.User{})
+	idParam := openapi.Parameter{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "integer", Format: "int64"}}
+	query := func(name, typ string, required bool) openapi.Parameter {
+		return openapi.Parameter{Name: name, In: "query", Required: required, Schema: &openapi.Schema{Type: typ}}
+	}
+
+	return map[string]*openapi.Operation{
+		"POST /users/register": legacyOp("CreateUser", "创建用户，新用户均为普通用户", doc.Register("CreateUserRequest", createUserRequest{})),
+		"POST /users/update":   legacyOp("UpdateUser", "更新用户资料（本人或管理员），ID 取自请求体", doc.Register("UpdateUserRequest", updateUserRequest{})),
+		"POST /users/login": legacyOp("Login", "用户登录，成功后写入 session_id Cookie；连续失败后账号被临时锁定，返回 429 与 Retry-After",
+			doc.Register("LoginRequest", struct {
+				Account     string `json:"userAccount"`
+				Password    string `json:"userPassword"`
+				RememberMe  bool   `json:"rememberMe"`
+				IssueTokens bool   `json:"issueTokens"`
+			}{})),
+		"POST /users/login/mfa": legacyOp("VerifyMfa", "两步验证登录的第二步：提交登录返回的 challengeToken 与验证码（或恢复码）",
+			doc.Register("VerifyMfaRequest", struct {
+				ChallengeToken string `json:"challengeToken"`
+				Code           string `json:"code"`
+				RememberMe     bool   `json:"rememberMe"`
+				IssueTokens    bool   `json:"issueTokens"`
+			}{})),
+		"POST /users/password/forgot": legacyOp("ForgotPassword", "发送重置密码邮件，邮箱是否存在都返回相同结果",
+			doc.Register("ForgotPasswordRequest", struct {
+				Email string `json:"email"`
+			}{})),
+		"POST /users/password/reset": legacyOp("ResetPassword", "使用邮件中的一次性令牌设置新密码，成功后撤销该用户的全部会话",
+			doc.Register("ResetPasswordRequest", struct {
+				Token       string `json:"token"`
+				NewPassword string `json:"newPassword"`
+			}{})),
+		"POST /users/email/verify": legacyOp("VerifyEmail", "使用邮件中的一次性令牌验证邮箱",
+			doc.Register("VerifyEmailRequest", struct {
+				Token string `json:"token"`
+			}{})),
+		"POST /users/:id/mfa/enroll": httpOp("EnrollMfa", "登记 TOTP 两步验证（本人），返回密钥与 otpauth:// 地址", nil, idParam),
+		"POST /users/:id/mfa/confirm": httpOp("ConfirmMfa", "用验证码确认并启用两步验证，返回一次性恢复码", doc.Register("MfaCodeRequest", struct {
+			Code string `json:"code"`
+		}{}), idParam),
+		"POST /users/:id/mfa/disable": httpOp("DisableMfa", "关闭两步验证：本人需提供验证码或恢复码，管理员可直接重置", openapi.Ref("MfaCodeRequest"), idParam),
+		"GET /users/:id":              legacyOp("GetUserByID", "根据ID获取用户", nil, idParam),
+		"GET /users/by-account":       legacyOp("GetUserByAccount", "根据账号获取用户（本人或管理员）", nil, query("userAccount", "string", true)),
+		"PUT /users/:id/password": legacyOp("UpdateUserPassword", "本人修改密码：校验当前密码与密码策略，成功后撤销其他会话",
+			doc.Register("UpdatePasswordRequest", struct {
+				CurrentPassword string `json:"currentPassword"`
+				NewPassword     string `json:"newPassword"`
+			}{}), idParam),
+		"POST /users/:id/password/force-reset": legacyOp("ForceResetPassword", "管理员为用户设置新密码，撤销其全部会话并解除登录锁定",
+			doc.Register("ForceResetPasswordRequest", struct {
+				NewPassword string `json:"newPassword"`
+			}{}), idParam),
+		"GET /users/list": legacyOp("ListUsers", "获取用户列表（管理员）", nil, query("page", "integer", false), query("size", "integer", false)),
+		"GET /users/export": httpOp("ExportUsers", "导出未删除的用户（管理员），逐行写出 CSV（默认）或 NDJSON，不分页；columns 为逗号分隔的列名，默认导出全部列，不含密码摘要；"+
+			"客户端支持时以 Content-Encoding: gzip 传输，gzip=true 时下载 .gz 文件", nil,
+			query("format", "string", false), query("columns", "string", false), query("gzip", "boolean", false)),
+		"POST /users/import": httpOp("ImportUsers", "批量导入用户（管理员）：请求体为 CSV（首行表头）或 NDJSON，或以 multipart/form-data 的 file 字段上传；"+
+			"onDuplicate 为 skip | update | fail（默认），dryRun=true 只校验；小文件直接返回逐行结果，大文件或 async=true 时返回 202 与后台任务", nil,
+			query("format", "string", false), query("onDuplicate", "string", false), query("dryRun", "boolean", false), query("async", "boolean", false)),
+		"GET /users/import/jobs/:id": legacyOp("GetImportJob", "查询后台导入任务（管理员），结束后附带逐行结果", nil,
+			openapi.Parameter{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}}),
+		"GET /users/events": httpOp("WatchUsers", "以 Server-Sent Events 推送用户的创建、修改与删除（管理员）；types、userIds 为逗号分隔的过滤条件，重连时通过 Last-Event-ID 头或 lastEventId 参数恢复", nil,
+			query("types", "string", false), query("userIds", "string", false), query("lastEventId", "string", false)),
+		"DELETE /users/:id":      legacyOp("DeleteUser", "删除用户（管理员）", nil, idParam),
+		"POST /users/logout":     httpOp("Logout", "退出登录，删除当前会话并清除 Cookie", nil),
+		"GET /users/me/sessions": legacyOp("ListMySessions", "当前用户的活跃会话（设备、IP、登录与最后访问时间）", nil),
+		"DELETE /users/me/sessions/:id": legacyOp("RevokeMySession", "撤销当前用户的某个会话，id 取自会话列表", nil,
+			openapi.Parameter{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}}),
+		"DELETE /users/:id/sessions": legacyOp("RevokeUserSessions", "撤销用户的全部会话（管理员）", nil, idParam),
+		"POST /users/:id/suspend":    legacyOp("SuspendUser", "停用用户并撤销其全部会话（管理员）", nil, idParam),
+		"POST /users/:id/unlock":     legacyOp("UnlockUser", "解除连续登录失败导致的账号锁定（管理员）", nil, idParam),
+		"POST /users/:id/api-keys": legacyOp("CreateAPIKey", "创建 API Key（本人或管理员），响应中的 key 只返回这一次；scopes 可选 users:read、users:write、sessions:admin、audit:read、webhooks:admin",
+			doc.Register("CreateAPIKeyRequest", service.CreateAPIKeyRequest{}), idParam),
+		"GET /users/:id/api-keys": legacyOp("ListAPIKeys", "列出用户的 API Key（不含密钥），含最后使用时间", nil, idParam),
+		"DELETE /users/:id/api-keys/:keyId": legacyOp("RevokeAPIKey", "撤销 API Key", nil, idParam,
+			openapi.Parameter{Name: "keyId", In: "path", Required: true, Schema: &openapi.Schema{Type: "integer", Format: "int64"}}),
+		"GET /audit/events": legacyOp("ListAuditEvents", "查询审计事件（管理员），新的在前；action 以 . 结尾时按前缀匹配，from/to 为 RFC 3339 时间", nil,
+			query("actorId", "integer", false), query("targetId", "integer", false), query("action", "string", false),
+			query("transport", "string", false), query("requestId", "string", false),
+			query("from", "string", false), query("to", "string", false),
+			query("page", "integer", false), query("size", "integer", false)),
+		"POST /webhooks": legacyOp("CreateWebhook", "创建 Webhook（管理员），响应中的 secret 只返回这一次；events 为空表示订阅全部事件",
+			doc.Register("WebhookRequest", service.WebhookRequest{})),
+		"GET /webhooks":     legacyOp("ListWebhooks", "列出全部 Webhook（管理员）", nil),
+		"GET /webhooks/:id": legacyOp("GetWebhook", "查询 Webhook（管理员）", nil, idParam),
+		"PUT /webhooks/:id": legacyOp("UpdateWebhook", "更新 Webhook（管理员），未提供的字段保持不变；rotateSecret 为 true 时返回新的 secret",
+			openapi.Ref("WebhookRequest"), idParam),
+		"DELETE /webhooks/:id":    legacyOp("DeleteWebhook", "删除 Webhook（管理员）", nil, idParam),
+		"POST /webhooks/:id/test": legacyOp("TestWebhook", "向 Webhook 发送 webhook.test 事件（管理员），结果见投递日志", nil, idParam),
+		"GET /webhooks/:id/deliveries": legacyOp("ListWebhookDeliveries", "Webhook 的投递日志（管理员），每次发送一条，新的在前", nil,
+			idParam, query("page", "integer", false), query("size", "integer", false)),
+		"POST /webhooks/:id/deliveries/:deliveryId/redeliver": legacyOp("RedeliverWebhook", "以新的投递 ID 重新发送某次投递（管理员）", nil, idParam,
+			openapi.Parameter{Name: "deliveryId", In: "path", Required: true, Schema: &openapi.Schema{Type: "integer", Format: "int64"}}),
+		"POST /auth/refresh": legacyOp("RefreshToken", "用刷新令牌换发访问令牌与新的刷新令牌，旧令牌重复使用时撤销整个令牌族",
+			doc.Register("RefreshTokenRequest", struct {
+				RefreshToken string `json:"refreshToken"`
+			}{})),
+		"GET /.well-known/jwks.json": {
+			Tags:        []string{"auth"},
+			OperationID: "JWKS",
+			Summary:     "访问令牌的校验公钥（JWK Set）",
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "OK", Content: openapi.JSONContent(&openapi.Schema{Type: "object"})},
+			},
+		},
+		"GET /healthz":    probeOp("Healthz", "存活探针"),
+		"GET /readyz":     probeOp("Readyz", "就绪探针：各依赖检查结果，排空期间返回 503"),
+		"GET /debug/vars": probeOp("DebugVars", "expvar 运行指标，含会话存储容量与淘汰计数（管理员）"),
+	}
+}
+
+func probeOp(id, summary string) *openapi.Operation {
+	return &openapi.Operation{
+		Tags:        []string{"health"},
+		OperationID: id,
+		Summary:     summary,
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "OK", Content: openapi.JSONContent(&openapi.Schema{Type: "object"})},
+			"503": {Description: "Service Unavailable", Content: openapi.JSONContent(&openapi.Schema{Type: "object"})},
+		},
+	}
+}
+
+// httpOp 只由手写路由提供的接口（网关无法表达的流式、上传与 Cookie 接口），不受 legacy_handlers 开关影响
+func httpOp(id, summary string, body *openapi.Schema, params ...openapi.Parameter) *openapi.Operation {
+	op := legacyOp(id, summary, body, params...)
+	op.Tags = []string{"http"}
+	op.OperationID = "http_" + id
+	return op
+}
+
+func legacyOp(id, summary string, body *openapi.Schema, params ...openapi.Parameter) *openapi.Operation {
+	op := &openapi.Operation{
+		Tags:        []string{"legacy"},
+		OperationID: "legacy_" + id,
+		Summary:     summary,
+		Parameters:  params,
+		Responses: map[string]*openapi.Response{
+			"200":     {Description: "OK", Content: openapi.JSONContent(openapi.Ref("Response"))},
+			"default": {Description: "Error", Content: map[string]*openapi.MediaType{"application/problem+json": {Schema: openapi.Ref("Problem")}}},
+		},
+	}
+	if body != nil {
+		op.RequestBody = &openapi.RequestBody{Required: true, Content: openapi.JSONContent(body)}
+	}
+	return op
+}
+
+// BuildSpec 生成 OpenAPI 文档：网关路由来自 user.proto，手写路由来自 routeDocs
+// 已注册但没有文档的路由会返回错误
+func BuildSpec(routes gin.RoutesInfo) (*openapi.Document, error) {
+	doc := openapi.New("UserCenter API", "1.0.0")
+	doc.Register("Response", utils.Response{})
+	problem := doc.Register("Problem", utils.Problem{})
+	doc.AddProtoFile(userpb.File_proto_user_user_proto, problem)
+
+	docs := routeDocs(doc)
+	var missing []string
+	for _, r := range routes {
+		if isUndocumented(r.Path) {
+			continue
+		}
+		op, ok := docs[r.Method+" "+r.Path]
+		if !ok {
+			missing = append(missing, r.Method+" "+r.Path)
+			continue
+		}
+		doc.AddOperation(r.Method, openapi.GinPath(r.Path), op)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("routes without OpenAPI entry: %s", strings.Join(missing, ", "))
+	}
+	return doc, nil
+}
+
+func isUndocumented(path string) bool {
+	for _, prefix := range undocumentedPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBuildSpecCoversAllRoutes(t *testing.T) {
+	router := gin.New()
+	SetupHTTPRoutes(router)
+	SetupRoutes(router)
+
+	doc, err := BuildSpec(router.Routes())
+	if err != nil {
+		t.Fatalf("BuildSpec: %v", err)
+	}
+	if doc == nil {
+		t.Fatal("BuildSpec returned nil document")
+	}
+}
+
+func TestBuildSpecRejectsUndocumentedRoute(t *testing.T) {
+	router := gin.New()
+	SetupHTTPRoutes(router)
+	SetupRoutes(router)
+	router.GET("/users/undocumented", func(c *gin.Context) {})
+
+	_, err := BuildSpec(router.Routes())
+	if err == nil {
+		t.Fatal("BuildSpec accepted a route without OpenAPI entry")
+	}
+	if !strings.Contains(err.Error(), "GET /users/undocumented") {
+		t.Errorf("error does not name the missing route: %v", err)
+	}
+}
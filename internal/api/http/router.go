@@ -4,7 +4,23 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// SetupRoutes 初始化所有路由
+// SetupHTTPRoutes 注册 /v1 网关无法提供的接口，不受 legacy_handlers 开关影响：
+// 流式导出、订阅与上传，删除会话 Cookie 的退出登录，以及 proto 中尚未定义的两步验证登记
+func SetupHTTPRoutes(router *gin.Engine) {
+	userRoutes := router.Group("/users")
+	{
+		userRoutes.GET("/export", ExportUsers)
+		userRoutes.GET("/events", WatchUsers)
+		userRoutes.POST("/import", ImportUsers)
+		userRoutes.POST("/logout", Logout)
+		userRoutes.POST("/:id/mfa/enroll", EnrollMfa)
+		userRoutes.POST("/:id/mfa/confirm", ConfirmMfa)
+		userRoutes.POST("/:id/mfa/disable", DisableMfa)
+	}
+}
+
+// SetupRoutes 注册旧版手写路由，功能与 /v1 网关重复，仅为兼容旧客户端保留（legacy_handlers）。
+// 新接口在 user.proto 中定义并通过 /v1 网关提供，不再添加到这里
 func SetupRoutes(router *gin.Engine) {
 
 	// 用户相关路由
@@ -13,11 +29,44 @@ func SetupRoutes(router *gin.Engine) {
 		userRoutes.POST("/register", CreateUser)
 		userRoutes.POST("/update", UpdateUser)
 		userRoutes.POST("/login", Login)
+		userRoutes.POST("/login/mfa", VerifyMfa)
+		userRoutes.POST("/password/forgot", ForgotPassword)
+		userRoutes.POST("/password/reset", ResetPassword)
+		userRoutes.POST("/email/verify", VerifyEmail)
 		userRoutes.GET("/:id", GetUserByID)
 		userRoutes.GET("/by-account", GetUserByAccount)
 		userRoutes.PUT("/:id/password", UpdateUserPassword)
+		userRoutes.POST("/:id/password/force-reset", ForceResetPassword)
 		userRoutes.GET("/list", ListUsers)
+		userRoutes.GET("/import/jobs/:id", GetImportJob)
 		userRoutes.DELETE("/:id", DeleteUser)
+		userRoutes.GET("/me/sessions", ListMySessions)
+		userRoutes.DELETE("/me/sessions/:id", RevokeMySession)
+		userRoutes.DELETE("/:id/sessions", RevokeUserSessions)
+		userRoutes.POST("/:id/suspend", SuspendUser)
+		userRoutes.POST("/:id/unlock", UnlockUser)
+		userRoutes.POST("/:id/api-keys", CreateAPIKey)
+		userRoutes.GET("/:id/api-keys", ListAPIKeys)
+		userRoutes.DELETE("/:id/api-keys/:keyId", RevokeAPIKey)
+	}
+
+	// 令牌相关路由
+	router.POST("/auth/refresh", RefreshToken)
+
+	// 审计相关路由
+	router.GET("/audit/events", ListAuditEvents)
+
+	// Webhook 相关路由（管理员）
+	webhookRoutes := router.Group("/webhooks")
+	{
+		webhookRoutes.POST("", CreateWebhook)
+		webhookRoutes.GET("", ListWebhooks)
+		webhookRoutes.GET("/:id", GetWebhook)
+		webhookRoutes.PUT("/:id", UpdateWebhook)
+		webhookRoutes.DELETE("/:id", DeleteWebhook)
+		webhookRoutes.POST("/:id/test", TestWebhook)
+		webhookRoutes.GET("/:id/deliveries", ListWebhookDeliveries)
+		webhookRoutes.POST("/:id/deliveries/:deliveryId/redeliver", RedeliverWebhook)
 	}
 
 }
@@ -1,25 +1,61 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"http_grpc/pkg/config"
 	"http_grpc/pkg/pool"
+	"http_grpc/pkg/utils"
 	"log"
+	"net/http"
+	"time"
 )
 
-func StartHttpServer() {
+// shutdownTimeout 停机时等待进行中请求完成的最长时间
+const shutdownTimeout = 10 * time.Second
+
+// StartHttpServer 启动 HTTP 服务，ctx 结束后优雅停机
+func StartHttpServer(ctx context.Context) {
 	InitUserHandler(pool.HandlerWorkerPool)
 
 	c := config.AppConfig
 	port := c.Http.Port
+	utils.LegacyEnvelope = c.Http.LegacyEnvelope
 	// 创建 Gin 引擎，启动服务
 	router := gin.Default()
 	if err := router.SetTrustedProxies([]string{"127.0.0.1"}); err != nil {
 		log.Fatalf("设置代理失败: %v", err)
 	}
-	SetupRoutes(router)
-	if err := router.Run(fmt.Sprintf(":%d", port)); err != nil {
+	router.Use(Authenticate(), AuditContext())
+	SetupHealthRoutes(router)
+	SetupAuthRoutes(router)
+	SetupHTTPRoutes(router)
+	if c.Http.LegacyHandlers {
+		SetupRoutes(router)
+	}
+	if err := SetupGateway(ctx, router, c.Grpc.Port); err != nil {
+		log.Fatalf("网关初始化失败: %v", err)
+	}
+	// 所有路由注册完成后生成文档，缺少文档的路由会导致启动失败
+	if err := SetupDocs(router); err != nil {
+		log.Fatalf("OpenAPI 文档生成失败: %v", err)
+	}
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: router,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("HTTP 服务停机失败: %v", err)
+		}
+	}()
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("服务器启动失败: %v", err)
 	}
 }
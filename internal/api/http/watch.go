@@ -0,0 +1,106 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"http_grpc/internal/repository/watch"
+	"http_grpc/internal/service"
+	"http_grpc/pkg/utils"
+	"http_grpc/pkg/validator"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sseRetry 建议浏览器断线后重连的等待时间（毫秒）
+const sseRetry = 3000
+
+// WatchUsers 以 Server-Sent Events 推送用户的创建、修改与删除（管理员）。
+// 事件名为事件类型（如 user.created），另有 heartbeat 与 reset；
+// 重连时浏览器自动携带 Last-Event-ID，也可通过 lastEventId 参数指定
+func WatchUsers(c *gin.Context) {
+	if ok, _ := userService.CheckUserAuthorization(c, -1); !ok {
+		return
+	}
+
+	var v validator.Collector
+	req := service.WatchRequest{
+		Types:       splitQuery(c, "types"),
+		LastEventID: c.GetHeader("Last-Event-ID"),
+	}
+	if req.LastEventID == "" {
+		req.LastEventID = c.Query("lastEventId")
+	}
+	for _, raw := range splitQuery(c, "userIds") {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			v.Add("userIds", "must be comma-separated integers")
+			break
+		}
+		req.UserIDs = append(req.UserIDs, id)
+	}
+	if err := v.Err(); err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+
+	// 第一条消息发送前出错时仍可返回普通的错误响应
+	started := false
+	send := func(m watch.Message) error {
+		if !started {
+			started = true
+			h := c.Writer.Header()
+			h.Set("Content-Type", "text/event-stream")
+			h.Set("Cache-Control", "no-cache")
+			h.Set("Connection", "keep-alive")
+			h.Set("X-Accel-Buffering", "no")
+			c.Status(http.StatusOK)
+			if _, err := fmt.Fprintf(c.Writer, "retry: %d\n\n", sseRetry); err != nil {
+				return err
+			}
+		}
+		if err := writeSSE(c, m); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}
+	if err := userService.WatchUsers(c.Request.Context(), req, send); err != nil && !started {
+		utils.FailErr(c, err)
+	}
+}
+
+// writeSSE 按 SSE 格式写出一条消息，data 为单行 JSON
+func writeSSE(c *gin.Context, m watch.Message) error {
+	name := m.Kind
+	var data interface{}
+	switch m.Kind {
+	case watch.KindChange:
+		name, data = m.Event.Type, m.Event
+	case watch.KindHeartbeat:
+		data = gin.H{"time": time.Now().UnixMilli()}
+	case watch.KindReset:
+		data = gin.H{"reason": "last event id is no longer available, reload the user list"}
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", m.ID, name, b)
+	return err
+}
+
+// splitQuery 读取逗号分隔或重复出现的查询参数
+func splitQuery(c *gin.Context, name string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(name) {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"http_grpc/internal/service"
+	"http_grpc/pkg/utils"
+	"net/http"
+	"strconv"
+)
+
+// CreateWebhook 创建 Webhook（管理员），签名密钥只在本次响应中返回
+func CreateWebhook(c *gin.Context) {
+	if ok, _ := userService.CheckUserAuthorization(c, -1); !ok {
+		return
+	}
+	var req service.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid request payload")
+		return
+	}
+	secret, hook, err := userService.CreateWebhook(c.Request.Context(), req)
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"secret": secret, "data": hook})
+}
+
+// ListWebhooks 列出全部 Webhook（管理员）
+func ListWebhooks(c *gin.Context) {
+	if ok, _ := userService.CheckUserAuthorization(c, -1); !ok {
+		return
+	}
+	hooks, err := userService.ListWebhooks()
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"data": hooks})
+}
+
+// GetWebhook 查询单个 Webhook（管理员）
+func GetWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+	hook, err := userService.GetWebhook(id)
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"data": hook})
+}
+
+// UpdateWebhook 更新 Webhook（管理员），rotateSecret 为 true 时返回新的签名密钥
+func UpdateWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+	var req service.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid request payload")
+		return
+	}
+	secret, hook, err := userService.UpdateWebhook(c.Request.Context(), id, req)
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	res := gin.H{"data": hook}
+	if secret != "" {
+		res["secret"] = secret
+	}
+	utils.Success(c, res)
+}
+
+// DeleteWebhook 删除 Webhook（管理员）
+func DeleteWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+	if err := userService.DeleteWebhook(c.Request.Context(), id); err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"message": "Webhook deleted"})
+}
+
+// TestWebhook 发送测试事件（管理员），结果通过投递日志查看
+func TestWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+	d, err := userService.TestWebhook(id)
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"data": d})
+}
+
+// ListWebhookDeliveries 查询 Webhook 的投递日志（管理员）
+func ListWebhookDeliveries(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+	deliveries, total, err := userService.ListWebhookDeliveries(id, page, size)
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  deliveries,
+		"page":  page,
+		"size":  size,
+		"total": total,
+	})
+}
+
+// RedeliverWebhook 重新发送某次投递（管理员）
+func RedeliverWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+	deliveryID, err := strconv.ParseInt(c.Param("deliveryId"), 10, 64)
+	if err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid delivery ID")
+		return
+	}
+	d, err := userService.RedeliverWebhook(id, deliveryID)
+	if err != nil {
+		utils.FailErr(c, err)
+		return
+	}
+	utils.Success(c, gin.H{"data": d})
+}
+
+// webhookID 检查管理员权限并解析路径中的 Webhook ID
+func webhookID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, utils.BadRequestCode, "Invalid webhook ID")
+		return 0, false
+	}
+	if ok, _ := userService.CheckUserAuthorization(c, -1); !ok {
+		return 0, false
+	}
+	return id, true
+}
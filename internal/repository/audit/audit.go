@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// 审计动作
+const (
+	ActionLogin          = "auth.login"        // 登录成功（含两步验证完成）
+	ActionLoginFailed    = "auth.login_failed" // 密码或两步验证码错误
+	ActionUserCreate     = "user.create"
+	ActionUserUpdate     = "user.update"
+	ActionUserDelete     = "user.delete"
+	ActionUserSuspend    = "user.suspend"
+	ActionUserUnlock     = "user.unlock"
+	ActionPasswordChange = "password.change"      // 本人修改
+	ActionPasswordReset  = "password.reset"       // 通过邮件重置
+	ActionPasswordForce  = "password.force_reset" // 管理员重置
+	ActionEmailVerify    = "email.verify"
+	ActionMFAEnable      = "mfa.enable"
+	ActionMFADisable     = "mfa.disable"
+	ActionAPIKeyCreate   = "apikey.create"
+	ActionAPIKeyRevoke   = "apikey.revoke"
+	ActionSessionRevoke  = "session.revoke"
+	ActionWebhookCreate  = "webhook.create"
+	ActionWebhookUpdate  = "webhook.update"
+	ActionWebhookDelete  = "webhook.delete"
+	ActionUserExport     = "user.export" // 导出用户，记录格式、列与行数
+)
+
+// 请求来源
+const (
+	TransportHTTP   = "http"
+	TransportGRPC   = "grpc"
+	TransportSystem = "system" // 后台任务等非请求触发的操作
+)
+
+// Meta 发起操作的请求信息，由 HTTP 中间件与 gRPC 拦截器写入 context
+type Meta struct {
+	ActorID      int64
+	ActorAccount string
+	Transport    string
+	IP           string
+	UserAgent    string
+	RequestID    string
+}
+
+type metaKey struct{}
+
+// WithMeta 把请求信息写入 context
+func WithMeta(ctx context.Context, m Meta) context.Context {
+	return context.WithValue(ctx, metaKey{}, m)
+}
+
+// FromContext 读取请求信息，没有时视为系统操作
+func FromContext(ctx context.Context) Meta {
+	if m, ok := ctx.Value(metaKey{}).(Meta); ok {
+		return m
+	}
+	return Meta{Transport: TransportSystem}
+}
+
+// NewRequestID 生成请求 ID
+func NewRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
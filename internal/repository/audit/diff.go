@@ -0,0 +1,120 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Redacted 敏感字段在审计记录中的占位值，只表明字段发生了变化
+const Redacted = "[REDACTED]"
+
+// sensitive 需要脱敏的字段（JSON 字段名，小写比较）
+var sensitive = map[string]bool{
+	"userpassword": true,
+	"password":     true,
+	"hash":         true,
+	"secret":       true,
+	"token":        true,
+	"key":          true,
+}
+
+// Change 单个字段变更前后的值，新增时 Before 为空，删除时 After 为空
+type Change struct {
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// Changes 字段名到变更的映射
+type Changes map[string]Change
+
+// Diff 比较两个结构体（按 JSON 字段）并返回发生变化的字段，before 为 nil 时记录 after 的全部字段
+func Diff(before, after interface{}) Changes {
+	b, a := fields(before), fields(after)
+	changes := Changes{}
+	for name, value := range a {
+		old, ok := b[name]
+		if ok && reflect.DeepEqual(old, value) {
+			continue
+		}
+		// 新增记录只保留有值的字段
+		if !ok && isEmpty(value) {
+			continue
+		}
+		changes.Set(name, old, value)
+	}
+	for name, old := range b {
+		if _, ok := a[name]; !ok {
+			changes.Set(name, old, nil)
+		}
+	}
+	return changes
+}
+
+// Set 记录一个字段的变更，敏感字段自动脱敏
+func (c Changes) Set(name string, before, after interface{}) Changes {
+	if sensitive[strings.ToLower(name)] {
+		before, after = redact(before), redact(after)
+	}
+	c[name] = Change{Before: before, After: after}
+	return c
+}
+
+// Only 只保留指定的字段，用于部分字段更新
+func (c Changes) Only(names ...string) Changes {
+	kept := Changes{}
+	for _, name := range names {
+		if change, ok := c[name]; ok {
+			kept[name] = change
+		}
+	}
+	return kept
+}
+
+// JSON 序列化为审计记录中的 changes 列，没有变更时为空字符串
+func (c Changes) JSON() string {
+	if len(c) == 0 {
+		return ""
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// isEmpty JSON 解码后的零值，包括零时间
+func isEmpty(v interface{}) bool {
+	switch x := v.(type) {
+	case nil:
+		return true
+	case string:
+		return x == "" || x == "0001-01-01T00:00:00Z"
+	case float64:
+		return x == 0
+	case bool:
+		return !x
+	}
+	return false
+}
+
+func redact(v interface{}) interface{} {
+	if v == nil || v == "" {
+		return v
+	}
+	return Redacted
+}
+
+// fields 结构体按 JSON 展开为字段表，忽略 json:"-" 的字段
+func fields(v interface{}) map[string]interface{} {
+	m := map[string]interface{}{}
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return m
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return m
+	}
+	_ = json.Unmarshal(b, &m)
+	return m
+}
@@ -0,0 +1,121 @@
+package audit
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"http_grpc/internal/repository/model"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// retentionLockKey 多实例部署时只由一个实例执行归档
+const retentionLockKey = "audit_retention_lock"
+
+// RetentionOptions 审计事件保留与归档配置
+type RetentionOptions struct {
+	Client     *redis.Client // 用于多实例互斥，为空时不加锁
+	Retention  time.Duration // 保留时长，0 表示永久保留
+	ArchiveDir string        // 过期事件写入的目录（gzip 压缩的 NDJSON），为空时直接删除
+	Interval   time.Duration // 执行间隔
+	BatchSize  int           // 每批处理的事件数
+}
+
+// StartRetention 启动后台归档任务，ctx 结束时退出
+func StartRetention(ctx context.Context, opts RetentionOptions) {
+	if opts.Retention <= 0 {
+		return
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 24 * time.Hour
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1000
+	}
+	go func() {
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+		for {
+			if n, err := runRetention(ctx, opts); err != nil {
+				log.Printf("Audit retention failed: %v", err)
+			} else if n > 0 {
+				log.Printf("Archived %d audit events", n)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// runRetention 按批归档并删除超过保留期的事件，返回处理的事件数
+func runRetention(ctx context.Context, opts RetentionOptions) (int, error) {
+	if opts.Client != nil {
+		ok, err := opts.Client.SetNX(ctx, retentionLockKey, 1, opts.Interval/2).Result()
+		if err != nil || !ok {
+			return 0, err
+		}
+	}
+
+	cutoff := time.Now().Add(-opts.Retention)
+	total := 0
+	for ctx.Err() == nil {
+		events, err := model.ListAuditEventsBefore(cutoff, opts.BatchSize)
+		if err != nil || len(events) == 0 {
+			return total, err
+		}
+		if opts.ArchiveDir != "" {
+			if err := archive(opts.ArchiveDir, events); err != nil {
+				return total, err
+			}
+		}
+		ids := make([]int64, len(events))
+		for i := range events {
+			ids[i] = events[i].ID
+		}
+		if err := model.DeleteAuditEvents(ids); err != nil {
+			return total, err
+		}
+		total += len(events)
+	}
+	return total, ctx.Err()
+}
+
+// archive 把一批事件写入单独的文件，写入并落盘成功后才允许删除
+func archive(dir string, events []model.AuditEvent) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("audit-%d-%d.ndjson.gz", events[0].ID, events[len(events)-1].ID)
+	tmp := filepath.Join(dir, name+".tmp")
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(f)
+	enc := json.NewEncoder(zw)
+	for i := range events {
+		if err := enc.Encode(&events[i]); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, name))
+}
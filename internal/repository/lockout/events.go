@@ -0,0 +1,59 @@
+package lockout
+
+import (
+	"encoding/json"
+	"expvar"
+	"log"
+	"sync"
+	"time"
+)
+
+// 安全监控事件类型
+const (
+	EventLoginFailed       = "login_failed"       // 密码错误或账号不存在
+	EventLoginBlocked      = "login_blocked"      // 登录请求因锁定或 IP 限流被拒绝
+	EventAccountLocked     = "account_locked"     // 账号达到失败上限被锁定
+	EventAccountUnlocked   = "account_unlocked"   // 管理员解除锁定
+	EventIPThrottled       = "ip_throttled"       // IP 达到失败上限
+	EventRecoveryThrottled = "recovery_throttled" // 找回密码请求超过邮箱或 IP 的上限
+)
+
+// Event 安全监控事件
+type Event struct {
+	Type       string        `json:"type"`
+	Account    string        `json:"account,omitempty"`
+	IP         string        `json:"ip,omitempty"`
+	Failures   int           `json:"failures,omitempty"`
+	RetryAfter time.Duration `json:"retryAfter,omitempty"`
+	Time       time.Time     `json:"time"`
+}
+
+// counters 各类事件计数（/debug/vars 中的 login_security 字段）
+var counters = expvar.NewMap("login_security")
+
+var (
+	subscribersMu sync.RWMutex
+	subscribers   []func(Event)
+)
+
+// Subscribe 订阅安全事件，回调在触发事件的请求中同步执行，不应阻塞
+func Subscribe(fn func(Event)) {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+	subscribers = append(subscribers, fn)
+}
+
+// emit 计数、输出结构化日志并通知订阅者
+func emit(e Event) {
+	e.Time = time.Now()
+	counters.Add(e.Type, 1)
+	if b, err := json.Marshal(e); err == nil {
+		log.Printf("security_event %s", b)
+	}
+
+	subscribersMu.RLock()
+	defer subscribersMu.RUnlock()
+	for _, fn := range subscribers {
+		fn(e)
+	}
+}
@@ -0,0 +1,291 @@
+package lockout
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	accountFailPrefix   = "login_fail:account:"
+	ipFailPrefix        = "login_fail:ip:"
+	lockPrefix          = "login_lock:"
+	recoveryEmailPrefix = "recovery:email:"
+	recoveryIPPrefix    = "recovery:ip:"
+)
+
+// Options 登录防暴力破解配置
+type Options struct {
+	Client              *redis.Client // 失败计数与锁定状态存储，为空时不做任何限制
+	Window              time.Duration // 失败次数统计的滑动窗口
+	MaxAccountFailures  int           // 窗口内单个账号的失败上限，达到后锁定账号
+	MaxIPFailures       int           // 窗口内单个 IP 的失败上限，达到后拒绝该 IP 登录直到窗口滑过
+	LockoutDuration     time.Duration // 账号锁定时长，到期自动解锁
+	BaseDelay           time.Duration // 第一次失败后的响应延迟，之后每次失败翻倍，负数表示不延迟
+	MaxDelay            time.Duration // 响应延迟上限
+	MaxRecoveryPerEmail int           // 窗口内同一邮箱的找回密码请求上限
+	MaxRecoveryPerIP    int           // 窗口内同一 IP 的找回密码请求上限
+}
+
+var options = Options{
+	Window:              15 * time.Minute,
+	MaxAccountFailures:  5,
+	MaxIPFailures:       50,
+	LockoutDuration:     15 * time.Minute,
+	BaseDelay:           250 * time.Millisecond,
+	MaxDelay:            4 * time.Second,
+	MaxRecoveryPerEmail: 3,
+	MaxRecoveryPerIP:    20,
+}
+
+// Setup 设置防暴力破解配置，未设置的项使用默认值
+func Setup(opts Options) {
+	if opts.Window <= 0 {
+		opts.Window = options.Window
+	}
+	if opts.MaxAccountFailures <= 0 {
+		opts.MaxAccountFailures = options.MaxAccountFailures
+	}
+	if opts.MaxIPFailures <= 0 {
+		opts.MaxIPFailures = options.MaxIPFailures
+	}
+	if opts.LockoutDuration <= 0 {
+		opts.LockoutDuration = options.LockoutDuration
+	}
+	if opts.BaseDelay == 0 {
+		opts.BaseDelay = options.BaseDelay
+	}
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = options.MaxDelay
+	}
+	if opts.MaxRecoveryPerEmail <= 0 {
+		opts.MaxRecoveryPerEmail = options.MaxRecoveryPerEmail
+	}
+	if opts.MaxRecoveryPerIP <= 0 {
+		opts.MaxRecoveryPerIP = options.MaxRecoveryPerIP
+	}
+	options = opts
+}
+
+// Block 拒绝登录的原因与剩余时长
+type Block struct {
+	Reason     string // EventAccountLocked、EventIPThrottled 或 EventRecoveryThrottled
+	RetryAfter time.Duration
+}
+
+// Failure 记录一次失败后的状态
+type Failure struct {
+	Failures int           // 账号在窗口内的失败次数
+	Locked   bool          // 本次失败导致账号被锁定
+	Delay    time.Duration // 返回结果前应等待的时长
+}
+
+// Check 登录前检查账号是否被锁定、IP 是否超过失败上限，允许登录时返回 nil
+func Check(ctx context.Context, account, ip string) (*Block, error) {
+	if options.Client == nil {
+		return nil, nil
+	}
+	now := time.Now()
+	var lockTTL *redis.DurationCmd
+	var ipCount *redis.IntCmd
+	var oldest *redis.ZSliceCmd
+	_, err := options.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		lockTTL = pipe.PTTL(ctx, lockPrefix+accountKey(account))
+		if ip != "" {
+			pipe.ZRemRangeByScore(ctx, ipFailPrefix+ip, "-inf", windowStart(now))
+			ipCount = pipe.ZCard(ctx, ipFailPrefix+ip)
+			oldest = pipe.ZRangeWithScores(ctx, ipFailPrefix+ip, 0, 0)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if ttl := lockTTL.Val(); ttl > 0 {
+		block := &Block{Reason: EventAccountLocked, RetryAfter: ttl}
+		emit(Event{Type: EventLoginBlocked, Account: account, IP: ip, RetryAfter: ttl})
+		return block, nil
+	}
+	if ipCount != nil && ipCount.Val() >= int64(options.MaxIPFailures) {
+		retryAfter := options.Window
+		if first := oldest.Val(); len(first) > 0 {
+			retryAfter = time.Unix(0, int64(first[0].Score)).Add(options.Window).Sub(now)
+		}
+		block := &Block{Reason: EventIPThrottled, RetryAfter: retryAfter}
+		emit(Event{Type: EventLoginBlocked, Account: account, IP: ip, Failures: int(ipCount.Val()), RetryAfter: retryAfter})
+		return block, nil
+	}
+	return nil, nil
+}
+
+// Recovery 在与登录失败相同的滑动窗口中记录一次找回密码请求（ip 可为空），
+// 邮箱或 IP 在窗口内的请求数超过上限时返回拒绝原因与剩余时长，被拒绝的请求同样计数
+func Recovery(ctx context.Context, email, ip string) (*Block, error) {
+	if options.Client == nil {
+		return nil, nil
+	}
+	now := time.Now()
+	block, err := limit(ctx, recoveryEmailPrefix+email, options.MaxRecoveryPerEmail, now)
+	if err != nil || block != nil {
+		if block != nil {
+			emit(Event{Type: EventRecoveryThrottled, Account: email, IP: ip, RetryAfter: block.RetryAfter})
+		}
+		return block, err
+	}
+	if ip == "" {
+		return nil, nil
+	}
+	block, err = limit(ctx, recoveryIPPrefix+ip, options.MaxRecoveryPerIP, now)
+	if block != nil {
+		emit(Event{Type: EventRecoveryThrottled, IP: ip, RetryAfter: block.RetryAfter})
+	}
+	return block, err
+}
+
+// limit 在 key 的窗口中记录一次请求，超过 max 时返回窗口内最早一次请求滑出窗口的剩余时长
+func limit(ctx context.Context, key string, max int, now time.Time) (*Block, error) {
+	count, err := record(ctx, key, now)
+	if err != nil {
+		return nil, err
+	}
+	if count <= int64(max) {
+		return nil, nil
+	}
+	retryAfter := options.Window
+	first, err := options.Client.ZRangeWithScores(ctx, key, 0, 0).Result()
+	if err == nil && len(first) > 0 {
+		retryAfter = time.Unix(0, int64(first[0].Score)).Add(options.Window).Sub(now)
+	}
+	return &Block{Reason: EventRecoveryThrottled, RetryAfter: retryAfter}, nil
+}
+
+// Fail 记录一次失败登录（账号不存在时同样记录），账号达到失败上限时锁定
+func Fail(ctx context.Context, account, ip string) (*Failure, error) {
+	if options.Client == nil {
+		return &Failure{}, nil
+	}
+	now := time.Now()
+	accountCount, err := record(ctx, accountFailPrefix+accountKey(account), now)
+	if err != nil {
+		return nil, err
+	}
+	ipFailures := 0
+	if ip != "" {
+		n, err := record(ctx, ipFailPrefix+ip, now)
+		if err != nil {
+			return nil, err
+		}
+		ipFailures = int(n)
+	}
+
+	f := &Failure{Failures: int(accountCount), Delay: delay(int(accountCount))}
+	emit(Event{Type: EventLoginFailed, Account: account, IP: ip, Failures: f.Failures})
+	if ipFailures == options.MaxIPFailures {
+		emit(Event{Type: EventIPThrottled, IP: ip, Failures: ipFailures, RetryAfter: options.Window})
+	}
+	if f.Failures >= options.MaxAccountFailures {
+		_, err := options.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, lockPrefix+accountKey(account), now.Unix(), options.LockoutDuration)
+			pipe.Del(ctx, accountFailPrefix+accountKey(account))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		f.Locked = true
+		emit(Event{Type: EventAccountLocked, Account: account, IP: ip, Failures: f.Failures, RetryAfter: options.LockoutDuration})
+	}
+	return f, nil
+}
+
+// Succeed 登录成功后清除账号的失败计数，IP 计数保留到窗口滑过
+func Succeed(ctx context.Context, account string) error {
+	if options.Client == nil {
+		return nil
+	}
+	return options.Client.Del(ctx, accountFailPrefix+accountKey(account)).Err()
+}
+
+// Unlock 解除账号锁定并清除失败计数，返回账号之前是否处于锁定状态
+func Unlock(ctx context.Context, account string) (bool, error) {
+	if options.Client == nil {
+		return false, nil
+	}
+	var locked *redis.IntCmd
+	_, err := options.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		locked = pipe.Del(ctx, lockPrefix+accountKey(account))
+		pipe.Del(ctx, accountFailPrefix+accountKey(account))
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if locked.Val() > 0 {
+		emit(Event{Type: EventAccountUnlocked, Account: account})
+		return true, nil
+	}
+	return false, nil
+}
+
+// Wait 按 d 等待，ctx 结束时提前返回
+func Wait(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// seq 同一纳秒内多次失败时保证有序集合成员不重复
+var seq atomic.Uint64
+
+// record 在滑动窗口中记录一次失败，返回窗口内的失败次数
+func record(ctx context.Context, key string, now time.Time) (int64, error) {
+	var count *redis.IntCmd
+	_, err := options.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, key, "-inf", windowStart(now))
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixNano()), Member: fmt.Sprintf("%d-%d", now.UnixNano(), seq.Add(1))})
+		count = pipe.ZCard(ctx, key)
+		pipe.PExpire(ctx, key, options.Window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count.Val(), nil
+}
+
+// accountKey 计数与锁定使用的账号键：MySQL 比较账号时不区分大小写并忽略末尾空格，
+// Admin、admin 与 "ADMIN " 是同一个账号，必须共用同一组计数
+func accountKey(account string) string {
+	return strings.ToLower(strings.TrimSpace(account))
+}
+
+// windowStart 滑动窗口起点（不含），用作 ZREMRANGEBYSCORE 的上界
+func windowStart(now time.Time) string {
+	return "(" + strconv.FormatInt(now.Add(-options.Window).UnixNano(), 10)
+}
+
+// delay 第 n 次失败后的响应延迟：BaseDelay * 2^(n-1)，不超过 MaxDelay
+func delay(n int) time.Duration {
+	if n <= 0 || options.BaseDelay <= 0 {
+		return 0
+	}
+	d := options.BaseDelay
+	for i := 1; i < n && d < options.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > options.MaxDelay {
+		d = options.MaxDelay
+	}
+	return d
+}
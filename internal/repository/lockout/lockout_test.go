@@ -0,0 +1,53 @@
+package lockout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func setupRedis(t *testing.T, opts Options) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	opts.Client = client
+	opts.BaseDelay = -1
+	Setup(opts)
+	t.Cleanup(func() {
+		Setup(Options{})
+		client.Close()
+	})
+}
+
+func TestAccountVariantsShareCounter(t *testing.T) {
+	setupRedis(t, Options{MaxAccountFailures: 3, MaxIPFailures: 100})
+	ctx := context.Background()
+
+	// MySQL 认为这些账号相同，失败次数必须累计到同一个账号上
+	var f *Failure
+	for _, account := range []string{"Admin", "admin ", "ADMIN"} {
+		var err error
+		if f, err = Fail(ctx, account, "192.0.2.1"); err != nil {
+			t.Fatalf("Fail(%q): %v", account, err)
+		}
+	}
+	if !f.Locked {
+		t.Fatalf("account not locked after %d failures across case variants", f.Failures)
+	}
+	block, err := Check(ctx, " admin", "192.0.2.2")
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if block == nil || block.Reason != EventAccountLocked {
+		t.Fatalf("variant not blocked: %+v", block)
+	}
+
+	if locked, err := Unlock(ctx, "aDmIn"); err != nil || !locked {
+		t.Fatalf("Unlock = %v, %v; want true", locked, err)
+	}
+	if block, _ := Check(ctx, "Admin", "192.0.2.2"); block != nil {
+		t.Errorf("still blocked after unlock: %+v", block)
+	}
+}
@@ -0,0 +1,106 @@
+package mfa
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+// ErrChallengeInvalid 挑战令牌不存在、已过期或尝试次数用尽
+var ErrChallengeInvalid = errors.New("mfa challenge invalid or expired")
+
+const challengePrefix = "mfa_challenge:"
+
+// Options 两步验证配置
+type Options struct {
+	Client          *redis.Client // 登录挑战存储
+	Issuer          string        // 验证器应用中显示的服务名
+	ChallengeTTL    time.Duration // 密码验证通过后完成第二步的时限
+	MaxAttempts     int           // 每个挑战允许的验证码尝试次数
+	RequireForAdmin bool          // 管理员必须启用两步验证才能获得管理员权限
+}
+
+var options = Options{Issuer: "UserCenter", ChallengeTTL: 5 * time.Minute, MaxAttempts: 5}
+
+// Setup 设置两步验证配置
+func Setup(opts Options) {
+	if opts.Issuer == "" {
+		opts.Issuer = options.Issuer
+	}
+	if opts.ChallengeTTL <= 0 {
+		opts.ChallengeTTL = options.ChallengeTTL
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = options.MaxAttempts
+	}
+	options = opts
+}
+
+// RequiredForAdmin 管理员是否必须启用两步验证
+func RequiredForAdmin() bool {
+	return options.RequireForAdmin
+}
+
+// NewChallenge 密码验证通过后创建登录挑战，返回交给客户端的挑战令牌
+func NewChallenge(ctx context.Context, userID int64) (string, error) {
+	if options.Client == nil {
+		return "", errors.New("mfa: redis client not configured")
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	raw := base64.RawURLEncoding.EncodeToString(b)
+	key := challengeKey(raw)
+	_, err := options.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "userId", userID, "attempts", 0)
+		pipe.Expire(ctx, key, options.ChallengeTTL)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// Attempt 记录一次验证尝试并返回挑战所属用户，超过尝试次数的挑战立即作废
+func Attempt(ctx context.Context, raw string) (int64, error) {
+	if options.Client == nil {
+		return 0, ErrChallengeInvalid
+	}
+	key := challengeKey(raw)
+	userID, err := options.Client.HGet(ctx, key, "userId").Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, ErrChallengeInvalid
+	}
+	if err != nil {
+		return 0, err
+	}
+	attempts, err := options.Client.HIncrBy(ctx, key, "attempts", 1).Result()
+	if err != nil {
+		return 0, err
+	}
+	if attempts > int64(options.MaxAttempts) {
+		options.Client.Del(ctx, key)
+		return 0, ErrChallengeInvalid
+	}
+	id, _ := strconv.ParseInt(userID, 10, 64)
+	return id, nil
+}
+
+// Complete 验证通过后作废挑战，同一挑战不能完成两次
+func Complete(ctx context.Context, raw string) bool {
+	n, err := options.Client.Del(ctx, challengeKey(raw)).Result()
+	return err == nil && n == 1
+}
+
+func challengeKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return challengePrefix + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"http_grpc/pkg/database"
+	"time"
+)
+
+// APIKey 个人 API Key，只保存密钥的摘要，Prefix 用于查找
+type APIKey struct {
+	ID         int64      `gorm:"primaryKey;autoIncrement;comment:ID" json:"id"`
+	UserID     int64      `gorm:"column:userId;index;not null;comment:所属用户ID" json:"userId"`
+	Name       string     `gorm:"type:varchar(64);comment:名称" json:"name"`
+	Prefix     string     `gorm:"type:varchar(16);uniqueIndex;not null;comment:密钥前缀" json:"prefix"`
+	Hash       string     `gorm:"type:char(64);not null;comment:密钥摘要" json:"-"`
+	Scopes     string     `gorm:"type:varchar(256);comment:权限范围，逗号分隔" json:"scopes"`
+	MaxRole    int        `gorm:"column:maxRole;type:int;not null;default:0;comment:创建时调用方的角色，Key 的权限不超过该角色" json:"maxRole"`
+	ExpiresAt  time.Time  `gorm:"column:expiresAt;type:datetime;comment:过期时间" json:"expiresAt"`
+	LastUsedAt *time.Time `gorm:"column:lastUsedAt;type:datetime;comment:最后使用时间" json:"lastUsedAt"`
+	CreateTime time.Time  `gorm:"column:createTime;type:datetime;default:CURRENT_TIMESTAMP;comment:创建时间" json:"createTime"`
+	IsDelete   int8       `gorm:"column:isDelete;type:tinyint;default:0;comment:是否已撤销" json:"-"`
+}
+
+func (APIKey) TableName() string {
+	return "api_key"
+}
+
+// AddAPIKey 插入新的 API Key
+func AddAPIKey(db *gorm.DB, key *APIKey) error {
+	return db.Create(key).Error
+}
+
+// GetAPIKeyByPrefix 按前缀查询未撤销的 API Key，没查到时返回 gorm.ErrRecordNotFound
+func GetAPIKeyByPrefix(prefix string, key *APIKey) error {
+	return database.DB.Where("prefix = ? AND isDelete = 0", prefix).First(key).Error
+}
+
+// ListAPIKeys 列出用户未撤销的 API Key
+func ListAPIKeys(userID int64) ([]APIKey, error) {
+	var keys []APIKey
+	err := database.DB.Where("userId = ? AND isDelete = 0", userID).Order("id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// RevokeAPIKey 撤销用户的某个 API Key，返回是否存在
+func RevokeAPIKey(db *gorm.DB, userID, id int64) (bool, error) {
+	result := db.Model(&APIKey{}).
+		Where("id = ? AND userId = ? AND isDelete = 0", id, userID).
+		Update("isDelete", 1)
+	return result.RowsAffected > 0, result.Error
+}
+
+// RevokeUserAPIKeys 撤销用户的全部 API Key（停用、删除账号或撤销全部会话时），返回撤销数量
+func RevokeUserAPIKeys(db *gorm.DB, userID int64) (int64, error) {
+	result := db.Model(&APIKey{}).
+		Where("userId = ? AND isDelete = 0", userID).
+		Update("isDelete", 1)
+	return result.RowsAffected, result.Error
+}
+
+// TouchAPIKey 更新最后使用时间
+func TouchAPIKey(id int64, at time.Time) error {
+	return database.DB.Model(&APIKey{}).Where("id = ?", id).Update("lastUsedAt", at).Error
+}
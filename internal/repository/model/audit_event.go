@@ -0,0 +1,101 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"http_grpc/pkg/database"
+	"strings"
+	"time"
+)
+
+// AuditEvent 审计事件，只追加不修改，超过保留期后由归档任务转存并删除。
+// 修改数据的事件与修改本身在同一事务中写入，接收 db 参数的 model 函数可传入该事务
+type AuditEvent struct {
+	ID            int64     `gorm:"primaryKey;autoIncrement;comment:ID" json:"id"`
+	CreateTime    time.Time `gorm:"column:createTime;type:datetime(3);index;not null;comment:发生时间" json:"createTime"`
+	Action        string    `gorm:"type:varchar(64);index;not null;comment:动作" json:"action"`
+	ActorID       int64     `gorm:"column:actorId;index;comment:操作者ID，0 表示匿名" json:"actorId"`
+	ActorAccount  string    `gorm:"column:actorAccount;type:varchar(256);comment:操作者账号" json:"actorAccount,omitempty"`
+	TargetID      int64     `gorm:"column:targetId;index;comment:目标用户ID" json:"targetId"`
+	TargetAccount string    `gorm:"column:targetAccount;type:varchar(256);comment:目标账号" json:"targetAccount,omitempty"`
+	Transport     string    `gorm:"type:varchar(16);comment:http | grpc | system" json:"transport"`
+	IP            string    `gorm:"column:ip;type:varchar(64);comment:客户端IP" json:"ip,omitempty"`
+	UserAgent     string    `gorm:"column:userAgent;type:varchar(512);comment:客户端标识" json:"userAgent,omitempty"`
+	RequestID     string    `gorm:"column:requestId;type:varchar(64);index;comment:请求ID" json:"requestId,omitempty"`
+	Changes       string    `gorm:"type:text;comment:变更前后的字段（JSON），敏感字段已脱敏" json:"changes,omitempty"`
+}
+
+func (AuditEvent) TableName() string {
+	return "audit_event"
+}
+
+// AuditFilter 审计事件查询条件，零值表示不限制
+type AuditFilter struct {
+	ActorID   int64
+	TargetID  int64
+	Action    string // 精确匹配，以 . 结尾时按前缀匹配（如 "user."）
+	Transport string
+	RequestID string
+	From      time.Time
+	To        time.Time
+}
+
+// likeEscaper 转义 LIKE 中的通配符
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// AddAuditEvent 写入审计事件
+func AddAuditEvent(db *gorm.DB, event *AuditEvent) error {
+	return db.Create(event).Error
+}
+
+// ListAuditEvents 按条件分页查询审计事件，新的在前，同时返回总数
+func ListAuditEvents(filter AuditFilter, page, size int) ([]AuditEvent, int64, error) {
+	query := database.DB.Model(&AuditEvent{})
+	if filter.ActorID != 0 {
+		query = query.Where("actorId = ?", filter.ActorID)
+	}
+	if filter.TargetID != 0 {
+		query = query.Where("targetId = ?", filter.TargetID)
+	}
+	if filter.Action != "" {
+		if filter.Action[len(filter.Action)-1] == '.' {
+			query = query.Where("action LIKE ?", likeEscaper.Replace(filter.Action)+"%")
+		} else {
+			query = query.Where("action = ?", filter.Action)
+		}
+	}
+	if filter.Transport != "" {
+		query = query.Where("transport = ?", filter.Transport)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("requestId = ?", filter.RequestID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("createTime >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("createTime < ?", filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var events []AuditEvent
+	err := query.Order("id DESC").Offset((page - 1) * size).Limit(size).Find(&events).Error
+	return events, total, err
+}
+
+// ListAuditEventsBefore 按 ID 升序取出 before 之前的一批事件，供归档使用
+func ListAuditEventsBefore(before time.Time, limit int) ([]AuditEvent, error) {
+	var events []AuditEvent
+	err := database.DB.Where("createTime < ?", before).Order("id ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// DeleteAuditEvents 删除已归档的事件
+func DeleteAuditEvents(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return database.DB.Delete(&AuditEvent{}, ids).Error
+}
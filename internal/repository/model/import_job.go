@@ -0,0 +1,72 @@
+package model
+
+import (
+	"http_grpc/pkg/database"
+	"time"
+)
+
+// 导入任务状态
+const (
+	ImportJobRunning   = "running"
+	ImportJobSucceeded = "succeeded"
+	ImportJobFailed    = "failed"
+)
+
+// ImportJob 后台执行的用户导入任务，执行中按批更新计数，结束后写入逐行结果
+type ImportJob struct {
+	ID          string     `gorm:"primaryKey;type:char(32);comment:任务ID" json:"id"`
+	Status      string     `gorm:"type:varchar(16);not null;comment:running | succeeded | failed" json:"status"`
+	Format      string     `gorm:"type:varchar(16);not null;comment:文件格式" json:"format"`
+	DryRun      bool       `gorm:"column:dryRun;not null;default:false;comment:是否只校验不写入" json:"dryRun"`
+	OnDuplicate string     `gorm:"column:onDuplicate;type:varchar(16);not null;comment:账号已存在时的处理方式" json:"onDuplicate"`
+	ActorID     int64      `gorm:"column:actorId;index;comment:发起导入的管理员ID" json:"actorId"`
+	Total       int        `gorm:"not null;default:0;comment:已处理行数" json:"total"`
+	Created     int        `gorm:"not null;default:0" json:"created"`
+	Updated     int        `gorm:"not null;default:0" json:"updated"`
+	Skipped     int        `gorm:"not null;default:0" json:"skipped"`
+	Failed      int        `gorm:"not null;default:0" json:"failed"`
+	Report      string     `gorm:"type:longtext;comment:逐行结果（JSON）" json:"-"`
+	Error       string     `gorm:"type:varchar(512);comment:任务中止的原因" json:"error,omitempty"`
+	CreateTime  time.Time  `gorm:"column:createTime;type:datetime(3);not null;comment:创建时间" json:"createTime"`
+	UpdateTime  time.Time  `gorm:"column:updateTime;type:datetime(3);not null;comment:最近一次更新进度的时间" json:"updateTime"`
+	FinishTime  *time.Time `gorm:"column:finishTime;type:datetime(3);comment:结束时间" json:"finishTime,omitempty"`
+}
+
+func (ImportJob) TableName() string {
+	return "user_import_job"
+}
+
+// AddImportJob 写入新任务
+func AddImportJob(job *ImportJob) error {
+	return database.DB.Create(job).Error
+}
+
+// GetImportJob 查询任务，没查到时返回 gorm.ErrRecordNotFound
+func GetImportJob(id string, job *ImportJob) error {
+	return database.DB.Where("id = ?", id).First(job).Error
+}
+
+// SaveImportJobProgress 保存计数、状态与结果
+func SaveImportJobProgress(job *ImportJob) error {
+	return database.DB.Model(&ImportJob{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+		"status":     job.Status,
+		"total":      job.Total,
+		"created":    job.Created,
+		"updated":    job.Updated,
+		"skipped":    job.Skipped,
+		"failed":     job.Failed,
+		"report":     job.Report,
+		"error":      job.Error,
+		"updateTime": job.UpdateTime,
+		"finishTime": job.FinishTime,
+	}).Error
+}
+
+// FailStaleImportJob 把 before 之后没有更新过进度的执行中任务标记为失败，返回是否更新
+func FailStaleImportJob(id string, before time.Time, reason string) (bool, error) {
+	now := time.Now()
+	result := database.DB.Model(&ImportJob{}).
+		Where("id = ? AND status = ? AND updateTime < ?", id, ImportJobRunning, before).
+		Updates(map[string]interface{}{"status": ImportJobFailed, "error": reason, "finishTime": now})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"http_grpc/pkg/database"
+	"time"
+)
+
+// UserMFA 用户的 TOTP 两步验证设置，确认前 Enabled 为 false
+type UserMFA struct {
+	UserID       int64      `gorm:"column:userId;primaryKey;comment:用户ID"`
+	Secret       string     `gorm:"type:varchar(64);not null;comment:TOTP密钥"`
+	Enabled      bool       `gorm:"not null;default:false;comment:是否已启用"`
+	LastUsedStep int64      `gorm:"column:lastUsedStep;not null;default:0;comment:最后使用的时间步，防止验证码重放"`
+	ConfirmedAt  *time.Time `gorm:"column:confirmedAt;type:datetime;comment:启用时间"`
+}
+
+func (UserMFA) TableName() string {
+	return "user_mfa"
+}
+
+// RecoveryCode 一次性恢复码，只保存摘要
+type RecoveryCode struct {
+	ID     int64      `gorm:"primaryKey;autoIncrement"`
+	UserID int64      `gorm:"column:userId;index;not null;comment:用户ID"`
+	Hash   string     `gorm:"type:char(64);not null;comment:恢复码摘要"`
+	UsedAt *time.Time `gorm:"column:usedAt;type:datetime;comment:使用时间"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "user_recovery_code"
+}
+
+// GetUserMFA 查询用户的两步验证设置，没有时返回 gorm.ErrRecordNotFound
+func GetUserMFA(userID int64, mfa *UserMFA) error {
+	return database.DB.First(mfa, "userId = ?", userID).Error
+}
+
+// SaveUserMFA 新建或覆盖两步验证设置（重新登记时替换未确认的密钥）
+func SaveUserMFA(mfa *UserMFA) error {
+	return database.DB.Save(mfa).Error
+}
+
+// EnableUserMFA 确认启用两步验证并替换恢复码
+func EnableUserMFA(db *gorm.DB, userID, step int64, hashes []string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Model(&UserMFA{}).Where("userId = ?", userID).
+			Updates(map[string]interface{}{"enabled": true, "lastUsedStep": step, "confirmedAt": now}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("userId = ?", userID).Delete(&RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]RecoveryCode, 0, len(hashes))
+		for _, hash := range hashes {
+			codes = append(codes, RecoveryCode{UserID: userID, Hash: hash})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// AdvanceMFAStep 记录已使用的时间步，只有比已记录的更新时才成功（并发使用同一验证码时只有一个成功）
+func AdvanceMFAStep(userID, step int64) (bool, error) {
+	result := database.DB.Model(&UserMFA{}).
+		Where("userId = ? AND lastUsedStep < ?", userID, step).
+		Update("lastUsedStep", step)
+	return result.RowsAffected > 0, result.Error
+}
+
+// UseRecoveryCode 使用一个未用过的恢复码，返回是否成功
+func UseRecoveryCode(userID int64, hash string) (bool, error) {
+	result := database.DB.Model(&RecoveryCode{}).
+		Where("userId = ? AND hash = ? AND usedAt IS NULL", userID, hash).
+		Update("usedAt", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteUserMFA 关闭两步验证并删除恢复码
+func DeleteUserMFA(db *gorm.DB, userID int64) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("userId = ?", userID).Delete(&RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("userId = ?", userID).Delete(&UserMFA{}).Error
+	})
+}
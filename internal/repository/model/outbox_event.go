@@ -0,0 +1,82 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"http_grpc/pkg/database"
+	"time"
+)
+
+// OutboxEvent 待发布的领域事件，与产生它的修改在同一事务中写入，由中继按 ID 顺序发布
+type OutboxEvent struct {
+	ID          int64      `gorm:"primaryKey;autoIncrement;comment:ID" json:"id"`
+	EventID     string     `gorm:"column:eventId;type:char(32);uniqueIndex;not null;comment:事件ID，消费方据此去重" json:"eventId"`
+	Type        string     `gorm:"type:varchar(64);not null;comment:事件类型" json:"type"`
+	UserID      int64      `gorm:"column:userId;index;not null;comment:所属用户ID，同一用户的事件按顺序发布" json:"userId"`
+	Payload     string     `gorm:"type:text;not null;comment:事件内容（JSON）" json:"payload"`
+	CreateTime  time.Time  `gorm:"column:createTime;type:datetime(3);not null;comment:发生时间" json:"createTime"`
+	PublishedAt *time.Time `gorm:"column:publishedAt;type:datetime(3);index;comment:发布时间，为空表示待发布" json:"publishedAt"`
+	Attempts    int        `gorm:"not null;default:0;comment:发布失败次数" json:"attempts"`
+	LastError   string     `gorm:"column:lastError;type:varchar(512);comment:最近一次发布失败的原因" json:"lastError,omitempty"`
+	ParkedAt    *time.Time `gorm:"column:parkedAt;type:datetime(3);index;comment:失败次数达到上限后停止发布的时间" json:"parkedAt,omitempty"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox_event"
+}
+
+// AddOutboxEvents 写入待发布事件，db 应为产生事件的修改所在的事务
+func AddOutboxEvents(db *gorm.DB, events ...*OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return db.Create(events).Error
+}
+
+// ListPendingOutboxEvents 按 ID 升序取出一批待发布事件，跳过已停止发布的事件与 excludeUsers 中用户的事件
+func ListPendingOutboxEvents(limit int, excludeUsers []int64) ([]OutboxEvent, error) {
+	var events []OutboxEvent
+	query := database.DB.Where("publishedAt IS NULL AND parkedAt IS NULL")
+	if len(excludeUsers) > 0 {
+		query = query.Where("userId NOT IN ?", excludeUsers)
+	}
+	err := query.Order("id ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// MarkOutboxPublished 标记事件已发布
+func MarkOutboxPublished(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return database.DB.Model(&OutboxEvent{}).Where("id IN ?", ids).Update("publishedAt", time.Now()).Error
+}
+
+// MarkOutboxFailed 记录一次发布失败
+func MarkOutboxFailed(id int64, reason string) error {
+	if len(reason) > 512 {
+		reason = reason[:512]
+	}
+	return database.DB.Model(&OutboxEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts":  gorm.Expr("attempts + 1"),
+		"lastError": reason,
+	}).Error
+}
+
+// ParkOutboxEvent 记录最后一次失败并停止发布该事件，同一用户之后的事件继续发布。
+// 排除故障后把 parkedAt 置空即可重新发布
+func ParkOutboxEvent(id int64, reason string) error {
+	if len(reason) > 512 {
+		reason = reason[:512]
+	}
+	return database.DB.Model(&OutboxEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts":  gorm.Expr("attempts + 1"),
+		"lastError": reason,
+		"parkedAt":  time.Now(),
+	}).Error
+}
+
+// DeletePublishedOutboxEvents 删除 before 之前已发布的事件，每次最多 limit 条，返回删除的数量
+func DeletePublishedOutboxEvents(before time.Time, limit int) (int64, error) {
+	result := database.DB.Where("publishedAt < ?", before).Limit(limit).Delete(&OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"http_grpc/pkg/database"
+	"time"
+)
+
+// PasswordHistory 用户设置过的密码摘要，用于禁止重复使用最近的密码
+type PasswordHistory struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement;comment:ID" json:"id"`
+	UserID     int64     `gorm:"column:userId;index;not null;comment:所属用户ID" json:"userId"`
+	Hash       string    `gorm:"type:varchar(128);not null;comment:密码摘要" json:"-"`
+	CreateTime time.Time `gorm:"column:createTime;type:datetime;default:CURRENT_TIMESTAMP;comment:创建时间" json:"createTime"`
+}
+
+func (PasswordHistory) TableName() string {
+	return "password_history"
+}
+
+// SetUserPassword 更新密码摘要并记入历史，只保留最近 keep 条
+func SetUserPassword(db *gorm.DB, userID int64, hash string, keep int) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&User{}).Where("id = ?", userID).Update("userPassword", hash).Error; err != nil {
+			return err
+		}
+		return addPasswordHistory(tx, userID, hash, keep)
+	})
+}
+
+// AddPasswordHistory 记录新密码摘要，只保留最近 keep 条
+func AddPasswordHistory(db *gorm.DB, userID int64, hash string, keep int) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		return addPasswordHistory(tx, userID, hash, keep)
+	})
+}
+
+func addPasswordHistory(tx *gorm.DB, userID int64, hash string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	if err := tx.Create(&PasswordHistory{UserID: userID, Hash: hash}).Error; err != nil {
+		return err
+	}
+	// MySQL 不支持没有 LIMIT 的 OFFSET，历史记录很少，直接在内存中截取
+	var ids []int64
+	err := tx.Model(&PasswordHistory{}).
+		Where("userId = ?", userID).
+		Order("id DESC").
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) <= keep {
+		return err
+	}
+	return tx.Delete(&PasswordHistory{}, ids[keep:]).Error
+}
+
+// ListPasswordHistory 最近 limit 条密码摘要，新的在前
+func ListPasswordHistory(userID int64, limit int) ([]string, error) {
+	var hashes []string
+	if limit <= 0 {
+		return hashes, nil
+	}
+	err := database.DB.Model(&PasswordHistory{}).
+		Where("userId = ?", userID).
+		Order("id DESC").
+		Limit(limit).
+		Pluck("hash", &hashes).Error
+	return hashes, err
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"context"
 	"errors"
 	"gorm.io/gorm"
 	"http_grpc/pkg/database"
@@ -9,29 +10,36 @@ import (
 
 // User 数据库映射模型
 type User struct {
-	ID           int64     `gorm:"primaryKey;autoIncrement;comment:用户ID" json:"id"`
-	Username     string    `gorm:"type:varchar(256);comment:用户昵称" json:"username"`
-	UserAccount  string    `gorm:"column:userAccount;type:varchar(256);comment:账号" json:"userAccount"`
-	AvatarUrl    string    `gorm:"column:avatarUrl;type:varchar(1024);comment:用户头像" json:"avatarUrl"`
-	Gender       int8      `gorm:"type:tinyint;comment:性别" json:"gender"`
-	UserPassword string    `gorm:"column:userPassword;type:varchar(512);not null;comment:密码" json:"userPassword"`
-	Phone        string    `gorm:"type:varchar(128);comment:电话" json:"phone"`
-	Email        string    `gorm:"type:varchar(512);comment:邮箱" json:"email"`
-	UserStatus   int       `gorm:"column:userStatus;type:int;default:0;comment:用户状态 0-正常" json:"userStatus"`
-	CreateTime   time.Time `gorm:"column:createTime;type:datetime;default:CURRENT_TIMESTAMP;comment:创建时间" json:"createTime"`
-	UpdateTime   time.Time `gorm:"column:updateTime;type:datetime;default:CURRENT_TIMESTAMP;on update CURRENT_TIMESTAMP;comment:更新时间" json:"updateTime"`
-	IsDelete     int8      `gorm:"column:isDelete;type:tinyint;default:0;comment:是否删除" json:"isDelete"`
-	UserRole     int       `gorm:"column:userRole;type:int;not null;comment:用户角色 0-普通用户 1-管理员" json:"userRole"`
-	PlanetCode   string    `gorm:"column:planetCode;type:varchar(512);comment:星球编号" json:"planetCode"`
+	ID            int64     `gorm:"primaryKey;autoIncrement;comment:用户ID" json:"id"`
+	Username      string    `gorm:"type:varchar(256);comment:用户昵称" json:"username"`
+	UserAccount   string    `gorm:"column:userAccount;type:varchar(256);comment:账号" json:"userAccount"`
+	AvatarUrl     string    `gorm:"column:avatarUrl;type:varchar(1024);comment:用户头像" json:"avatarUrl"`
+	Gender        int8      `gorm:"type:tinyint;comment:性别" json:"gender"`
+	UserPassword  string    `gorm:"column:userPassword;type:varchar(512);not null;comment:密码摘要，不参与 JSON 序列化" json:"-"`
+	Phone         string    `gorm:"type:varchar(128);comment:电话" json:"phone"`
+	Email         string    `gorm:"type:varchar(512);comment:邮箱" json:"email"`
+	EmailVerified bool      `gorm:"column:emailVerified;default:false;comment:邮箱是否已验证" json:"emailVerified"`
+	UserStatus    int       `gorm:"column:userStatus;type:int;default:0;comment:用户状态 0-正常" json:"userStatus"`
+	CreateTime    time.Time `gorm:"column:createTime;type:datetime;default:CURRENT_TIMESTAMP;comment:创建时间" json:"createTime"`
+	UpdateTime    time.Time `gorm:"column:updateTime;type:datetime;default:CURRENT_TIMESTAMP;on update CURRENT_TIMESTAMP;comment:更新时间" json:"updateTime"`
+	IsDelete      int8      `gorm:"column:isDelete;type:tinyint;default:0;comment:是否删除" json:"isDelete"`
+	UserRole      int       `gorm:"column:userRole;type:int;not null;comment:用户角色 0-普通用户 1-管理员" json:"userRole"`
+	PlanetCode    string    `gorm:"column:planetCode;type:varchar(512);comment:星球编号" json:"planetCode"`
 }
 
+// 用户状态
+const (
+	UserStatusNormal    = 0
+	UserStatusSuspended = 1 // 已停用：禁止登录，已有会话全部撤销
+)
+
 func (User) TableName() string {
 	return "user"
 }
 
 // AddUser 插入新用户
-func AddUser(user *User) error {
-	return database.DB.Create(user).Error
+func AddUser(db *gorm.DB, user *User) error {
+	return db.Create(user).Error
 }
 
 // GetUserByID 根据用户ID查询用户信息
@@ -39,16 +47,19 @@ func GetUserByID(id int64, user *User) error {
 	return database.DB.First(&user, id).Error
 }
 
-// GetUserByAccount 根据账号查询用户信息
+// GetUserByAccount 根据账号查询用户信息，没查到时返回 gorm.ErrRecordNotFound
 func GetUserByAccount(account string, user *User) error {
-	result := database.DB.Where("userAccount = ?", account).First(user)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil // 没查到
-		}
-		return result.Error // 非正常情况：数据库坏了
+	return database.DB.Where("userAccount = ?", account).First(user).Error
+}
+
+// ListUsersByAccounts 按账号批量查询用户（含已删除的），未删除的在前
+func ListUsersByAccounts(accounts []string) ([]User, error) {
+	var users []User
+	if len(accounts) == 0 {
+		return users, nil
 	}
-	return nil
+	err := database.DB.Where("userAccount IN ?", accounts).Order("isDelete ASC, id ASC").Find(&users).Error
+	return users, err
 }
 
 // FindUserByAccount 查找是否存在
@@ -70,14 +81,32 @@ func UpdateUserPassword(id int64, newPassword string) error {
 	return result.Error
 }
 
+// ListUsersByEmail 查询使用该邮箱的未删除用户
+func ListUsersByEmail(email string) ([]User, error) {
+	var users []User
+	err := database.DB.Where("email = ? AND isDelete = 0", email).Find(&users).Error
+	return users, err
+}
+
+// MarkEmailVerified 邮箱仍为 email 时标记为已验证，返回是否更新
+func MarkEmailVerified(db *gorm.DB, id int64, email string) (bool, error) {
+	result := db.Model(&User{}).Where("id = ? AND email = ?", id, email).Update("emailVerified", true)
+	return result.RowsAffected > 0, result.Error
+}
+
+// UpdateUserStatus 更新用户状态
+func UpdateUserStatus(db *gorm.DB, id int64, status int) error {
+	return db.Model(&User{}).Where("id = ?", id).Update("userStatus", status).Error
+}
+
 // DeleteUser 软删除用户
-func DeleteUser(id int64) error {
-	result := database.DB.Model(&User{}).Where("id = ?", id).Update("isDelete", 1)
+func DeleteUser(db *gorm.DB, id int64) error {
+	result := db.Model(&User{}).Where("id = ?", id).Update("isDelete", 1)
 	return result.Error
 }
 
-func UpdateUser(user *User, fields []string) error {
-	return database.DB.Model(&User{}).
+func UpdateUser(db *gorm.DB, user *User, fields []string) error {
+	return db.Model(&User{}).
 		Where("id = ?", user.ID).
 		Select(fields).
 		Updates(user).
@@ -93,3 +122,23 @@ func ListUsers(page, size int) ([]User, error) {
 	}
 	return users, nil
 }
+
+// EachUser 按 id 顺序逐行读取未删除的用户，只查询 columns 中的列；
+// 使用数据库游标，内存占用与用户数量无关。fn 返回错误或 ctx 结束时停止读取
+func EachUser(ctx context.Context, columns []string, fn func(user *User) error) error {
+	rows, err := database.DB.WithContext(ctx).Model(&User{}).Select(columns).Where("isDelete = 0").Order("id").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var user User
+		if err := database.DB.ScanRows(rows, &user); err != nil {
+			return err
+		}
+		if err := fn(&user); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
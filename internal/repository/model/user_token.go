@@ -0,0 +1,72 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"http_grpc/pkg/database"
+	"time"
+)
+
+// 一次性令牌用途
+const (
+	TokenPurposePasswordReset = "password_reset"
+	TokenPurposeEmailVerify   = "email_verify"
+)
+
+// UserToken 通过邮件发送的一次性令牌（重置密码、验证邮箱），只保存令牌的摘要
+type UserToken struct {
+	ID         int64      `gorm:"primaryKey;autoIncrement;comment:ID" json:"id"`
+	UserID     int64      `gorm:"column:userId;index;not null;comment:所属用户ID" json:"userId"`
+	Purpose    string     `gorm:"type:varchar(32);not null;comment:用途" json:"purpose"`
+	Hash       string     `gorm:"type:char(64);uniqueIndex;not null;comment:令牌摘要" json:"-"`
+	Email      string     `gorm:"type:varchar(512);comment:发送到的邮箱" json:"email"`
+	ExpiresAt  time.Time  `gorm:"column:expiresAt;type:datetime;comment:过期时间" json:"expiresAt"`
+	UsedAt     *time.Time `gorm:"column:usedAt;type:datetime;comment:使用时间" json:"usedAt"`
+	CreateTime time.Time  `gorm:"column:createTime;type:datetime;default:CURRENT_TIMESTAMP;comment:创建时间" json:"createTime"`
+}
+
+func (UserToken) TableName() string {
+	return "user_token"
+}
+
+// AddUserToken 插入新令牌，同一用户同一用途未使用的旧令牌全部作废
+func AddUserToken(tok *UserToken) error {
+	now := time.Now()
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&UserToken{}).
+			Where("userId = ? AND purpose = ? AND usedAt IS NULL", tok.UserID, tok.Purpose).
+			Update("usedAt", now).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(tok).Error
+	})
+}
+
+// FindUserToken 查询未使用且未过期的令牌，返回是否存在
+func FindUserToken(purpose, hash string, tok *UserToken) (bool, error) {
+	result := database.DB.Where("hash = ? AND purpose = ? AND usedAt IS NULL AND expiresAt > ?", hash, purpose, time.Now()).Limit(1).Find(tok)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ConsumeUserToken 使用令牌：未使用且未过期时标记为已使用并读入 tok，返回是否成功。
+// 标记与判断在同一条 UPDATE 中完成，并发使用同一令牌时只有一个请求成功
+func ConsumeUserToken(purpose, hash string, tok *UserToken) (bool, error) {
+	now := time.Now()
+	result := database.DB.Model(&UserToken{}).
+		Where("hash = ? AND purpose = ? AND usedAt IS NULL AND expiresAt > ?", hash, purpose, now).
+		Update("usedAt", now)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	if err := database.DB.Where("hash = ?", hash).First(tok).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RevokeUserTokens 作废用户某种用途的全部未使用令牌
+func RevokeUserTokens(userID int64, purpose string) error {
+	return database.DB.Model(&UserToken{}).
+		Where("userId = ? AND purpose = ? AND usedAt IS NULL", userID, purpose).
+		Update("usedAt", time.Now()).Error
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"http_grpc/pkg/database"
+	"time"
+)
+
+// Webhook 接收领域事件的 HTTP 端点，Secret 用于签名请求，需要明文保存
+type Webhook struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement;comment:ID" json:"id"`
+	URL         string    `gorm:"column:url;type:varchar(1024);not null;comment:接收地址" json:"url"`
+	Secret      string    `gorm:"type:varchar(128);not null;comment:签名密钥" json:"-"`
+	Events      string    `gorm:"type:varchar(512);comment:订阅的事件类型，逗号分隔，为空表示全部" json:"events"`
+	Enabled     bool      `gorm:"not null;default:true;comment:是否启用" json:"enabled"`
+	Description string    `gorm:"type:varchar(256);comment:说明" json:"description"`
+	CreateTime  time.Time `gorm:"column:createTime;type:datetime;default:CURRENT_TIMESTAMP;comment:创建时间" json:"createTime"`
+	UpdateTime  time.Time `gorm:"column:updateTime;type:datetime;default:CURRENT_TIMESTAMP;on update CURRENT_TIMESTAMP;comment:更新时间" json:"updateTime"`
+	IsDelete    int8      `gorm:"column:isDelete;type:tinyint;default:0;comment:是否删除" json:"-"`
+}
+
+func (Webhook) TableName() string {
+	return "webhook"
+}
+
+// 投递状态
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery 投递日志，每次发送一行；同一次投递的重试共用 DeliveryID。
+// DedupKey 只在事件的首次投递中设置，重复发布的事件不会再次投递。
+// NextAttemptAt 非空表示还有待办：pending 行到时仍未完成时重新发送，failed 行到时发送下一次重试
+type WebhookDelivery struct {
+	ID            int64      `gorm:"primaryKey;autoIncrement;comment:ID" json:"id"`
+	WebhookID     int64      `gorm:"column:webhookId;index;not null;comment:Webhook ID" json:"webhookId"`
+	DeliveryID    string     `gorm:"column:deliveryId;type:char(32);index;not null;comment:投递ID，重试时不变" json:"deliveryId"`
+	EventID       string     `gorm:"column:eventId;type:char(32);not null;comment:事件ID" json:"eventId"`
+	EventType     string     `gorm:"column:eventType;type:varchar(64);not null;comment:事件类型" json:"eventType"`
+	Payload       string     `gorm:"type:text;not null;comment:请求体" json:"-"`
+	Attempt       int        `gorm:"not null;comment:第几次发送" json:"attempt"`
+	Redelivery    bool       `gorm:"not null;default:false;comment:是否为手动重新投递或测试" json:"redelivery"`
+	Status        string     `gorm:"type:varchar(16);not null;comment:pending | succeeded | failed" json:"status"`
+	ResponseCode  int        `gorm:"column:responseCode;comment:响应状态码，0 表示未收到响应" json:"responseCode"`
+	ResponseBody  string     `gorm:"column:responseBody;type:varchar(1024);comment:响应内容（截断）" json:"responseBody,omitempty"`
+	Error         string     `gorm:"type:varchar(512);comment:发送失败的原因" json:"error,omitempty"`
+	DurationMs    int64      `gorm:"column:durationMs;comment:耗时（毫秒）" json:"durationMs"`
+	DedupKey      *string    `gorm:"column:dedupKey;type:varchar(64);uniqueIndex;comment:首次投递的去重键" json:"-"`
+	CreateTime    time.Time  `gorm:"column:createTime;type:datetime(3);index;not null;comment:发送时间" json:"createTime"`
+	NextAttemptAt *time.Time `gorm:"column:nextAttemptAt;type:datetime(3);index;comment:下一次发送时间，为空表示没有待办" json:"nextAttemptAt,omitempty"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_delivery"
+}
+
+// AddWebhook 插入新的 Webhook
+func AddWebhook(db *gorm.DB, hook *Webhook) error {
+	return db.Create(hook).Error
+}
+
+// GetWebhook 查询未删除的 Webhook，没查到时返回 gorm.ErrRecordNotFound
+func GetWebhook(id int64, hook *Webhook) error {
+	return database.DB.Where("id = ? AND isDelete = 0", id).First(hook).Error
+}
+
+// ListWebhooks 列出全部未删除的 Webhook
+func ListWebhooks() ([]Webhook, error) {
+	var hooks []Webhook
+	err := database.DB.Where("isDelete = 0").Order("id ASC").Find(&hooks).Error
+	return hooks, err
+}
+
+// ListEnabledWebhooks 列出启用中的 Webhook
+func ListEnabledWebhooks() ([]Webhook, error) {
+	var hooks []Webhook
+	err := database.DB.Where("isDelete = 0 AND enabled = ?", true).Order("id ASC").Find(&hooks).Error
+	return hooks, err
+}
+
+// UpdateWebhook 更新指定字段
+func UpdateWebhook(db *gorm.DB, hook *Webhook, fields []string) error {
+	return db.Model(&Webhook{}).Where("id = ? AND isDelete = 0", hook.ID).Select(fields).Updates(hook).Error
+}
+
+// DeleteWebhook 软删除 Webhook
+func DeleteWebhook(db *gorm.DB, id int64) error {
+	return db.Model(&Webhook{}).Where("id = ?", id).Update("isDelete", 1).Error
+}
+
+// AddWebhookDelivery 写入一次发送记录，返回是否写入；DedupKey 重复时不写入并返回 false
+func AddWebhookDelivery(delivery *WebhookDelivery) (bool, error) {
+	result := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(delivery)
+	return result.RowsAffected > 0, result.Error
+}
+
+// FinishWebhookDelivery 保存发送结果
+func FinishWebhookDelivery(delivery *WebhookDelivery) error {
+	return database.DB.Model(&WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]interface{}{
+		"status":        delivery.Status,
+		"responseCode":  delivery.ResponseCode,
+		"responseBody":  delivery.ResponseBody,
+		"error":         delivery.Error,
+		"durationMs":    delivery.DurationMs,
+		"nextAttemptAt": delivery.NextAttemptAt,
+	}).Error
+}
+
+// ListDueWebhookDeliveries 取出 nextAttemptAt 不晚于 now 的发送记录，最早的在前
+func ListDueWebhookDeliveries(now time.Time, limit int) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	err := database.DB.Where("nextAttemptAt <= ?", now).Order("nextAttemptAt ASC").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ClaimWebhookDelivery 仅当 nextAttemptAt 仍为 due 时改为 next，返回是否成功；
+// 多个实例同时处理到期记录时只有一个成功
+func ClaimWebhookDelivery(id int64, due time.Time, next *time.Time) (bool, error) {
+	result := database.DB.Model(&WebhookDelivery{}).Where("id = ? AND nextAttemptAt = ?", id, due).Update("nextAttemptAt", next)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetWebhookDelivery 查询发送记录，没查到时返回 gorm.ErrRecordNotFound
+func GetWebhookDelivery(webhookID, id int64, delivery *WebhookDelivery) error {
+	return database.DB.Where("id = ? AND webhookId = ?", id, webhookID).First(delivery).Error
+}
+
+// ListWebhookDeliveries 分页查询 Webhook 的发送记录，新的在前
+func ListWebhookDeliveries(webhookID int64, page, size int) ([]WebhookDelivery, int64, error) {
+	query := database.DB.Model(&WebhookDelivery{}).Where("webhookId = ?", webhookID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var deliveries []WebhookDelivery
+	err := query.Order("id DESC").Offset((page - 1) * size).Limit(size).Find(&deliveries).Error
+	return deliveries, total, err
+}
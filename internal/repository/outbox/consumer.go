@@ -0,0 +1,65 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// ErrInProgress 同一事件正在由其他消费者处理，稍后重试
+var ErrInProgress = errors.New("event is being processed")
+
+const (
+	dedupPending = "pending"
+	dedupDone    = "done"
+)
+
+// Deduplicator 以 Redis 记录某个消费方已处理的事件 ID，使重复投递的事件只处理一次
+type Deduplicator struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration // 已处理记录的保留时间，应长于事件可能重复投递的时间范围
+	lease  time.Duration // 处理中标记的有效期，处理方崩溃后到期即可重试
+}
+
+// NewDeduplicator 创建去重器，consumer 区分不同的消费方；ttl 为 0 时保留 7 天
+func NewDeduplicator(client *redis.Client, consumer string, ttl time.Duration) *Deduplicator {
+	if ttl <= 0 {
+		ttl = 7 * 24 * time.Hour
+	}
+	return &Deduplicator{client: client, prefix: "event_done:" + consumer + ":", ttl: ttl, lease: time.Minute}
+}
+
+// Handle 事件未处理过时执行 fn 并记录；已处理过时直接返回 nil；
+// 其他消费者正在处理时返回 ErrInProgress。fn 失败时清除标记，允许重试
+func (d *Deduplicator) Handle(ctx context.Context, e Event, fn Handler) error {
+	key := d.prefix + e.ID
+	ok, err := d.client.SetNX(ctx, key, dedupPending, d.lease).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		state, err := d.client.Get(ctx, key).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		if state == dedupDone {
+			return nil
+		}
+		return ErrInProgress
+	}
+
+	if err := fn(ctx, e); err != nil {
+		d.client.Del(context.Background(), key)
+		return err
+	}
+	return d.client.Set(ctx, key, dedupDone, d.ttl).Err()
+}
+
+// Idempotent 包装 handler，重复投递的事件只处理一次
+func Idempotent(d *Deduplicator, fn Handler) Handler {
+	return func(ctx context.Context, e Event) error {
+		return d.Handle(ctx, e, fn)
+	}
+}
@@ -0,0 +1,97 @@
+package outbox
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"http_grpc/internal/repository/model"
+	"time"
+)
+
+// 领域事件类型
+const (
+	TypeUserCreated     = "user.created"
+	TypeUserUpdated     = "user.updated"
+	TypeUserDeleted     = "user.deleted"
+	TypePasswordChanged = "user.password_changed"
+	TypeUserLoggedIn    = "user.logged_in"
+)
+
+// Types 全部领域事件类型
+var Types = []string{TypeUserCreated, TypeUserUpdated, TypeUserDeleted, TypePasswordChanged, TypeUserLoggedIn}
+
+// Event 发布给其他服务的领域事件。ID 全局唯一，重复投递时相同，消费方据此去重；
+// 同一 UserID 的事件按发生顺序发布
+type Event struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	UserID     int64           `json:"userId"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	RequestID  string          `json:"requestId,omitempty"`
+	Data       json.RawMessage `json:"data,omitempty"`
+}
+
+// UserData UserCreated 与 UserDeleted 的内容
+type UserData struct {
+	UserAccount string `json:"userAccount"`
+	Username    string `json:"username,omitempty"`
+	Email       string `json:"email,omitempty"`
+	UserRole    int    `json:"userRole"`
+}
+
+// UpdatedData UserUpdated 的内容：变更前后的字段，敏感字段已脱敏
+type UpdatedData struct {
+	Changes json.RawMessage `json:"changes"`
+}
+
+// PasswordChangedData PasswordChanged 的内容
+type PasswordChangedData struct {
+	Reason string `json:"reason"` // change | reset | force_reset
+}
+
+// LoggedInData UserLoggedIn 的内容
+type LoggedInData struct {
+	IP        string `json:"ip,omitempty"`
+	Transport string `json:"transport"`
+	MFA       bool   `json:"mfa"` // 是否经过两步验证
+}
+
+// New 生成待写入 outbox 的事件，data 序列化为事件内容
+func New(eventType string, userID int64, requestID string, data interface{}) (*model.OutboxEvent, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	e := Event{
+		ID:         newEventID(),
+		Type:       eventType,
+		UserID:     userID,
+		OccurredAt: time.Now().UTC(),
+		RequestID:  requestID,
+		Data:       raw,
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return &model.OutboxEvent{
+		EventID:    e.ID,
+		Type:       e.Type,
+		UserID:     e.UserID,
+		Payload:    string(payload),
+		CreateTime: e.OccurredAt,
+	}, nil
+}
+
+// Decode 还原 outbox 记录或消息中的事件
+func Decode(payload []byte) (Event, error) {
+	var e Event
+	err := json.Unmarshal(payload, &e)
+	return e, err
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,90 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+)
+
+// EventPublisher 事件的发布方式。返回 nil 表示已可靠送达，返回错误时中继稍后重试，
+// 因此同一事件可能被投递多次
+type EventPublisher interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// Handler 处理一个事件，返回错误表示需要重试
+type Handler func(ctx context.Context, e Event) error
+
+// 可选的发布方式
+const (
+	PublisherRedis     = "redis"     // 写入 Redis Stream，供其他服务消费
+	PublisherInProcess = "inprocess" // 同步调用进程内的订阅者
+	PublisherLog       = "log"       // 只输出日志，用于开发环境
+)
+
+// LogPublisher 以 domain_event {json} 格式写入日志
+type LogPublisher struct{}
+
+func (LogPublisher) Publish(_ context.Context, e Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	log.Printf("domain_event %s", b)
+	return nil
+}
+
+// InProcessPublisher 把事件同步交给进程内的订阅者，任一订阅者失败时整个事件重试
+type InProcessPublisher struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+// NewInProcess 创建进程内发布方式
+func NewInProcess() *InProcessPublisher {
+	return &InProcessPublisher{}
+}
+
+// Subscribe 添加订阅者；重试时已成功的订阅者也会再次收到事件，可用 Idempotent 包装
+func (p *InProcessPublisher) Subscribe(h Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers = append(p.handlers, h)
+}
+
+func (p *InProcessPublisher) Publish(ctx context.Context, e Event) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, h := range p.handlers {
+		if err := h(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// local 进程内订阅者共用的发布方式
+var local = NewInProcess()
+
+// Local 进程内的发布方式，发布方式配置为 inprocess 时由中继使用，订阅者通过 Local().Subscribe 注册
+func Local() *InProcessPublisher {
+	return local
+}
+
+// multiPublisher 依次发布到多个发布方式
+type multiPublisher []EventPublisher
+
+// Multi 组合多个发布方式，任一失败时返回错误，整个事件稍后重试，已成功的发布方式会再次收到该事件
+func Multi(publishers ...EventPublisher) EventPublisher {
+	return multiPublisher(publishers)
+}
+
+func (m multiPublisher) Publish(ctx context.Context, e Event) error {
+	for _, p := range m {
+		if err := p.Publish(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"log"
+	"strings"
+	"time"
+)
+
+// DefaultStream 默认的事件 Stream
+const DefaultStream = "user_events"
+
+// RedisPublisher 把事件追加到 Redis Stream，字段 event 为事件 JSON，type 与 userId 便于筛选
+type RedisPublisher struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+// NewRedisPublisher 创建 Redis Stream 发布方式，maxLen 大于 0 时按近似长度裁剪旧消息
+func NewRedisPublisher(client *redis.Client, stream string, maxLen int64) *RedisPublisher {
+	if stream == "" {
+		stream = DefaultStream
+	}
+	return &RedisPublisher{client: client, stream: stream, maxLen: maxLen}
+}
+
+func (p *RedisPublisher) Publish(ctx context.Context, e Event) error {
+	payload, err := encodeEvent(e)
+	if err != nil {
+		return err
+	}
+	return p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		MaxLen: p.maxLen,
+		Approx: p.maxLen > 0,
+		Values: map[string]interface{}{"event": payload, "type": e.Type, "userId": e.UserID},
+	}).Err()
+}
+
+// StreamConsumerOptions 以消费组读取事件 Stream 的配置
+type StreamConsumerOptions struct {
+	Client   *redis.Client
+	Stream   string        // 为空时使用 DefaultStream
+	Group    string        // 消费组，同组的多个实例分担消息
+	Consumer string        // 组内的消费者名称，每个实例唯一
+	Count    int64         // 每次读取的消息数，默认 10
+	Block    time.Duration // 没有新消息时的等待时间，默认 5s
+}
+
+// pendingRetryDelay 处理失败的消息留在待确认列表中，间隔该时间后重新处理
+const pendingRetryDelay = 30 * time.Second
+
+// ConsumeStream 以消费组读取事件并交给 handler，成功后确认（XACK），失败的消息留在待确认列表中，
+// 稍后与启动时一并重新处理。消息可能重复投递，handler 应配合 Idempotent 使用。ctx 结束时返回
+func ConsumeStream(ctx context.Context, opts StreamConsumerOptions, handler Handler) error {
+	if opts.Stream == "" {
+		opts.Stream = DefaultStream
+	}
+	if opts.Count <= 0 {
+		opts.Count = 10
+	}
+	if opts.Block <= 0 {
+		opts.Block = 5 * time.Second
+	}
+	err := opts.Client.XGroupCreateMkStream(ctx, opts.Stream, opts.Group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("create consumer group: %w", err)
+	}
+
+	// "0" 读取本消费者未确认的消息，">" 读取新消息；先处理未确认的消息
+	start := "0"
+	var retryAt time.Time
+	for ctx.Err() == nil {
+		if start == ">" && !retryAt.IsZero() && time.Now().After(retryAt) {
+			start, retryAt = "0", time.Time{}
+		}
+		streams, err := opts.Client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    opts.Group,
+			Consumer: opts.Consumer,
+			Streams:  []string{opts.Stream, start},
+			Count:    opts.Count,
+			Block:    opts.Block,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			log.Printf("Failed to read event stream: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		messages, failed := 0, false
+		for _, s := range streams {
+			messages += len(s.Messages)
+			for _, msg := range s.Messages {
+				if !handleMessage(ctx, opts, msg, handler) {
+					failed = true
+				}
+			}
+		}
+		if failed && retryAt.IsZero() {
+			retryAt = time.Now().Add(pendingRetryDelay)
+		}
+		if start == "0" && (messages == 0 || failed) {
+			start = ">"
+		}
+	}
+	return ctx.Err()
+}
+
+// handleMessage 处理单条消息，返回是否已确认；无法解析的消息直接确认，避免反复失败
+func handleMessage(ctx context.Context, opts StreamConsumerOptions, msg redis.XMessage, handler Handler) bool {
+	payload, _ := msg.Values["event"].(string)
+	e, err := Decode([]byte(payload))
+	if err != nil {
+		log.Printf("Dropping malformed event %s: %v", msg.ID, err)
+	} else if err := handler(ctx, e); err != nil {
+		log.Printf("Failed to handle event %s (%s): %v", e.ID, e.Type, err)
+		return false
+	}
+	if err := opts.Client.XAck(ctx, opts.Stream, opts.Group, msg.ID).Err(); err != nil {
+		log.Printf("Failed to ack event %s: %v", msg.ID, err)
+		return false
+	}
+	return true
+}
+
+func encodeEvent(e Event) (string, error) {
+	b, err := json.Marshal(e)
+	return string(b), err
+}
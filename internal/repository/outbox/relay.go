@@ -0,0 +1,209 @@
+package outbox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/redis/go-redis/v9"
+	"http_grpc/internal/repository/model"
+	"log"
+	"time"
+)
+
+// relayLockKey 多实例部署时只由一个实例发布，保证事件顺序
+const relayLockKey = "outbox_relay_lock"
+
+// renewScript 锁仍由本实例持有时才续期，比较与续期在 Redis 中原子执行；
+// 分开执行时锁可能在两步之间过期并被其他实例取得，续期会延长别人的锁
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// cleanupInterval 清理已发布事件的间隔
+const cleanupInterval = time.Hour
+
+// maxBlockedUsers 一轮中最多跳过的用户数，超过时多半是发布方式整体不可用，等待下一轮
+const maxBlockedUsers = 1000
+
+// Options 中继配置
+type Options struct {
+	Publisher   EventPublisher
+	Client      *redis.Client // 用于多实例互斥，为空时不加锁
+	Interval    time.Duration // 轮询间隔，默认 1s；事务提交后会立即唤醒
+	BatchSize   int           // 每批发布的事件数，默认 100
+	Retention   time.Duration // 已发布事件在表中的保留时长，默认 7 天
+	MaxAttempts int           // 单个事件最多发布次数，达到后停止发布该事件，默认 10
+}
+
+// wake 有新事件提交时唤醒中继
+var wake = make(chan struct{}, 1)
+
+// Notify 通知中继有新事件，在写入 outbox 的事务提交后调用；不阻塞
+func Notify() {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+// StartRelay 启动中继：按 ID 顺序发布待发布事件，某个用户的事件发布失败时，
+// 本轮跳过该用户之后的事件（不影响其他用户），下一轮从失败的事件重试，保证同一用户的事件有序；
+// 失败 MaxAttempts 次的事件停止发布，该用户之后的事件继续发布。ctx 结束时退出。
+// ID 在写入时分配，不是提交顺序：同一用户的两个事务并发提交时，ID 较小的事件可能在较大的之后才提交，
+// 此时已经发布了较大的那个，因此消费方可能看到乱序的事件，需要按 OccurredAt 或数据本身判断新旧
+func StartRelay(ctx context.Context, opts Options) {
+	if opts.Publisher == nil {
+		return
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = 7 * 24 * time.Hour
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 10
+	}
+	go func() {
+		r := &relay{opts: opts, instance: newInstanceID()}
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+		for {
+			r.run(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-wake:
+			}
+		}
+	}()
+}
+
+type relay struct {
+	opts        Options
+	instance    string
+	lastCleanup time.Time
+}
+
+// run 持有锁时发布全部待发布事件，并定期清理已发布的事件
+func (r *relay) run(ctx context.Context) {
+	if !r.acquire(ctx) {
+		return
+	}
+	// 本轮发布失败的用户，之后的查询跳过这些用户，避免它们的事件占满每一批
+	blocked := map[int64]bool{}
+	for ctx.Err() == nil && len(blocked) < maxBlockedUsers {
+		n, err := r.publishBatch(ctx, blocked)
+		if err != nil {
+			log.Printf("Outbox relay failed: %v", err)
+			return
+		}
+		// 取到的事件不足一批时已没有其他可发布的事件
+		if n < r.opts.BatchSize {
+			break
+		}
+	}
+	if time.Since(r.lastCleanup) >= cleanupInterval {
+		r.lastCleanup = time.Now()
+		r.cleanup()
+	}
+}
+
+// acquire 获取或续期中继锁，锁由持有者在每轮续期，实例退出后到期释放
+func (r *relay) acquire(ctx context.Context) bool {
+	if r.opts.Client == nil {
+		return true
+	}
+	ttl := 10 * r.opts.Interval
+	if ttl < 10*time.Second {
+		ttl = 10 * time.Second
+	}
+	ok, err := r.opts.Client.SetNX(ctx, relayLockKey, r.instance, ttl).Result()
+	if err != nil {
+		log.Printf("Failed to acquire outbox relay lock: %v", err)
+		return false
+	}
+	if ok {
+		return true
+	}
+	renewed, err := renewScript.Run(ctx, r.opts.Client, []string{relayLockKey}, r.instance, ttl.Milliseconds()).Int()
+	if err != nil {
+		log.Printf("Failed to renew outbox relay lock: %v", err)
+		return false
+	}
+	return renewed == 1
+}
+
+// publishBatch 发布一批不属于 blocked 中用户的事件，发布失败的用户加入 blocked；返回取到的事件数
+func (r *relay) publishBatch(ctx context.Context, blocked map[int64]bool) (int, error) {
+	exclude := make([]int64, 0, len(blocked))
+	for id := range blocked {
+		exclude = append(exclude, id)
+	}
+	rows, err := model.ListPendingOutboxEvents(r.opts.BatchSize, exclude)
+	if err != nil {
+		return 0, err
+	}
+	published := make([]int64, 0, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		if blocked[row.UserID] {
+			continue
+		}
+		e, err := Decode([]byte(row.Payload))
+		if err == nil {
+			err = r.opts.Publisher.Publish(ctx, e)
+		}
+		if err != nil {
+			log.Printf("Failed to publish event %s (%s) of user %d, attempt %d: %v", row.EventID, row.Type, row.UserID, row.Attempts+1, err)
+			if row.Attempts+1 >= r.opts.MaxAttempts {
+				// 停止发布该事件，同一用户之后的事件不再被它阻塞
+				log.Printf("Parking event %s of user %d after %d attempts", row.EventID, row.UserID, row.Attempts+1)
+				if markErr := model.ParkOutboxEvent(row.ID, err.Error()); markErr != nil {
+					log.Printf("Failed to park outbox event: %v", markErr)
+					blocked[row.UserID] = true
+				}
+				continue
+			}
+			blocked[row.UserID] = true
+			if markErr := model.MarkOutboxFailed(row.ID, err.Error()); markErr != nil {
+				log.Printf("Failed to record outbox failure: %v", markErr)
+			}
+			continue
+		}
+		published = append(published, row.ID)
+	}
+	// 标记失败时事件会再次发布，消费方按事件 ID 去重
+	if err := model.MarkOutboxPublished(published); err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
+// cleanup 删除超过保留期的已发布事件
+func (r *relay) cleanup() {
+	before := time.Now().Add(-r.opts.Retention)
+	for {
+		n, err := model.DeletePublishedOutboxEvents(before, 1000)
+		if err != nil {
+			log.Printf("Failed to clean up outbox: %v", err)
+			return
+		}
+		if n < 1000 {
+			return
+		}
+	}
+}
+
+func newInstanceID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,46 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRelayLockRenewedOnlyByHolder(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	ctx := context.Background()
+	opts := Options{Client: client, Interval: time.Second}
+	a := &relay{opts: opts, instance: "a"}
+	b := &relay{opts: opts, instance: "b"}
+
+	if !a.acquire(ctx) {
+		t.Fatal("first instance did not acquire the lock")
+	}
+	if b.acquire(ctx) {
+		t.Fatal("second instance acquired a held lock")
+	}
+	server.FastForward(5 * time.Second)
+	if !a.acquire(ctx) {
+		t.Fatal("holder could not renew its lock")
+	}
+	if ttl := server.TTL(relayLockKey); ttl < 9*time.Second {
+		t.Errorf("lock TTL after renewal = %v, want about 10s", ttl)
+	}
+
+	// 锁过期后被其他实例取得，原持有者不能再续期
+	server.FastForward(11 * time.Second)
+	if !b.acquire(ctx) {
+		t.Fatal("second instance did not acquire the expired lock")
+	}
+	if a.acquire(ctx) {
+		t.Fatal("former holder renewed a lock it no longer holds")
+	}
+	if holder, _ := client.Get(ctx, relayLockKey).Result(); holder != "b" {
+		t.Errorf("lock holder = %q, want b", holder)
+	}
+}
@@ -0,0 +1,59 @@
+package password
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"sync"
+)
+
+// Hash 使用 bcrypt 生成密码摘要
+func Hash(plain string) (string, error) {
+	b, err := bcrypt.GenerateFromPassword([]byte(plain), policy.BcryptCost)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Verify 校验密码。stored 为空表示账号不存在，此时与占位摘要比较后返回 false；
+// 旧版本保存的明文密码仍可校验，同样先做一次 bcrypt 比较，使三种情况的耗时一致
+func Verify(stored, plain string) bool {
+	if isHash(stored) {
+		return bcrypt.CompareHashAndPassword([]byte(stored), []byte(plain)) == nil
+	}
+	_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(plain))
+	if stored == "" {
+		return false
+	}
+	a := sha256.Sum256([]byte(stored))
+	b := sha256.Sum256([]byte(plain))
+	return subtle.ConstantTimeCompare(a[:], b[:]) == 1
+}
+
+// NeedsRehash 明文密码或 bcrypt 成本与当前配置不一致时需要重新生成摘要
+func NeedsRehash(stored string) bool {
+	if !isHash(stored) {
+		return stored != ""
+	}
+	cost, err := bcrypt.Cost([]byte(stored))
+	return err != nil || cost != policy.BcryptCost
+}
+
+func isHash(stored string) bool {
+	return strings.HasPrefix(stored, "$2a$") || strings.HasPrefix(stored, "$2b$") || strings.HasPrefix(stored, "$2y$")
+}
+
+var (
+	dummyOnce sync.Once
+	dummy     []byte
+)
+
+// dummyHash 账号不存在时参与比较的占位摘要，成本与正常密码相同
+func dummyHash() []byte {
+	dummyOnce.Do(func() {
+		dummy, _ = bcrypt.GenerateFromPassword([]byte("absent-account-placeholder"), policy.BcryptCost)
+	})
+	return dummy
+}
@@ -0,0 +1,116 @@
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Policy 密码策略
+type Policy struct {
+	MinLength       int  // 最少字符数
+	MaxLength       int  // 最多字符数，bcrypt 只使用前 72 字节
+	MinClasses      int  // 至少包含的字符类别数：小写字母、大写字母、数字、其他符号
+	DisallowAccount bool // 不能与账号相同或包含账号
+	CheckCommon     bool // 不能是常见密码
+	HistorySize     int  // 不能与最近 N 次使用过的密码相同，0 不检查
+	BcryptCost      int  // bcrypt 成本
+}
+
+var policy = Policy{
+	MinLength:       8,
+	MaxLength:       64,
+	MinClasses:      2,
+	DisallowAccount: true,
+	CheckCommon:     true,
+	HistorySize:     5,
+	BcryptCost:      bcrypt.DefaultCost,
+}
+
+// Setup 设置密码策略，未设置的数值项使用默认值
+func Setup(p Policy) {
+	if p.MinLength <= 0 {
+		p.MinLength = policy.MinLength
+	}
+	if p.MaxLength <= 0 {
+		p.MaxLength = policy.MaxLength
+	}
+	if p.MinClasses <= 0 {
+		p.MinClasses = policy.MinClasses
+	}
+	if p.MinClasses > 4 {
+		p.MinClasses = 4
+	}
+	if p.HistorySize < 0 {
+		p.HistorySize = 0
+	}
+	if p.BcryptCost < bcrypt.MinCost || p.BcryptCost > bcrypt.MaxCost {
+		p.BcryptCost = policy.BcryptCost
+	}
+	policy = p
+}
+
+// HistorySize 需要检查的历史密码个数
+func HistorySize() int {
+	return policy.HistorySize
+}
+
+// Check 按密码策略检查新密码（不含历史记录），返回全部不满足的描述
+func Check(account, plain string) []string {
+	var problems []string
+	n := utf8.RuneCountInString(plain)
+	if n < policy.MinLength || n > policy.MaxLength {
+		problems = append(problems, fmt.Sprintf("length must be between %d and %d", policy.MinLength, policy.MaxLength))
+	}
+	if len(plain) > 72 {
+		problems = append(problems, "must not exceed 72 bytes")
+	}
+	if classes(plain) < policy.MinClasses {
+		problems = append(problems, fmt.Sprintf("must contain at least %d of: lowercase letters, uppercase letters, digits, symbols", policy.MinClasses))
+	}
+	lower := strings.ToLower(plain)
+	if policy.DisallowAccount && account != "" && strings.Contains(lower, strings.ToLower(account)) {
+		problems = append(problems, "must not contain the account name")
+	}
+	if policy.CheckCommon && common[lower] {
+		problems = append(problems, "is too common")
+	}
+	return problems
+}
+
+// classes 包含的字符类别数
+func classes(plain string) int {
+	var lower, upper, digit, other int
+	for _, r := range plain {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+	return lower + upper + digit + other
+}
+
+//go:embed common.txt
+var commonList string
+
+// common 常见密码表（小写），随程序打包
+var common = func() map[string]bool {
+	m := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(commonList))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			m[strings.ToLower(line)] = true
+		}
+	}
+	return m
+}()
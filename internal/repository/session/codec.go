@@ -0,0 +1,132 @@
+package session
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 会话中已知字段的键
+const (
+	KeyUserID      = "userID"
+	KeyUserAccount = "userAccount"
+	KeyUserRole    = "userRole"
+	KeyCreatedAt   = "createdAt" // 登录时间
+	KeyUserAgent   = "userAgent" // 登录时的客户端 User-Agent
+	KeyClientIP    = "clientIP"  // 登录时的客户端 IP
+)
+
+// Codec 会话数据编解码器
+type Codec interface {
+	Encode(values map[string]interface{}) ([]byte, error)
+	Decode(data []byte) (map[string]interface{}, error)
+}
+
+// 编码后的格式为 "<版本>:<base64 数据>"，没有版本前缀的是最早的 JSON 格式。
+// 新增编解码器时登记新版本并修改 currentCodecVersion，旧版本数据仍可读取，
+// 下一次写回时自动升级为当前版本。
+const currentCodecVersion = "g1"
+
+var codecs = map[string]Codec{
+	"g1": gobCodec{},
+}
+
+// legacyTypes 旧版 JSON 数据中数字统一解析为 float64，按字段恢复原始类型
+var legacyTypes = map[string]func(json.Number) (interface{}, error){
+	KeyUserID: func(n json.Number) (interface{}, error) {
+		return n.Int64()
+	},
+	KeyUserRole: func(n json.Number) (interface{}, error) {
+		v, err := n.Int64()
+		return int(v), err
+	},
+}
+
+func init() {
+	RegisterType(time.Time{})
+}
+
+// RegisterType 登记存入 Values 的自定义类型，gob 编码 interface 值时需要
+func RegisterType(value interface{}) {
+	gob.Register(value)
+}
+
+// gobCodec 使用 gob 编码，能保留 int64、int、time.Time 等具体类型
+type gobCodec struct{}
+
+func (gobCodec) Encode(values map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(values); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Decode(data []byte) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// encodeValues 使用当前版本的编解码器序列化会话数据
+func encodeValues(values map[string]interface{}) (string, error) {
+	data, err := codecs[currentCodecVersion].Encode(values)
+	if err != nil {
+		return "", fmt.Errorf("encode session values: %w", err)
+	}
+	return currentCodecVersion + ":" + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// decodeValues 按版本前缀选择编解码器反序列化会话数据
+func decodeValues(data string) (map[string]interface{}, error) {
+	if data == "" {
+		return make(map[string]interface{}), nil
+	}
+	version, payload, ok := strings.Cut(data, ":")
+	codec, known := codecs[version]
+	if !ok || !known {
+		return decodeLegacyJSON(data)
+	}
+	raw, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("decode session values (%s): %w", version, err)
+	}
+	return codec.Decode(raw)
+}
+
+// isCurrentEncoding 数据是否已经是当前版本
+func isCurrentEncoding(data string) bool {
+	return strings.HasPrefix(data, currentCodecVersion+":")
+}
+
+// decodeLegacyJSON 读取旧版 JSON 格式，按 legacyTypes 恢复数字类型
+func decodeLegacyJSON(data string) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	values := make(map[string]interface{})
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("decode legacy session values: %w", err)
+	}
+	for key, value := range values {
+		n, isNumber := value.(json.Number)
+		if !isNumber {
+			continue
+		}
+		if convert, typed := legacyTypes[key]; typed {
+			v, err := convert(n)
+			if err != nil {
+				return nil, fmt.Errorf("decode legacy session value %q: %w", key, err)
+			}
+			values[key] = v
+		} else if f, err := n.Float64(); err == nil {
+			values[key] = f
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,107 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// sessionIDBytes 会话 ID 的随机字节数（256 位）
+const sessionIDBytes = 32
+
+// CookieOptions 会话 Cookie 属性
+type CookieOptions struct {
+	Path        string
+	Domain      string
+	Secure      bool
+	SameSite    http.SameSite
+	SigningKeys [][]byte // HMAC 签名密钥，第一个用于签名，全部用于校验；为空时不签名
+}
+
+var cookieOptions = CookieOptions{Path: "/", SameSite: http.SameSiteLaxMode}
+
+// ParseSameSite 解析配置中的 SameSite 取值
+func ParseSameSite(value string) http.SameSite {
+	switch strings.ToLower(value) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+// generateSessionID 使用 crypto/rand 生成不可预测的 SessionID
+func generateSessionID() string {
+	b := make([]byte, sessionIDBytes)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand 失败说明系统熵源不可用，无法安全地继续
+		panic("session: crypto/rand unavailable: " + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// sign 计算会话 ID 的签名
+func sign(key []byte, id string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// encodeCookie 生成 Cookie 值：配置了签名密钥时为 "<id>.<签名>"
+func encodeCookie(id string) string {
+	if len(cookieOptions.SigningKeys) == 0 {
+		return id
+	}
+	return id + "." + sign(cookieOptions.SigningKeys[0], id)
+}
+
+// decodeCookie 校验并取出会话 ID，签名不匹配时视为没有携带会话
+func decodeCookie(value string) (string, bool) {
+	if len(cookieOptions.SigningKeys) == 0 {
+		return value, value != ""
+	}
+	id, signature, ok := strings.Cut(value, ".")
+	if !ok || id == "" {
+		return "", false
+	}
+	for _, key := range cookieOptions.SigningKeys {
+		if hmac.Equal([]byte(signature), []byte(sign(key, id))) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// readSessionID 读取请求携带的会话 ID
+func readSessionID(c *gin.Context) (string, bool) {
+	value, err := c.Cookie(cookieName)
+	if err != nil {
+		return "", false
+	}
+	return decodeCookie(value)
+}
+
+// writeCookie 下发会话 Cookie，有效期与会话剩余有效期一致；未配置超时时为浏览器会话 Cookie
+func writeCookie(c *gin.Context, s *SessionStore) {
+	maxAge := 0
+	if ttl := timeouts.ttl(s, time.Now()); ttl > 0 {
+		maxAge = int(ttl.Seconds())
+	}
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     cookieName,
+		Value:    encodeCookie(s.ID),
+		Path:     cookieOptions.Path,
+		Domain:   cookieOptions.Domain,
+		MaxAge:   maxAge,
+		Secure:   cookieOptions.Secure,
+		HttpOnly: true,
+		SameSite: cookieOptions.SameSite,
+	})
+}
@@ -0,0 +1,181 @@
+package session
+
+import (
+	"context"
+	"hash/fnv"
+	"http_grpc/pkg/pool"
+	"sync"
+	"time"
+)
+
+// tombstoneTTL 已删除会话的记录保留时长，远大于写回任务的排队时间
+const tombstoneTTL = 10 * time.Minute
+
+// HybridStore 进程内近缓存 + Redis：读优先命中本地，写入本地后异步写回 Redis，删除同步完成
+type HybridStore struct {
+	local       *MemoryStore
+	remote      *RedisStore
+	invalidator *Invalidator // 为空时不通知其他实例
+
+	// 按会话 ID 分段加锁，同一会话的写回与删除在 Redis 上串行执行
+	locks [64]sync.Mutex
+	// 已删除的会话 ID 与删除时间，排队中的写回与之后的保存不再写入，避免撤销的会话复活
+	mu      sync.Mutex
+	deleted map[string]time.Time
+}
+
+// NewHybridStore 创建近缓存存储。本地过期清理只移除近缓存：其他实例可能刚访问过该会话，
+// 本地副本的访问时间已经过时；Redis 中的会话按自身 TTL 过期
+func NewHybridStore(local *MemoryStore, remote *RedisStore) *HybridStore {
+	return &HybridStore{local: local, remote: remote, deleted: make(map[string]time.Time)}
+}
+
+// SetInvalidator 开启跨实例变更通知，写回或删除 Redis 成功后发布；
+// 其他实例删除的会话同样记录下来，本实例排队中的写回不再把它写回 Redis
+func (h *HybridStore) SetInvalidator(invalidator *Invalidator) {
+	invalidator.onDeleted = h.markDeleted
+	h.invalidator = invalidator
+}
+
+// Warm 启动时从 Redis 恢复 Session 到本地缓存
+func (h *HybridStore) Warm(ctx context.Context) error {
+	return h.remote.Scan(ctx, func(s *SessionStore) {
+		_ = h.local.Save(ctx, s)
+	})
+}
+
+func (h *HybridStore) Get(ctx context.Context, id string) (*SessionStore, error) {
+	if s, _ := h.local.Get(ctx, id); s != nil {
+		return s, nil
+	}
+	// 本地未命中（可能由其它实例创建），回源 Redis
+	s, err := h.remote.Get(ctx, id)
+	if err != nil || s == nil {
+		return nil, err
+	}
+	_ = h.local.Save(ctx, s)
+	return s, nil
+}
+
+func (h *HybridStore) Save(ctx context.Context, s *SessionStore) error {
+	return h.save(ctx, s, true)
+}
+
+// Touch 只更新最后访问时间，不通知其他实例：
+// 其他实例的旧副本空闲超时后本地读取不再命中，回源 Redis 取得最新访问时间
+func (h *HybridStore) Touch(ctx context.Context, s *SessionStore) error {
+	return h.save(ctx, s, false)
+}
+
+func (h *HybridStore) save(ctx context.Context, s *SessionStore, notify bool) error {
+	// 请求处理期间会话被撤销时，请求结束的保存不再恢复它
+	lock := h.lock(s.ID)
+	lock.Lock()
+	if h.isDeleted(s.ID) {
+		lock.Unlock()
+		return nil
+	}
+	err := h.local.Save(ctx, s)
+	lock.Unlock()
+	if err != nil {
+		return err
+	}
+	// 更新 Redis
+	snapshot := s.clone()
+	pool.SessionPool.AddTask(pool.Task{
+		Job: func() error {
+			lock := h.lock(snapshot.ID)
+			lock.Lock()
+			// 排队期间会话已被删除
+			if h.isDeleted(snapshot.ID) {
+				lock.Unlock()
+				return nil
+			}
+			err := h.remote.Save(context.Background(), snapshot)
+			lock.Unlock()
+			if err != nil {
+				return err
+			}
+			if notify && h.invalidator != nil {
+				h.invalidator.Publish(context.Background(), eventSaved, snapshot.ID)
+			}
+			return nil
+		},
+	})
+	return nil
+}
+
+// Delete 删除本地与 Redis 中的会话，返回时 Redis 已经删除，其他实例已收到通知
+func (h *HybridStore) Delete(ctx context.Context, id string) error {
+	lock := h.lock(id)
+	lock.Lock()
+	h.markDeleted(id)
+	if err := h.local.Delete(ctx, id); err != nil {
+		lock.Unlock()
+		return err
+	}
+	err := h.remote.Delete(ctx, id)
+	lock.Unlock()
+	if err != nil {
+		return err
+	}
+	if h.invalidator != nil {
+		h.invalidator.Publish(ctx, eventDeleted, id)
+	}
+	return nil
+}
+
+// GC 清理本地近缓存与过期的删除记录
+func (h *HybridStore) GC(ctx context.Context) error {
+	h.mu.Lock()
+	for id, at := range h.deleted {
+		if time.Since(at) > tombstoneTTL {
+			delete(h.deleted, id)
+		}
+	}
+	h.mu.Unlock()
+	return h.local.GC(ctx)
+}
+
+// ListByUser 以 Redis 为准，并补上尚未异步写回 Redis 的本地会话
+func (h *HybridStore) ListByUser(ctx context.Context, userID int64) ([]*SessionStore, error) {
+	remote, err := h.remote.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	local, _ := h.local.ListByUser(ctx, userID)
+	seen := make(map[string]bool, len(remote))
+	for _, s := range remote {
+		seen[s.ID] = true
+	}
+	for _, s := range local {
+		if !seen[s.ID] {
+			remote = append(remote, s)
+		}
+	}
+	return remote, nil
+}
+
+// Stats 本地近缓存的运行指标
+func (h *HybridStore) Stats() MemoryStats {
+	return h.local.Stats()
+}
+
+func (h *HybridStore) lock(id string) *sync.Mutex {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(id))
+	return &h.locks[hash.Sum32()%uint32(len(h.locks))]
+}
+
+func (h *HybridStore) markDeleted(id string) {
+	h.mu.Lock()
+	h.deleted[id] = time.Now()
+	h.mu.Unlock()
+}
+
+func (h *HybridStore) isDeleted(id string) bool {
+	h.mu.Lock()
+	_, ok := h.deleted[id]
+	h.mu.Unlock()
+	return ok
+}
@@ -0,0 +1,64 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestHybrid(t *testing.T) (*HybridStore, *RedisStore) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	timeouts := Timeouts{Idle: time.Hour}
+	remote := NewRedisStore(client, timeouts)
+	return NewHybridStore(NewMemoryStore(timeouts, 100, 0), remote), remote
+}
+
+func TestHybridDeleteIsNotUndoneByQueuedSave(t *testing.T) {
+	h, remote := newTestHybrid(t)
+	ctx := context.Background()
+
+	// 写回任务排在删除之前，执行时会话已被删除
+	for i := 0; i < 20; i++ {
+		s := &SessionStore{ID: "sid", LastAccess: time.Now(), Values: map[string]interface{}{KeyUserID: int64(1)}}
+		if err := h.Save(ctx, s); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	if err := h.Delete(ctx, "sid"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	// 请求结束时的保存也不能恢复已撤销的会话
+	_ = h.Save(ctx, &SessionStore{ID: "sid", LastAccess: time.Now(), Values: map[string]interface{}{}})
+
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		if s, err := remote.Get(ctx, "sid"); err != nil || s != nil {
+			t.Fatalf("session resurrected in Redis: %+v, %v", s, err)
+		}
+		if s, _ := h.Get(ctx, "sid"); s != nil {
+			t.Fatalf("session resurrected locally: %+v", s)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHybridDeleteRemovesRedisBeforeReturning(t *testing.T) {
+	h, remote := newTestHybrid(t)
+	ctx := context.Background()
+	s := &SessionStore{ID: "sid", LastAccess: time.Now(), Values: map[string]interface{}{}}
+	if err := remote.Save(ctx, s); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	if err := h.Delete(ctx, "sid"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if got, err := remote.Get(ctx, "sid"); err != nil || got != nil {
+		t.Fatalf("Redis still has the session after Delete returned: %+v, %v", got, err)
+	}
+}
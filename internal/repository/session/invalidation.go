@@ -0,0 +1,141 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/redis/go-redis/v9"
+	"log"
+	"time"
+)
+
+// invalidationChannel 会话变更通知频道
+const invalidationChannel = "session:invalidate"
+
+// 会话变更类型
+const (
+	eventSaved   = "saved"
+	eventDeleted = "deleted"
+)
+
+// sessionEvent 会话变更通知，订阅方收到后丢弃本地副本，下次访问时从 Redis 回源
+type sessionEvent struct {
+	Op       string `json:"op"`
+	ID       string `json:"id"`
+	Instance string `json:"instance"` // 发布者实例，订阅方忽略自己发出的通知
+}
+
+// instanceID 当前进程的标识
+var instanceID = newInstanceID()
+
+func newInstanceID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Invalidator 通过 Redis pub/sub 在实例之间同步会话变更
+type Invalidator struct {
+	client    *redis.Client
+	local     *MemoryStore
+	remote    *RedisStore
+	onDeleted func(id string) // 其他实例删除会话时调用，为空时忽略
+}
+
+// NewInvalidator 创建失效通知器，local 为需要保持一致的本地近缓存
+func NewInvalidator(client *redis.Client, local *MemoryStore, remote *RedisStore) *Invalidator {
+	return &Invalidator{client: client, local: local, remote: remote}
+}
+
+// Publish 通知其他实例某个会话已变更，应在写入 Redis 之后调用
+func (i *Invalidator) Publish(ctx context.Context, op, id string) {
+	payload, _ := json.Marshal(sessionEvent{Op: op, ID: id, Instance: instanceID})
+	if err := i.client.Publish(ctx, invalidationChannel, payload).Err(); err != nil {
+		log.Printf("Failed to publish session event: %v", err)
+	}
+}
+
+// Run 订阅变更通知直到 ctx 结束；连接断开期间可能错过通知，重新订阅成功后与 Redis 全量校对一次
+func (i *Invalidator) Run(ctx context.Context) {
+	pubsub := i.client.Subscribe(ctx, invalidationChannel)
+	defer pubsub.Close()
+
+	subscribed := false
+	backoff := 100 * time.Millisecond
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			// go-redis 在下一次 Receive 时自动重连并重新订阅
+			log.Printf("Session invalidation subscription lost: %v", err)
+			subscribed = false
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, 5*time.Second)
+			continue
+		}
+		backoff = 100 * time.Millisecond
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if m.Kind != "subscribe" || subscribed {
+				continue
+			}
+			subscribed = true
+			if err := i.Resync(ctx); err != nil {
+				log.Printf("Session resync failed: %v", err)
+			}
+		case *redis.Message:
+			i.handle(ctx, m.Payload)
+		}
+	}
+}
+
+func (i *Invalidator) handle(ctx context.Context, payload string) {
+	var event sessionEvent
+	if err := json.Unmarshal([]byte(payload), &event); err != nil || event.ID == "" {
+		return
+	}
+	if event.Instance == instanceID {
+		return
+	}
+	if event.Op == eventDeleted && i.onDeleted != nil {
+		i.onDeleted(event.ID)
+	}
+	// 变更与删除都只需丢弃本地副本：删除后 Redis 中也不存在，变更后回源得到最新值
+	_ = i.local.Delete(ctx, event.ID)
+}
+
+// Resync 逐个校对本地会话：Redis 中已不存在的删除，Redis 中更新的替换
+func (i *Invalidator) Resync(ctx context.Context) error {
+	ids := i.local.IDs()
+	dropped, refreshed := 0, 0
+	for _, id := range ids {
+		local, _ := i.local.Get(ctx, id)
+		if local == nil {
+			continue
+		}
+		remote, err := i.remote.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		switch {
+		case remote == nil:
+			_ = i.local.Delete(ctx, id)
+			dropped++
+		case remote.LastAccess.After(local.LastAccess):
+			_ = i.local.Save(ctx, remote)
+			refreshed++
+		}
+	}
+	if dropped > 0 || refreshed > 0 {
+		log.Printf("Session resync: %d dropped, %d refreshed", dropped, refreshed)
+	}
+	return nil
+}
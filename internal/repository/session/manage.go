@@ -0,0 +1,121 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Info 会话列表中展示的信息，不包含会话 ID 本身
+type Info struct {
+	ID        string    `json:"id"` // 会话 ID 的摘要，仅用于标识与撤销
+	UserAgent string    `json:"userAgent"`
+	IP        string    `json:"ip"`
+	CreatedAt time.Time `json:"createdAt"`
+	LastSeen  time.Time `json:"lastSeen"`
+	Current   bool      `json:"current"`
+}
+
+// publicID 会话 ID 的摘要：对外展示时使用，无法反推出可用的 Cookie
+func publicID(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func toInfo(s *SessionStore, currentID string) Info {
+	info := Info{ID: publicID(s.ID), LastSeen: s.LastAccess, Current: s.ID == currentID}
+	info.CreatedAt, _ = s.Values[KeyCreatedAt].(time.Time)
+	info.UserAgent, _ = s.Values[KeyUserAgent].(string)
+	info.IP, _ = s.Values[KeyClientIP].(string)
+	return info
+}
+
+// CurrentID 请求携带的有效会话 ID，没有时返回空字符串
+func CurrentID(c *gin.Context) string {
+	id, ok := readSessionID(c)
+	if !ok {
+		return ""
+	}
+	if s, err := store.Get(c.Request.Context(), id); err != nil || s == nil {
+		return ""
+	}
+	return id
+}
+
+// ListUserSessions 列出用户的活跃会话，按最后访问时间倒序；currentID 对应的会话标记为当前会话
+func ListUserSessions(ctx context.Context, userID int64, currentID string) ([]Info, error) {
+	sessions, err := store.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]Info, 0, len(sessions))
+	for _, s := range sessions {
+		infos = append(infos, toInfo(s, currentID))
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].LastSeen.After(infos[j].LastSeen)
+	})
+	return infos, nil
+}
+
+// RevokeSession 按会话列表中的 ID 撤销用户的单个会话，会话不存在时返回 false
+func RevokeSession(ctx context.Context, userID int64, id string) (bool, error) {
+	sessions, err := store.ListByUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, s := range sessions {
+		if publicID(s.ID) == id {
+			return true, store.Delete(ctx, s.ID)
+		}
+	}
+	return false, nil
+}
+
+// RevokeUserSessions 撤销用户的全部会话，keepID 非空时保留该会话，返回撤销数量
+func RevokeUserSessions(ctx context.Context, userID int64, keepID string) (int, error) {
+	sessions, err := store.ListByUser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	revoked := 0
+	for _, s := range sessions {
+		if s.ID == keepID {
+			continue
+		}
+		if err := store.Delete(ctx, s.ID); err != nil {
+			return revoked, err
+		}
+		revoked++
+	}
+	return revoked, nil
+}
+
+// Logout 删除当前请求的会话并清除 Cookie
+func Logout(c *gin.Context) error {
+	if id, ok := readSessionID(c); ok {
+		if err := store.Delete(c.Request.Context(), id); err != nil {
+			return err
+		}
+	}
+	clearCookie(c)
+	return nil
+}
+
+// clearCookie 让浏览器立即删除会话 Cookie
+func clearCookie(c *gin.Context) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     cookieName,
+		Value:    "",
+		Path:     cookieOptions.Path,
+		Domain:   cookieOptions.Domain,
+		MaxAge:   -1,
+		Secure:   cookieOptions.Secure,
+		HttpOnly: true,
+		SameSite: cookieOptions.SameSite,
+	})
+}
@@ -0,0 +1,203 @@
+package session
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MemoryStats 进程内存储的运行指标
+type MemoryStats struct {
+	Sessions         int   `json:"sessions"`
+	Bytes            int64 `json:"bytes"`
+	Hits             int64 `json:"hits"`
+	Misses           int64 `json:"misses"`
+	ExpiredEvictions int64 `json:"expiredEvictions"`
+	LRUEvictions     int64 `json:"lruEvictions"`
+}
+
+// memoryEntry 链表节点：会话及其估算大小
+type memoryEntry struct {
+	session *SessionStore
+	size    int64
+}
+
+// MemoryStore 进程内存储：map + 按访问时间排序的链表（volatile-LRU）
+type MemoryStore struct {
+	sessions    map[string]*list.Element // sessionID -> memoryEntry
+	list        *list.List               // 表头为最近访问，表尾为最久未访问
+	lock        sync.RWMutex             // 读写锁
+	timeouts    Timeouts                 // 过期策略
+	maxSessions int                      // 最多保留的会话数，0 不限制
+	maxBytes    int64                    // 估算内存上限，0 不限制
+	usedBytes   int64
+
+	hits, misses, expiredEvictions, lruEvictions atomic.Int64
+}
+
+// NewMemoryStore 创建进程内存储
+func NewMemoryStore(timeouts Timeouts, maxSessions int, maxBytes int64) *MemoryStore {
+	return &MemoryStore{
+		sessions:    make(map[string]*list.Element),
+		list:        list.New(),
+		timeouts:    timeouts,
+		maxSessions: maxSessions,
+		maxBytes:    maxBytes,
+	}
+}
+
+func (m *MemoryStore) Get(_ context.Context, id string) (*SessionStore, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	element, ok := m.sessions[id]
+	if !ok {
+		m.misses.Add(1)
+		return nil, nil
+	}
+	s := element.Value.(*memoryEntry).session
+	if m.timeouts.expired(s, time.Now()) {
+		m.misses.Add(1)
+		return nil, nil
+	}
+	m.hits.Add(1)
+	return s.clone(), nil
+}
+
+func (m *MemoryStore) Save(_ context.Context, s *SessionStore) error {
+	entry := &memoryEntry{session: s.clone(), size: estimateSize(s)}
+
+	m.lock.Lock()
+	if element, ok := m.sessions[s.ID]; ok {
+		// 更新并移动到链表头
+		m.usedBytes += entry.size - element.Value.(*memoryEntry).size
+		element.Value = entry
+		m.list.MoveToFront(element)
+	} else {
+		m.sessions[s.ID] = m.list.PushFront(entry) // 新的放到链表头
+		m.usedBytes += entry.size
+	}
+	m.evictOverCapacity()
+	m.lock.Unlock()
+	return nil
+}
+
+func (m *MemoryStore) Delete(_ context.Context, id string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if element, ok := m.sessions[id]; ok {
+		m.remove(element)
+	}
+	return nil
+}
+
+// IDs 当前保存的全部会话 ID
+func (m *MemoryStore) IDs() []string {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	ids := make([]string, 0, len(m.sessions))
+	for id := range m.sessions {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// ListByUser 遍历本地会话，按所属用户过滤
+func (m *MemoryStore) ListByUser(_ context.Context, userID int64) ([]*SessionStore, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	now := time.Now()
+	var result []*SessionStore
+	for element := m.list.Front(); element != nil; element = element.Next() {
+		s := element.Value.(*memoryEntry).session
+		if id, ok := s.userID(); ok && id == userID && !m.timeouts.expired(s, now) {
+			result = append(result, s.clone())
+		}
+	}
+	return result, nil
+}
+
+// GC 清理过期的 Session
+// 记住我会话与绝对超时使链表顺序不再等同于过期顺序，因此遍历全部会话
+func (m *MemoryStore) GC(_ context.Context) error {
+	evicted := 0
+
+	m.lock.Lock()
+	now := time.Now()
+	for element := m.list.Back(); element != nil; {
+		prev := element.Prev()
+		if s := element.Value.(*memoryEntry).session; m.timeouts.expired(s, now) {
+			m.remove(element)
+			evicted++
+		}
+		element = prev
+	}
+	m.lock.Unlock()
+
+	m.expiredEvictions.Add(int64(evicted))
+	return nil
+}
+
+// Stats 当前运行指标
+func (m *MemoryStore) Stats() MemoryStats {
+	m.lock.RLock()
+	sessions, bytes := len(m.sessions), m.usedBytes
+	m.lock.RUnlock()
+	return MemoryStats{
+		Sessions:         sessions,
+		Bytes:            bytes,
+		Hits:             m.hits.Load(),
+		Misses:           m.misses.Load(),
+		ExpiredEvictions: m.expiredEvictions.Load(),
+		LRUEvictions:     m.lruEvictions.Load(),
+	}
+}
+
+// evictOverCapacity 超过容量时从表尾淘汰最久未访问的会话，需持有写锁
+func (m *MemoryStore) evictOverCapacity() {
+	evicted := 0
+	for m.overCapacity() {
+		element := m.list.Back()
+		if element == nil || element == m.list.Front() {
+			// 至少保留刚写入的会话
+			break
+		}
+		m.remove(element)
+		evicted++
+	}
+	m.lruEvictions.Add(int64(evicted))
+}
+
+func (m *MemoryStore) overCapacity() bool {
+	return (m.maxSessions > 0 && len(m.sessions) > m.maxSessions) ||
+		(m.maxBytes > 0 && m.usedBytes > m.maxBytes)
+}
+
+// remove 从 list 和 map 中移除，需持有写锁
+func (m *MemoryStore) remove(element *list.Element) {
+	entry := element.Value.(*memoryEntry)
+	m.list.Remove(element)
+	delete(m.sessions, entry.session.ID)
+	m.usedBytes -= entry.size
+}
+
+// entryOverhead map 槽位、链表节点与结构体本身的估算开销
+const entryOverhead = 256
+
+// estimateSize 估算会话占用的内存字节数
+func estimateSize(s *SessionStore) int64 {
+	size := int64(entryOverhead + len(s.ID))
+	for k, v := range s.Values {
+		size += int64(len(k)) + 16
+		switch value := v.(type) {
+		case string:
+			size += int64(len(value))
+		case []byte:
+			size += int64(len(value))
+		default:
+			size += 16
+		}
+	}
+	return size
+}
@@ -0,0 +1,186 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+const sessionRedisPrefix = "session:"
+
+// sessionUserPrefix 用户会话索引（Set），成员为会话 ID；
+// 会话过期或删除后残留的成员在 ListByUser 时清理
+const sessionUserPrefix = "session_user:"
+
+// RedisStore 以 Redis 为唯一存储，不保留本地副本，多实例共享同一份会话
+type RedisStore struct {
+	client   *redis.Client
+	timeouts Timeouts
+}
+
+// NewRedisStore 创建 Redis 存储，键的 TTL 与会话剩余有效期一致
+func NewRedisStore(client *redis.Client, timeouts Timeouts) *RedisStore {
+	return &RedisStore{client: client, timeouts: timeouts}
+}
+
+func (r *RedisStore) Get(ctx context.Context, id string) (*SessionStore, error) {
+	data, err := r.client.HGetAll(ctx, sessionRedisPrefix+id).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	s, err := parseRedisSession(data)
+	if err != nil {
+		return nil, err
+	}
+	if r.timeouts.expired(s, time.Now()) {
+		return nil, nil
+	}
+	return s, nil
+}
+
+func (r *RedisStore) Save(ctx context.Context, s *SessionStore) error {
+	key := sessionRedisPrefix + s.ID
+	values, err := encodeValues(s.Values)
+	if err != nil {
+		return err
+	}
+	ttl := r.timeouts.ttl(s, time.Now())
+	if ttl < 0 {
+		// 已过期的会话不再写入
+		return r.client.Del(ctx, key).Err()
+	}
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, map[string]interface{}{
+			"id":          s.ID,
+			"last_access": s.LastAccess.UnixNano(),
+			"values":      values,
+		})
+		if ttl > 0 {
+			pipe.Expire(ctx, key, ttl)
+		}
+		if userID, ok := s.userID(); ok {
+			indexKey := sessionUserPrefix + strconv.FormatInt(userID, 10)
+			pipe.SAdd(ctx, indexKey, s.ID)
+			if longest := r.timeouts.longest(); longest > 0 {
+				pipe.Expire(ctx, indexKey, longest)
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+func (r *RedisStore) Delete(ctx context.Context, id string) error {
+	return r.client.Del(ctx, sessionRedisPrefix+id).Err()
+}
+
+// ListByUser 读取用户会话索引，顺带移除已失效的成员
+func (r *RedisStore) ListByUser(ctx context.Context, userID int64) ([]*SessionStore, error) {
+	indexKey := sessionUserPrefix + strconv.FormatInt(userID, 10)
+	ids, err := r.client.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	var result []*SessionStore
+	var stale []interface{}
+	for _, id := range ids {
+		s, err := r.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			stale = append(stale, id)
+			continue
+		}
+		// 会话已被重新分配给其他用户（例如同一浏览器换号登录）
+		if uid, ok := s.userID(); !ok || uid != userID {
+			stale = append(stale, id)
+			continue
+		}
+		result = append(result, s)
+	}
+	if len(stale) > 0 {
+		r.client.SRem(ctx, indexKey, stale...)
+	}
+	return result, nil
+}
+
+// GC 由 Redis 键过期负责清理
+func (r *RedisStore) GC(_ context.Context) error {
+	return nil
+}
+
+// Scan 遍历 Redis 中所有未过期的会话
+func (r *RedisStore) Scan(ctx context.Context, fn func(s *SessionStore)) error {
+	iter := r.client.Scan(ctx, 0, sessionRedisPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		// 取回 Session 数据
+		data, err := r.client.HGetAll(ctx, key).Result()
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		s, err := parseRedisSession(data)
+		if err != nil {
+			return err
+		}
+		// 过滤超时信息
+		if r.timeouts.expired(s, time.Now()) {
+			// 已过期，删除
+			r.client.Del(ctx, key)
+			continue
+		}
+		fn(s)
+	}
+	return iter.Err()
+}
+
+// Migrate 将 Redis 中旧版本编码的会话数据改写为当前版本，TTL 保持不变
+func (r *RedisStore) Migrate(ctx context.Context) (int, error) {
+	migrated := 0
+	iter := r.client.Scan(ctx, 0, sessionRedisPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		data, err := r.client.HGet(ctx, key, "values").Result()
+		if err != nil || isCurrentEncoding(data) {
+			continue
+		}
+		values, err := decodeValues(data)
+		if err != nil {
+			// 无法解析的数据直接丢弃，用户重新登录即可
+			r.client.Del(ctx, key)
+			continue
+		}
+		encoded, err := encodeValues(values)
+		if err != nil {
+			return migrated, err
+		}
+		if err := r.client.HSet(ctx, key, "values", encoded).Err(); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, iter.Err()
+}
+
+// parseRedisSession 恢复数据格式
+func parseRedisSession(data map[string]string) (*SessionStore, error) {
+	if data["id"] == "" {
+		return nil, errors.New("session hash without id")
+	}
+	lastAccessUnix, _ := strconv.ParseInt(data["last_access"], 10, 64)
+	values, err := decodeValues(data["values"])
+	if err != nil {
+		return nil, err
+	}
+	return &SessionStore{
+		ID:         data["id"],
+		LastAccess: time.Unix(0, lastAccessUnix),
+		Values:     values,
+	}, nil
+}
@@ -1,45 +1,55 @@
 package session
 
 import (
-	"container/list"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
-	"http_grpc/pkg/pool"
-	"math/rand"
-	"strconv"
-	"sync"
+	"log"
+	"sync/atomic"
 	"time"
 )
 
-// SessionStore 结构体
+// SessionStore 结构体：单个会话
 type SessionStore struct {
 	ID         string
 	LastAccess time.Time
 	Values     map[string]interface{}
 }
 
-// Provider 结构体：管理所有session
-type Provider struct {
-	sessions    map[string]*list.Element // sessionID -> SessionStore
-	list        *list.List               // 便于 GC 管理
-	lock        sync.RWMutex             // 读写锁
-	maxLifeTime time.Duration            // 超时时间
+// Store 会话存储后端
+type Store interface {
+	// Get 获取未过期的会话，不存在时返回 nil, nil
+	Get(ctx context.Context, id string) (*SessionStore, error)
+	// Save 新建或更新会话
+	Save(ctx context.Context, s *SessionStore) error
+	// Delete 删除会话
+	Delete(ctx context.Context, id string) error
+	// GC 清理过期会话，依赖 TTL 过期的后端可以为空实现
+	GC(ctx context.Context) error
+	// ListByUser 列出用户的全部未过期会话
+	ListByUser(ctx context.Context, userID int64) ([]*SessionStore, error)
 }
 
-// 创建一个全局 Provider
-var provider = &Provider{
-	sessions: make(map[string]*list.Element),
-	list:     list.New(),
-}
+// 可选的存储后端
+const (
+	BackendMemory = "memory" // 仅进程内，多实例之间不共享
+	BackendRedis  = "redis"  // Redis 为唯一存储，不保留本地副本
+	BackendHybrid = "hybrid" // 进程内近缓存 + Redis 持久化
+	BackendSQL    = "sql"    // MySQL 存储
+)
+
+const cookieName = "session_id"
 
 // Redis 客户端
 var rdb *redis.Client
-var ctx = context.Background()
 
-const sessionRedisPrefix = "session:"
+// store 当前使用的存储后端
+var store Store
+
+// loaded 启动时是否已完成 Session 恢复
+var loaded atomic.Bool
 
 // InitRedis 初始化 Redis
 func InitRedis(addr, password string, db int) error {
@@ -50,179 +60,231 @@ func InitRedis(addr, password string, db int) error {
 	})
 
 	// 测试连接
-	_, err := rdb.Ping(ctx).Result()
+	_, err := rdb.Ping(context.Background()).Result()
 	return err
 }
 
-// LoadSessionsFromRedis 启动时从 Redis 恢复 Session
-func LoadSessionsFromRedis() error {
-	keys, err := rdb.Keys(ctx, sessionRedisPrefix+"*").Result()
-	if err != nil {
-		return err
+// Client Redis 客户端，供其他需要 Redis 的存储共用连接
+func Client() *redis.Client {
+	return rdb
+}
+
+// Ping 检查 Redis 连接是否可用
+func Ping(ctx context.Context) error {
+	if rdb == nil {
+		return errors.New("redis not initialized")
 	}
+	return rdb.Ping(ctx).Err()
+}
 
-	for _, key := range keys {
-		// 取回 Session 数据
-		data, getSessionErr := rdb.HGetAll(ctx, key).Result()
-		if getSessionErr != nil || len(data) == 0 {
-			continue
-		}
+// Options 会话配置
+type Options struct {
+	Backend        string        // 存储后端
+	Timeouts       Timeouts      // 过期策略
+	MigrateOnStart bool          // 启动时把 Redis 中旧版本编码的会话升级为当前版本
+	MaxSessions    int           // 进程内最多保留的会话数，0 不限制
+	MaxMemory      int64         // 进程内会话估算占用字节上限，0 不限制
+	LazyCreate     bool          // 未登录的请求不创建会话
+	Cookie         CookieOptions // Cookie 属性
+}
 
-		// 恢复数据格式
-		lastAccessUnix, _ := strconv.ParseInt(data["last_access"], 10, 64)
-		// 过滤超时信息
-		if lastAccess := time.Unix(0, lastAccessUnix); lastAccess.Add(provider.maxLifeTime).Before(time.Now()) {
-			// 已过期，删除
-			rdb.Del(ctx, key)
-			continue
-		}
+var (
+	lazyCreate bool     // 未登录的请求不创建会话
+	timeouts   Timeouts // 过期策略，Cookie 有效期与之一致
+)
 
-		var values map[string]interface{}
-		unmarshalErr := json.Unmarshal([]byte(data["values"]), &values)
-		if unmarshalErr != nil {
-			return unmarshalErr
+// Setup 按配置创建存储后端，hybrid 模式下从 Redis 预热本地缓存
+func Setup(opts Options) error {
+	backend := opts.Backend
+	if opts.MigrateOnStart && (backend == BackendRedis || backend == BackendHybrid || backend == "") {
+		n, err := NewRedisStore(rdb, opts.Timeouts).Migrate(context.Background())
+		if err != nil {
+			return fmt.Errorf("migrate sessions: %w", err)
+		}
+		if n > 0 {
+			log.Printf("Migrated %d sessions to codec %s", n, currentCodecVersion)
 		}
+	}
 
-		store := &SessionStore{
-			ID:         data["id"],
-			LastAccess: time.Unix(0, lastAccessUnix),
-			Values:     make(map[string]interface{}),
+	switch backend {
+	case BackendMemory:
+		store = NewMemoryStore(opts.Timeouts, opts.MaxSessions, opts.MaxMemory)
+	case BackendRedis:
+		store = NewRedisStore(rdb, opts.Timeouts)
+	case BackendHybrid, "":
+		local := NewMemoryStore(opts.Timeouts, opts.MaxSessions, opts.MaxMemory)
+		remote := NewRedisStore(rdb, opts.Timeouts)
+		hybrid := NewHybridStore(local, remote)
+		if err := hybrid.Warm(context.Background()); err != nil {
+			return err
 		}
+		// 其他实例修改或撤销会话时丢弃本地副本
+		invalidator := NewInvalidator(rdb, local, remote)
+		hybrid.SetInvalidator(invalidator)
+		go invalidator.Run(context.Background())
+		store = hybrid
+	case BackendSQL:
+		sqlStore, err := NewSQLStore(opts.Timeouts)
+		if err != nil {
+			return err
+		}
+		store = sqlStore
+	default:
+		return fmt.Errorf("unknown session backend %q", backend)
+	}
+	lazyCreate = opts.LazyCreate
+	timeouts = opts.Timeouts
+	if opts.Cookie.Path == "" {
+		opts.Cookie.Path = "/"
+	}
+	cookieOptions = opts.Cookie
+	publishStats()
+	loaded.Store(true)
+	return nil
+}
 
-		// 这里的 Values 简化处理：可以根据需要扩展
-		provider.lock.Lock()
-		element := provider.list.PushFront(store)
-		provider.sessions[store.ID] = element
-		provider.lock.Unlock()
+// CheckLoaded 就绪检查：Session 是否已完成恢复
+func CheckLoaded(ctx context.Context) error {
+	if !loaded.Load() {
+		return errors.New("sessions not loaded")
 	}
 	return nil
 }
 
 // GetSession 获取或者创建新的 SessionStore
 func GetSession(c *gin.Context) *SessionStore {
-	var store *SessionStore
-
-	// 获取session_id cookie
-	cookie, err := c.Cookie("session_id")
-	if err == nil {
-		sessionID := cookie
-		provider.lock.RLock()
-		if element, ok := provider.sessions[sessionID]; ok {
-			store = element.Value.(*SessionStore)
+	ctx := c.Request.Context()
+	// 获取session_id cookie 对应的会话，并更新最后访问时间
+	if s := Lookup(c); s != nil {
+		return s
+	}
+
+	// 创建新 Session
+	s := newSession()
+	if err := store.Save(ctx, s); err != nil {
+		log.Printf("Failed to save session: %v", err)
+	}
+	// 设置到 Cookie
+	writeCookie(c, s)
+	return s
+}
+
+// Lookup 获取请求携带的已有会话，不存在时返回 nil 且不创建
+func Lookup(c *gin.Context) *SessionStore {
+	sessionID, ok := readSessionID(c)
+	if !ok {
+		return nil
+	}
+	ctx := c.Request.Context()
+	s, err := store.Get(ctx, sessionID)
+	if err != nil {
+		log.Printf("Failed to load session: %v", err)
+		return nil
+	}
+	// 距上次刷新超过 RefreshInterval 才回写访问时间并延长 Cookie，避免每个请求都写存储
+	if now := time.Now(); s != nil && timeouts.needsRefresh(s, now) {
+		s.LastAccess = now
+		if saveErr := touch(ctx, s); saveErr != nil {
+			log.Printf("Failed to save session: %v", saveErr)
 		}
-		provider.lock.RUnlock()
+		writeCookie(c, s)
 	}
+	return s
+}
 
-	if store == nil {
-		// 创建新 Session
-		store = newSession()
-		provider.lock.Lock()
-		element := provider.list.PushFront(store) // 新的放到链表头
-		provider.sessions[store.ID] = element
-		provider.lock.Unlock()
+// Current 用于鉴权等只读场景：开启 LazyCreate 时不为匿名请求创建会话，
+// 返回的会话可能为空 Values 的临时对象
+func Current(c *gin.Context) *SessionStore {
+	if !lazyCreate {
+		return GetSession(c)
+	}
+	if s := Lookup(c); s != nil {
+		return s
+	}
+	return newSession()
+}
 
-		// 设置到 Cookie
-		c.SetCookie("session_id", store.ID, 30*60, "/", "", false, true)
-	} else {
-		// 更新最后访问时间并移动到链表头
-		provider.lock.Lock()
-		store.LastAccess = time.Now()
-		provider.list.MoveToFront(provider.sessions[store.ID])
-		provider.lock.Unlock()
+// Rotate 更换会话 ID 并保留数据，旧 ID 立即失效，防止会话固定攻击
+func Rotate(c *gin.Context, s *SessionStore) (*SessionStore, error) {
+	ctx := c.Request.Context()
+	rotated := s.clone()
+	rotated.ID = generateSessionID()
+	rotated.LastAccess = time.Now()
+	if err := store.Save(ctx, rotated); err != nil {
+		return nil, err
 	}
+	if err := store.Delete(ctx, s.ID); err != nil {
+		log.Printf("Failed to delete rotated session: %v", err)
+	}
+	writeCookie(c, rotated)
+	return rotated, nil
+}
 
-	// 更新 Redis
-	saveSessionToRedis(store)
+// Elevate 登录或权限变化时使用：轮换会话 ID 后写入身份相关的值，
+// 同时记录登录时间与客户端信息供会话列表展示，绝对超时从此刻重新计算
+func Elevate(c *gin.Context, values map[string]interface{}) (*SessionStore, error) {
+	current := Lookup(c)
+	if current == nil {
+		current = newSession()
+	}
+	for k, v := range values {
+		current.Values[k] = v
+	}
+	current.Values[KeyCreatedAt] = time.Now()
+	current.Values[KeyUserAgent] = c.Request.UserAgent()
+	current.Values[KeyClientIP] = c.ClientIP()
+	return Rotate(c, current)
+}
+
+// touch 仅刷新访问时间，后端支持时不触发跨实例通知
+func touch(ctx context.Context, s *SessionStore) error {
+	if t, ok := store.(interface {
+		Touch(ctx context.Context, s *SessionStore) error
+	}); ok {
+		return t.Touch(ctx, s)
+	}
+	return store.Save(ctx, s)
+}
 
-	return store
+// Save 修改 Values 后写回存储
+func Save(ctx context.Context, s *SessionStore) error {
+	return store.Save(ctx, s)
 }
 
 // 创建新的 SessionStore
 func newSession() *SessionStore {
+	now := time.Now()
 	return &SessionStore{
 		ID:         generateSessionID(),
-		LastAccess: time.Now(),
-		Values:     make(map[string]interface{}),
+		LastAccess: now,
+		Values:     map[string]interface{}{KeyCreatedAt: now},
 	}
 }
 
-// 保存 SessionStore 到 Redis
-func saveSessionToRedis(store *SessionStore) {
-	key := sessionRedisPrefix + store.ID
-
-	valueBytes, err := json.Marshal(store.Values)
-	if err != nil {
-		// 打印日志，不阻止流程
-		fmt.Println("Failed to marshal session values:", err)
-		valueBytes = []byte("{}")
-	}
-
-	pool.SessionPool.AddTask(pool.Task{
-		Job: func() error {
-			rdb.HSet(ctx, key, map[string]interface{}{
-				"id":          store.ID,
-				"last_access": store.LastAccess.UnixNano(),
-				"values":      string(valueBytes),
-			})
-			rdb.Expire(ctx, key, 120*time.Minute)
-			return nil
-		},
-	})
-}
-
-// 删除 SessionStore 从 Redis
-func deleteSessionFromRedis(sessionID string) {
-	key := sessionRedisPrefix + sessionID
-	pool.SessionPool.AddTask(pool.Task{
-		Job: func() error {
-			rdb.Del(ctx, key)
-			return nil
-		},
-	})
+// clone 复制会话，避免多个请求并发修改同一个 Values
+func (s *SessionStore) clone() *SessionStore {
+	values := make(map[string]interface{}, len(s.Values))
+	for k, v := range s.Values {
+		values[k] = v
+	}
+	return &SessionStore{ID: s.ID, LastAccess: s.LastAccess, Values: values}
 }
 
-// SessionGC 清理过期的 Session
-func SessionGC() {
-	provider.lock.Lock()
-	defer provider.lock.Unlock()
-
-	for {
-		element := provider.list.Back() // 从尾部开始（最久未访问的）
-		if element == nil {
-			break
-		}
-
-		// 如果已经过期
-		if store := element.Value.(*SessionStore); store.LastAccess.Add(provider.maxLifeTime).Before(time.Now()) {
-			// 从 list 和 map 中移除
-			provider.list.Remove(element)
-			delete(provider.sessions, store.ID)
-			pool.SessionPool.AddTask(pool.Task{
-				Job: func() error {
-					deleteSessionFromRedis(store.ID)
-					return nil
-				},
-			})
-		} else {
-			// list 按时间顺序，后面都不会过期
-			break
-		}
-	}
+// userID 会话所属用户，未登录时 ok 为 false
+func (s *SessionStore) userID() (int64, bool) {
+	id, ok := s.Values[KeyUserID].(int64)
+	return id, ok
 }
 
 // StartGC 启动后台Session回收协程
-func StartGC(maxLifetime time.Duration) {
+func StartGC() {
 	ticker := time.NewTicker(1 * time.Minute) // 每1分钟检查一次
-	provider.maxLifeTime = maxLifetime
 	go func() {
 		for {
 			<-ticker.C
-			SessionGC()
+			if err := store.GC(context.Background()); err != nil {
+				log.Printf("Session GC failed: %v", err)
+			}
 		}
 	}()
 }
-
-// 生成简单的随机 SessionID
-func generateSessionID() string {
-	return strconv.FormatInt(time.Now().UnixNano(), 36) + "-" + strconv.Itoa(rand.Intn(100000))
-}
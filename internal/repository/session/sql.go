@@ -0,0 +1,102 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"http_grpc/pkg/database"
+	"time"
+)
+
+// sqlSession 会话表映射模型
+type sqlSession struct {
+	ID         string    `gorm:"primaryKey;type:varchar(64);comment:会话ID"`
+	UserID     int64     `gorm:"column:userId;index;comment:所属用户ID，未登录为0"`
+	Data       string    `gorm:"type:text;comment:会话数据"`
+	LastAccess time.Time `gorm:"column:lastAccess;type:datetime(6);comment:最后访问时间"`
+	ExpiresAt  time.Time `gorm:"column:expiresAt;type:datetime(6);index;comment:过期时间"`
+}
+
+func (sqlSession) TableName() string {
+	return "session"
+}
+
+// neverExpires 未配置超时的会话在表中的过期时间
+var neverExpires = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// SQLStore 基于 MySQL 的会话存储，多实例共享
+type SQLStore struct {
+	db       *gorm.DB
+	timeouts Timeouts
+}
+
+// NewSQLStore 创建 SQL 存储并迁移会话表
+func NewSQLStore(timeouts Timeouts) (*SQLStore, error) {
+	if err := database.DB.AutoMigrate(&sqlSession{}); err != nil {
+		return nil, err
+	}
+	return &SQLStore{db: database.DB, timeouts: timeouts}, nil
+}
+
+func (q *SQLStore) Get(ctx context.Context, id string) (*SessionStore, error) {
+	var row sqlSession
+	if err := q.db.WithContext(ctx).First(&row, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	values, err := decodeValues(row.Data)
+	if err != nil {
+		return nil, err
+	}
+	s := &SessionStore{ID: row.ID, LastAccess: row.LastAccess, Values: values}
+	if q.timeouts.expired(s, time.Now()) {
+		return nil, nil
+	}
+	return s, nil
+}
+
+func (q *SQLStore) Save(ctx context.Context, s *SessionStore) error {
+	data, err := encodeValues(s.Values)
+	if err != nil {
+		return err
+	}
+	row := sqlSession{ID: s.ID, Data: data, LastAccess: s.LastAccess, ExpiresAt: q.timeouts.expiresAt(s)}
+	if row.ExpiresAt.IsZero() {
+		row.ExpiresAt = neverExpires
+	}
+	row.UserID, _ = s.userID()
+	return q.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&row).Error
+}
+
+func (q *SQLStore) Delete(ctx context.Context, id string) error {
+	return q.db.WithContext(ctx).Delete(&sqlSession{}, "id = ?", id).Error
+}
+
+func (q *SQLStore) ListByUser(ctx context.Context, userID int64) ([]*SessionStore, error) {
+	var rows []sqlSession
+	err := q.db.WithContext(ctx).
+		Where("userId = ? AND expiresAt > ?", userID, time.Now()).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*SessionStore, 0, len(rows))
+	for _, row := range rows {
+		values, err := decodeValues(row.Data)
+		if err != nil {
+			continue
+		}
+		result = append(result, &SessionStore{ID: row.ID, LastAccess: row.LastAccess, Values: values})
+	}
+	return result, nil
+}
+
+// GC 删除已过期的会话
+func (q *SQLStore) GC(ctx context.Context) error {
+	return q.db.WithContext(ctx).
+		Where("expiresAt <= ?", time.Now()).
+		Delete(&sqlSession{}).Error
+}
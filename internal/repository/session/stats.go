@@ -0,0 +1,20 @@
+package session
+
+import (
+	"expvar"
+	"sync"
+)
+
+var publishOnce sync.Once
+
+// publishStats 通过 expvar 暴露进程内会话存储的指标（/debug/vars 中的 sessions 字段）
+func publishStats() {
+	publishOnce.Do(func() {
+		expvar.Publish("sessions", expvar.Func(func() interface{} {
+			if s, ok := store.(interface{ Stats() MemoryStats }); ok {
+				return s.Stats()
+			}
+			return nil
+		}))
+	})
+}
@@ -0,0 +1,67 @@
+package session
+
+import "time"
+
+// KeyRememberMe 登录时勾选"记住我"的会话使用更长的超时
+const KeyRememberMe = "rememberMe"
+
+// Timeouts 会话过期策略，内存、Redis、SQL 与 Cookie 的有效期均由此计算
+type Timeouts struct {
+	Idle             time.Duration // 空闲超时：超过该时间未访问即过期
+	Absolute         time.Duration // 绝对超时：自创建（登录）起的最长有效期，0 不限制
+	RememberIdle     time.Duration // "记住我"会话的空闲超时
+	RememberAbsolute time.Duration // "记住我"会话的绝对超时，0 不限制
+	RefreshInterval  time.Duration // 距上次刷新不足该间隔的请求不回写访问时间，0 每次都回写
+}
+
+// limits 会话适用的空闲与绝对超时
+func (t Timeouts) limits(s *SessionStore) (idle, absolute time.Duration) {
+	if remember, _ := s.Values[KeyRememberMe].(bool); remember && t.RememberIdle > 0 {
+		return t.RememberIdle, t.RememberAbsolute
+	}
+	return t.Idle, t.Absolute
+}
+
+// expiresAt 会话的过期时刻：空闲到期与绝对到期中较早者，零值表示永不过期
+func (t Timeouts) expiresAt(s *SessionStore) time.Time {
+	idle, absolute := t.limits(s)
+	var at time.Time
+	if idle > 0 {
+		at = s.LastAccess.Add(idle)
+	}
+	if created, ok := s.Values[KeyCreatedAt].(time.Time); ok && absolute > 0 {
+		if deadline := created.Add(absolute); at.IsZero() || deadline.Before(at) {
+			at = deadline
+		}
+	}
+	return at
+}
+
+// ttl 会话剩余有效期，永不过期时返回 0
+func (t Timeouts) ttl(s *SessionStore, now time.Time) time.Duration {
+	at := t.expiresAt(s)
+	if at.IsZero() {
+		return 0
+	}
+	return at.Sub(now)
+}
+
+// expired 会话在 now 时刻是否已过期
+func (t Timeouts) expired(s *SessionStore, now time.Time) bool {
+	at := t.expiresAt(s)
+	return !at.IsZero() && !at.After(now)
+}
+
+// longest 任意会话可能的最长有效期，用于用户会话索引等汇总数据的过期时间
+func (t Timeouts) longest() time.Duration {
+	longest := max(t.Idle, t.RememberIdle)
+	if t.Absolute == 0 || t.RememberAbsolute == 0 {
+		return longest
+	}
+	return min(longest, max(t.Absolute, t.RememberAbsolute))
+}
+
+// needsRefresh 本次访问是否需要回写访问时间
+func (t Timeouts) needsRefresh(s *SessionStore, now time.Time) bool {
+	return now.Sub(s.LastAccess) >= t.RefreshInterval
+}
@@ -0,0 +1,41 @@
+package token
+
+import "context"
+
+// Identity 通过访问令牌或 API Key 认证的调用方
+type Identity struct {
+	UserID      int64
+	UserAccount string
+	UserRole    int
+	APIKeyID    int64    // 通过 API Key 认证时的 Key ID
+	Scopes      []string // API Key 的权限范围，nil 表示不受限（会话、访问令牌）
+}
+
+// Allows 是否允许执行需要 scope 的操作；scope 为空表示该操作不对 API Key 开放
+func (i *Identity) Allows(scope string) bool {
+	if i.Scopes == nil {
+		return true
+	}
+	if scope == "" {
+		return false
+	}
+	for _, s := range i.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type identityKey struct{}
+
+// WithIdentity 把已认证的调用方写入 context
+func WithIdentity(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext 取出访问令牌认证的调用方，没有时返回 nil
+func FromContext(ctx context.Context) *Identity {
+	id, _ := ctx.Value(identityKey{}).(*Identity)
+	return id
+}
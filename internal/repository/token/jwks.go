@@ -0,0 +1,45 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+)
+
+// JWK 公钥的 JSON Web Key 表示
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"` // OKP
+	X   string `json:"x,omitempty"`   // OKP
+	N   string `json:"n,omitempty"`   // RSA
+	E   string `json:"e,omitempty"`   // RSA
+}
+
+// JWKSet /.well-known/jwks.json 的响应体
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS 密钥环中全部公钥，供其他服务离线校验访问令牌
+func (r *KeyRing) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(r.keys))}
+	enc := base64.RawURLEncoding
+	for _, key := range r.keys {
+		alg, _ := key.method()
+		jwk := JWK{Kid: key.ID, Use: "sig", Alg: alg}
+		switch public := key.Private.Public().(type) {
+		case ed25519.PublicKey:
+			jwk.Kty, jwk.Crv, jwk.X = "OKP", "Ed25519", enc.EncodeToString(public)
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = enc.EncodeToString(public.N.Bytes())
+			jwk.E = enc.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
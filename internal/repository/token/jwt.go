@@ -0,0 +1,103 @@
+package token
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidToken 令牌无效、过期或已被撤销
+var ErrInvalidToken = errors.New("invalid token")
+
+// claims 访问令牌的载荷，sub 为用户 ID
+type claims struct {
+	jwt.RegisteredClaims
+	Account string `json:"account"`
+	Role    int    `json:"role"`
+}
+
+// Issuer 签发与校验访问令牌
+type Issuer struct {
+	ring     *KeyRing
+	issuer   string
+	audience string
+	ttl      time.Duration
+}
+
+// NewIssuer 创建访问令牌签发器
+func NewIssuer(ring *KeyRing, issuer, audience string, ttl time.Duration) *Issuer {
+	return &Issuer{ring: ring, issuer: issuer, audience: audience, ttl: ttl}
+}
+
+// Issue 签发访问令牌，返回令牌与过期时间
+func (i *Issuer) Issue(id Identity) (string, time.Time, error) {
+	key := i.ring.signer()
+	alg, err := key.method()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	now := time.Now()
+	expiresAt := now.Add(i.ttl)
+	t := jwt.NewWithClaims(jwt.GetSigningMethod(alg), claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    i.issuer,
+			Subject:   strconv.FormatInt(id.UserID, 10),
+			Audience:  jwt.ClaimStrings{i.audience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        randomHex(16),
+		},
+		Account: id.UserAccount,
+		Role:    id.UserRole,
+	})
+	t.Header["kid"] = key.ID
+	signed, err := t.SignedString(key.Private)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// Verify 校验访问令牌的签名、签发方、受众与有效期
+func (i *Issuer) Verify(raw string) (*Identity, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(raw, &c, i.keyFunc,
+		jwt.WithValidMethods([]string{"EdDSA", "RS256"}),
+		jwt.WithIssuer(i.issuer),
+		jwt.WithAudience(i.audience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	userID, err := strconv.ParseInt(c.Subject, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad subject", ErrInvalidToken)
+	}
+	return &Identity{UserID: userID, UserAccount: c.Account, UserRole: c.Role}, nil
+}
+
+// keyFunc 按 kid 取校验公钥，并确认算法与密钥类型一致，防止算法混淆
+func (i *Issuer) keyFunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	key, ok := i.ring.lookup(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+	if alg, _ := key.method(); alg != t.Method.Alg() {
+		return nil, fmt.Errorf("alg %s does not match key %q", t.Method.Alg(), kid)
+	}
+	return key.Private.Public(), nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic("token: crypto/rand unavailable: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
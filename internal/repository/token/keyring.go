@@ -0,0 +1,109 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Key 签名密钥，ID 写入 JWT 头部的 kid
+type Key struct {
+	ID      string
+	Private crypto.Signer // ed25519.PrivateKey 或 *rsa.PrivateKey
+}
+
+// method 密钥对应的 JWT 签名算法
+func (k Key) method() (string, error) {
+	switch k.Private.(type) {
+	case ed25519.PrivateKey:
+		return "EdDSA", nil
+	case *rsa.PrivateKey:
+		return "RS256", nil
+	default:
+		return "", fmt.Errorf("unsupported key type %T", k.Private)
+	}
+}
+
+// LoadKey 读取 PEM 格式的私钥文件（PKCS#8，RSA 也可为 PKCS#1）
+func LoadKey(id, path string) (Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, fmt.Errorf("%s: no PEM block", path)
+	}
+	var parsed interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return Key{}, fmt.Errorf("%s: %w", path, err)
+	}
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return Key{}, fmt.Errorf("%s: not a signing key", path)
+	}
+	key := Key{ID: id, Private: signer}
+	if _, err := key.method(); err != nil {
+		return Key{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// GenerateKey 生成临时的 Ed25519 密钥，进程重启后签发的令牌全部失效
+func GenerateKey(id string) (Key, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{ID: id, Private: private}, nil
+}
+
+// KeyRing 密钥环：第一个密钥用于签名，全部密钥用于校验，轮换时把新密钥放在第一位
+type KeyRing struct {
+	keys []Key
+	byID map[string]Key
+}
+
+// NewKeyRing 创建密钥环
+func NewKeyRing(keys []Key) (*KeyRing, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("key ring requires at least one key")
+	}
+	ring := &KeyRing{keys: keys, byID: make(map[string]Key, len(keys))}
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("key without id")
+		}
+		if _, dup := ring.byID[key.ID]; dup {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		if _, err := key.method(); err != nil {
+			return nil, err
+		}
+		ring.byID[key.ID] = key
+	}
+	return ring, nil
+}
+
+// signer 当前用于签名的密钥
+func (r *KeyRing) signer() Key {
+	return r.keys[0]
+}
+
+// lookup 按 kid 查找校验用的密钥
+func (r *KeyRing) lookup(id string) (Key, bool) {
+	key, ok := r.byID[id]
+	return key, ok
+}
@@ -0,0 +1,175 @@
+package token
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+// ErrTokenReused 已轮换的刷新令牌被再次使用，整个令牌族随之撤销
+var ErrTokenReused = errors.New("refresh token reused")
+
+const (
+	refreshPrefix       = "refresh:"        // 刷新令牌摘要 -> {userId, maxRole, family, used}
+	refreshFamilyPrefix = "refresh_family:" // 令牌族 -> userId，删除即撤销整族
+	refreshUserPrefix   = "refresh_user:"   // 用户 -> 令牌族集合
+)
+
+// Grant 刷新令牌族所属的用户，以及登录时授予的最高角色：
+// 未启用两步验证的管理员登录时只获得普通用户权限，刷新后也不能超过该角色
+type Grant struct {
+	UserID  int64
+	MaxRole int
+}
+
+// RefreshStore 刷新令牌存储：只保存令牌摘要，每次刷新换发新令牌（同一令牌族），
+// 旧令牌保留到过期用于检测重放
+type RefreshStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRefreshStore 创建刷新令牌存储
+func NewRefreshStore(client *redis.Client, ttl time.Duration) *RefreshStore {
+	return &RefreshStore{client: client, ttl: ttl}
+}
+
+// Issue 为新登录创建令牌族并签发第一个刷新令牌
+func (r *RefreshStore) Issue(ctx context.Context, grant Grant) (string, error) {
+	family := randomHex(16)
+	uid := strconv.FormatInt(grant.UserID, 10)
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, refreshFamilyPrefix+family, uid, r.ttl)
+		pipe.SAdd(ctx, refreshUserPrefix+uid, family)
+		pipe.Expire(ctx, refreshUserPrefix+uid, r.ttl)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return r.issueInFamily(ctx, grant, family)
+}
+
+// Rotate 用刷新令牌换发新令牌（沿用令牌族的 Grant），返回所属用户；已使用过的令牌再次出现时撤销整个令牌族
+func (r *RefreshStore) Rotate(ctx context.Context, raw string) (Grant, string, error) {
+	key := refreshPrefix + digest(raw)
+	data, err := r.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return Grant{}, "", err
+	}
+	if len(data) == 0 {
+		return Grant{}, "", ErrInvalidToken
+	}
+	userID, _ := strconv.ParseInt(data["userId"], 10, 64)
+	family := data["family"]
+	// 没有 maxRole 的旧令牌按普通用户处理
+	maxRole, _ := strconv.Atoi(data["maxRole"])
+	grant := Grant{UserID: userID, MaxRole: maxRole}
+
+	// HINCRBY 保证并发刷新时只有一个请求能使用该令牌
+	used, err := r.client.HIncrBy(ctx, key, "used", 1).Result()
+	if err != nil {
+		return Grant{}, "", err
+	}
+	if used > 1 {
+		if err := r.revokeFamily(ctx, userID, family); err != nil {
+			return Grant{}, "", err
+		}
+		return Grant{}, "", ErrTokenReused
+	}
+
+	alive, err := r.client.Exists(ctx, refreshFamilyPrefix+family).Result()
+	if err != nil {
+		return Grant{}, "", err
+	}
+	if alive == 0 {
+		return Grant{}, "", ErrInvalidToken
+	}
+	next, err := r.issueInFamily(ctx, grant, family)
+	if err != nil {
+		return Grant{}, "", err
+	}
+	return grant, next, nil
+}
+
+// Revoke 撤销刷新令牌所属的令牌族（退出登录）
+func (r *RefreshStore) Revoke(ctx context.Context, raw string) error {
+	data, err := r.client.HGetAll(ctx, refreshPrefix+digest(raw)).Result()
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	userID, _ := strconv.ParseInt(data["userId"], 10, 64)
+	return r.revokeFamily(ctx, userID, data["family"])
+}
+
+// RevokeUser 撤销用户的全部令牌族，返回撤销数量
+func (r *RefreshStore) RevokeUser(ctx context.Context, userID int64) (int, error) {
+	userKey := refreshUserPrefix + strconv.FormatInt(userID, 10)
+	families, err := r.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	if len(families) == 0 {
+		return 0, nil
+	}
+	keys := make([]string, 0, len(families))
+	for _, family := range families {
+		keys = append(keys, refreshFamilyPrefix+family)
+	}
+	n, err := r.client.Del(ctx, keys...).Result()
+	if err != nil {
+		return 0, err
+	}
+	r.client.Del(ctx, userKey)
+	return int(n), nil
+}
+
+func (r *RefreshStore) issueInFamily(ctx context.Context, grant Grant, family string) (string, error) {
+	raw := randomToken()
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		key := refreshPrefix + digest(raw)
+		pipe.HSet(ctx, key, map[string]interface{}{
+			"userId":  grant.UserID,
+			"maxRole": grant.MaxRole,
+			"family":  family,
+			"used":    0,
+		})
+		pipe.Expire(ctx, key, r.ttl)
+		// 令牌族随每次刷新续期
+		pipe.Expire(ctx, refreshFamilyPrefix+family, r.ttl)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+func (r *RefreshStore) revokeFamily(ctx context.Context, userID int64, family string) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, refreshFamilyPrefix+family)
+		pipe.SRem(ctx, refreshUserPrefix+strconv.FormatInt(userID, 10), family)
+		return nil
+	})
+	return err
+}
+
+// digest Redis 中只保存刷新令牌的摘要，泄露存储内容不会泄露可用的令牌
+func digest(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic("token: crypto/rand unavailable: " + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,108 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// Options 令牌配置
+type Options struct {
+	Keys       []Key         // 签名密钥，第一个用于签名
+	Issuer     string        // iss
+	Audience   string        // aud
+	AccessTTL  time.Duration // 访问令牌有效期
+	RefreshTTL time.Duration // 刷新令牌有效期，每次刷新后重新计算
+	Client     *redis.Client // 刷新令牌存储
+}
+
+// Pair 登录或刷新后返回给客户端的令牌
+type Pair struct {
+	AccessToken  string    `json:"accessToken"`
+	RefreshToken string    `json:"refreshToken"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	TokenType    string    `json:"tokenType"`
+}
+
+var (
+	issuer  *Issuer       // 为空表示未开启令牌认证
+	refresh *RefreshStore // 刷新令牌存储
+	ring    *KeyRing
+)
+
+// Setup 开启令牌认证
+func Setup(opts Options) error {
+	r, err := NewKeyRing(opts.Keys)
+	if err != nil {
+		return err
+	}
+	if opts.Client == nil {
+		return errors.New("token: redis client required")
+	}
+	ring = r
+	issuer = NewIssuer(r, opts.Issuer, opts.Audience, opts.AccessTTL)
+	refresh = NewRefreshStore(opts.Client, opts.RefreshTTL)
+	return nil
+}
+
+// Enabled 是否已开启令牌认证
+func Enabled() bool {
+	return issuer != nil
+}
+
+// IssuePair 为用户签发访问令牌与新的刷新令牌族，id.UserRole 作为令牌族之后能获得的最高角色
+func IssuePair(ctx context.Context, id Identity) (*Pair, error) {
+	raw, err := refresh.Issue(ctx, Grant{UserID: id.UserID, MaxRole: id.UserRole})
+	if err != nil {
+		return nil, err
+	}
+	return pairWith(id, raw)
+}
+
+// Rotate 用刷新令牌换发新的刷新令牌，返回令牌族的 Grant，调用方查询最新的用户信息后再调用 Complete
+func Rotate(ctx context.Context, refreshToken string) (Grant, string, error) {
+	return refresh.Rotate(ctx, refreshToken)
+}
+
+// Complete 为换发的刷新令牌配上新的访问令牌
+func Complete(id Identity, refreshToken string) (*Pair, error) {
+	return pairWith(id, refreshToken)
+}
+
+// Verify 校验访问令牌
+func Verify(raw string) (*Identity, error) {
+	if issuer == nil {
+		return nil, ErrInvalidToken
+	}
+	return issuer.Verify(raw)
+}
+
+// RevokeRefresh 撤销刷新令牌所属的令牌族
+func RevokeRefresh(ctx context.Context, refreshToken string) error {
+	return refresh.Revoke(ctx, refreshToken)
+}
+
+// RevokeUser 撤销用户的全部刷新令牌；已签发的访问令牌在短有效期内仍然有效
+func RevokeUser(ctx context.Context, userID int64) (int, error) {
+	if refresh == nil {
+		return 0, nil
+	}
+	return refresh.RevokeUser(ctx, userID)
+}
+
+// Keys 公钥集合
+func Keys() JWKSet {
+	if ring == nil {
+		return JWKSet{Keys: []JWK{}}
+	}
+	return ring.JWKS()
+}
+
+func pairWith(id Identity, refreshToken string) (*Pair, error) {
+	access, expiresAt, err := issuer.Issue(id)
+	if err != nil {
+		return nil, err
+	}
+	return &Pair{AccessToken: access, RefreshToken: refreshToken, ExpiresAt: expiresAt, TokenType: "Bearer"}, nil
+}
@@ -0,0 +1,213 @@
+package userexport
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"http_grpc/internal/repository/model"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 支持的文件格式。只提供逐行写出的 CSV 与 NDJSON，Parquet 等列式格式需要整批缓冲后按列编码，
+// 与逐行流式导出的方式不符，不在支持范围内，需要时由下游从 NDJSON 转换
+const (
+	FormatCSV    = "csv"    // 首行为表头，列名与 JSON 字段名相同
+	FormatNDJSON = "ndjson" // 每行一个 JSON 对象，只包含选择的列
+)
+
+// Columns 可导出的字段，也是默认的列顺序；密码摘要不在其中，任何情况下都不导出
+var Columns = []string{
+	"id", "userAccount", "username", "avatarUrl", "gender", "phone", "email", "emailVerified",
+	"userStatus", "userRole", "planetCode", "createTime", "updateTime",
+}
+
+// Options 用户导出配置
+type Options struct {
+	MaxConcurrent int // 本实例同时执行的导出数，默认 2
+	FlushRows     int // 每写出多少行把缓冲内容发送给客户端，默认 100
+}
+
+var (
+	opts  = Options{MaxConcurrent: 2, FlushRows: 100}
+	slots = make(chan struct{}, opts.MaxConcurrent)
+)
+
+// Setup 设置导出参数，未设置的字段使用默认值
+func Setup(o Options) {
+	if o.MaxConcurrent <= 0 {
+		o.MaxConcurrent = 2
+	}
+	if o.FlushRows <= 0 {
+		o.FlushRows = 100
+	}
+	opts = o
+	slots = make(chan struct{}, o.MaxConcurrent)
+}
+
+// FlushRows 每写出多少行发送一次
+func FlushRows() int {
+	return opts.FlushRows
+}
+
+// TryAcquire 占用一个导出名额，没有空闲名额时返回 false；成功后需调用 Release
+func TryAcquire() bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Release 归还导出名额
+func Release() {
+	<-slots
+}
+
+// ParseColumns 不区分大小写解析列名并去重，为空时返回全部列
+func ParseColumns(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return append([]string(nil), Columns...), nil
+	}
+	columns := make([]string, 0, len(names))
+	seen := map[string]bool{}
+	for _, name := range names {
+		column := canonicalColumn(name)
+		if column == "" {
+			return nil, fmt.Errorf("unknown column %q, allowed: %s", name, strings.Join(Columns, ", "))
+		}
+		if !seen[column] {
+			seen[column] = true
+			columns = append(columns, column)
+		}
+	}
+	return columns, nil
+}
+
+// Writer 逐行写出用户，结束或需要把数据发送给客户端时调用 Flush
+type Writer interface {
+	Write(u *model.User) error
+	Flush() error
+}
+
+// NewWriter 创建对应格式的写入器，CSV 会先写出表头
+func NewWriter(format string, w io.Writer, columns []string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return nil, err
+		}
+		return &csvWriter{w: cw, columns: columns, record: make([]string, len(columns))}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{w: bufio.NewWriter(w), columns: columns}, nil
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	columns []string
+	record  []string
+}
+
+func (c *csvWriter) Write(u *model.User) error {
+	for i, column := range c.columns {
+		switch v := Value(u, column).(type) {
+		case string:
+			c.record[i] = v
+		case int64:
+			c.record[i] = strconv.FormatInt(v, 10)
+		case int:
+			c.record[i] = strconv.Itoa(v)
+		case int8:
+			c.record[i] = strconv.Itoa(int(v))
+		case bool:
+			c.record[i] = strconv.FormatBool(v)
+		case time.Time:
+			c.record[i] = v.Format(time.RFC3339)
+		}
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonWriter struct {
+	w       *bufio.Writer
+	columns []string
+}
+
+// Write 按列顺序手动拼接对象，字段顺序与 CSV 表头一致
+func (n *ndjsonWriter) Write(u *model.User) error {
+	n.w.WriteByte('{')
+	for i, column := range n.columns {
+		if i > 0 {
+			n.w.WriteByte(',')
+		}
+		value, err := json.Marshal(Value(u, column))
+		if err != nil {
+			return err
+		}
+		n.w.WriteString(strconv.Quote(column))
+		n.w.WriteByte(':')
+		n.w.Write(value)
+	}
+	n.w.WriteString("}\n")
+	return nil
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return n.w.Flush()
+}
+
+// Value 读取用户的某一列
+func Value(u *model.User, column string) interface{} {
+	switch column {
+	case "id":
+		return u.ID
+	case "userAccount":
+		return u.UserAccount
+	case "username":
+		return u.Username
+	case "avatarUrl":
+		return u.AvatarUrl
+	case "gender":
+		return u.Gender
+	case "phone":
+		return u.Phone
+	case "email":
+		return u.Email
+	case "emailVerified":
+		return u.EmailVerified
+	case "userStatus":
+		return u.UserStatus
+	case "userRole":
+		return u.UserRole
+	case "planetCode":
+		return u.PlanetCode
+	case "createTime":
+		return u.CreateTime
+	case "updateTime":
+		return u.UpdateTime
+	}
+	return nil
+}
+
+// canonicalColumn 不区分大小写匹配字段名，未知字段返回空字符串
+func canonicalColumn(name string) string {
+	name = strings.TrimSpace(name)
+	for _, column := range Columns {
+		if strings.EqualFold(column, name) {
+			return column
+		}
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+package userimport
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// Options 用户导入配置
+type Options struct {
+	BatchSize     int    // 每个事务写入的行数，默认 100
+	MaxRows       int    // 单次导入的行数上限，默认 100000
+	MaxBytes      int64  // 上传文件的大小上限，默认 32MB
+	SyncMaxBytes  int64  // 不超过该大小的文件同步导入并直接返回结果，更大的文件作为后台任务，默认 1MB
+	MaxConcurrent int    // 本实例同时执行的导入数（含同步导入与后台任务），默认 2
+	TempDir       string // 上传文件的暂存目录，为空时使用系统临时目录
+}
+
+var (
+	opts  = Options{BatchSize: 100, MaxRows: 100000, MaxBytes: 32 << 20, SyncMaxBytes: 1 << 20, MaxConcurrent: 2}
+	slots = make(chan struct{}, opts.MaxConcurrent)
+)
+
+// ErrTooLarge 上传文件超过 MaxBytes
+var ErrTooLarge = errors.New("import file is too large")
+
+// Setup 设置导入参数，未设置的字段使用默认值
+func Setup(o Options) {
+	if o.BatchSize <= 0 {
+		o.BatchSize = 100
+	}
+	if o.MaxRows <= 0 {
+		o.MaxRows = 100000
+	}
+	if o.MaxBytes <= 0 {
+		o.MaxBytes = 32 << 20
+	}
+	if o.SyncMaxBytes <= 0 {
+		o.SyncMaxBytes = 1 << 20
+	}
+	if o.MaxConcurrent <= 0 {
+		o.MaxConcurrent = 2
+	}
+	opts = o
+	slots = make(chan struct{}, o.MaxConcurrent)
+}
+
+// BatchSize 每个事务写入的行数
+func BatchSize() int {
+	return opts.BatchSize
+}
+
+// MaxRows 单次导入的行数上限
+func MaxRows() int {
+	return opts.MaxRows
+}
+
+// SyncMaxBytes 同步导入的文件大小上限
+func SyncMaxBytes() int64 {
+	return opts.SyncMaxBytes
+}
+
+// TryAcquire 占用一个导入名额，没有空闲名额时返回 false；成功后需调用 Release
+func TryAcquire() bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Release 归还导入名额
+func Release() {
+	<-slots
+}
+
+// Spool 把上传内容写入暂存文件并返回路径与大小，超过 MaxBytes 时返回 ErrTooLarge；
+// 文件由调用方在导入结束后删除
+func Spool(r io.Reader) (string, int64, error) {
+	f, err := os.CreateTemp(opts.TempDir, "user-import-*")
+	if err != nil {
+		return "", 0, err
+	}
+	n, err := io.Copy(f, io.LimitReader(r, opts.MaxBytes+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && n > opts.MaxBytes {
+		err = ErrTooLarge
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", 0, err
+	}
+	return f.Name(), n, nil
+}
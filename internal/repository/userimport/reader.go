@@ -0,0 +1,235 @@
+package userimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"http_grpc/internal/repository/model"
+	"io"
+	"mime"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 支持的文件格式
+const (
+	FormatCSV    = "csv"    // 首行为表头，列名与 JSON 字段名相同（不区分大小写）
+	FormatNDJSON = "ndjson" // 每行一个 JSON 对象
+)
+
+// Columns 可导入的字段，其余字段（角色、状态等）使用默认值
+var Columns = []string{"userAccount", "userPassword", "username", "avatarUrl", "gender", "phone", "email"}
+
+// maxLineBytes NDJSON 单行的长度上限
+const maxLineBytes = 1 << 20
+
+// Record 读取到的一行。Err 非空表示该行无法解析，只影响这一行
+type Record struct {
+	Row  int // 第几条记录，从 1 开始，不含表头与空行
+	Line int // 在文件中的行号，流式导入时为 0
+	User model.User
+	Err  error
+}
+
+// Reader 逐行读取导入数据，读完时返回 io.EOF；返回其他错误时无法继续读取
+type Reader interface {
+	Next() (Record, error)
+}
+
+// DetectFormat 按 format 参数、Content-Type 或文件扩展名判断格式，无法判断时返回空字符串
+func DetectFormat(format, contentType, filename string) string {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return FormatCSV
+	case FormatNDJSON, "jsonl":
+		return FormatNDJSON
+	case "":
+	default:
+		return ""
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch mediaType {
+		case "text/csv", "application/csv":
+			return FormatCSV
+		case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines":
+			return FormatNDJSON
+		}
+	}
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON
+	}
+	return ""
+}
+
+// NewReader 创建对应格式的读取器，CSV 会先读取并校验表头
+func NewReader(format string, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatNDJSON:
+		s := bufio.NewScanner(r)
+		s.Buffer(make([]byte, 64*1024), maxLineBytes)
+		return &ndjsonReader{scanner: s}, nil
+	}
+	return nil, fmt.Errorf("unsupported format %q, must be csv or ndjson", format)
+}
+
+type csvReader struct {
+	r       *csv.Reader
+	columns []string // 每列对应的字段名
+	row     int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("csv header is missing")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv header: %w", err)
+	}
+	columns := make([]string, len(header))
+	seen := map[string]bool{}
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		column := canonicalColumn(strings.TrimSpace(name))
+		if column == "" {
+			return nil, fmt.Errorf("unknown csv column %q, allowed: %s", name, strings.Join(Columns, ", "))
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("duplicate csv column %q", name)
+		}
+		seen[column] = true
+		columns[i] = column
+	}
+	if !seen["userAccount"] {
+		return nil, errors.New("csv header must include userAccount")
+	}
+	return &csvReader{r: cr, columns: columns}, nil
+}
+
+func (c *csvReader) Next() (Record, error) {
+	fields, err := c.r.Read()
+	if errors.Is(err, io.EOF) {
+		return Record{}, io.EOF
+	}
+	c.row++
+	line, _ := c.r.FieldPos(0)
+	rec := Record{Row: c.row, Line: line}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		rec.Line, rec.Err = parseErr.Line, errors.New(parseErr.Err.Error())
+		return rec, nil
+	}
+	if err != nil {
+		return Record{}, err
+	}
+	for i, value := range fields {
+		if err := setField(&rec.User, c.columns[i], strings.TrimSpace(value)); err != nil {
+			rec.Err = err
+			break
+		}
+	}
+	return rec, nil
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	row     int
+	line    int
+}
+
+// ndjsonUser NDJSON 中一行的字段，gender 为数字
+type ndjsonUser struct {
+	UserAccount  string `json:"userAccount"`
+	UserPassword string `json:"userPassword"`
+	Username     string `json:"username"`
+	AvatarUrl    string `json:"avatarUrl"`
+	Gender       int8   `json:"gender"`
+	Phone        string `json:"phone"`
+	Email        string `json:"email"`
+}
+
+func (n *ndjsonReader) Next() (Record, error) {
+	for n.scanner.Scan() {
+		n.line++
+		text := bytes.TrimSpace(n.scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		n.row++
+		rec := Record{Row: n.row, Line: n.line}
+		var u ndjsonUser
+		dec := json.NewDecoder(bytes.NewReader(text))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&u); err != nil {
+			rec.Err = fmt.Errorf("invalid json: %v", err)
+			return rec, nil
+		}
+		rec.User = model.User{
+			UserAccount:  u.UserAccount,
+			UserPassword: u.UserPassword,
+			Username:     u.Username,
+			AvatarUrl:    u.AvatarUrl,
+			Gender:       u.Gender,
+			Phone:        u.Phone,
+			Email:        u.Email,
+		}
+		return rec, nil
+	}
+	if err := n.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return Record{}, fmt.Errorf("line %d is longer than %d bytes", n.line+1, maxLineBytes)
+		}
+		return Record{}, err
+	}
+	return Record{}, io.EOF
+}
+
+// canonicalColumn 不区分大小写匹配字段名，未知字段返回空字符串
+func canonicalColumn(name string) string {
+	for _, column := range Columns {
+		if strings.EqualFold(column, name) {
+			return column
+		}
+	}
+	return ""
+}
+
+func setField(u *model.User, column, value string) error {
+	switch column {
+	case "userAccount":
+		u.UserAccount = value
+	case "userPassword":
+		u.UserPassword = value
+	case "username":
+		u.Username = value
+	case "avatarUrl":
+		u.AvatarUrl = value
+	case "gender":
+		if value == "" {
+			return nil
+		}
+		gender, err := strconv.ParseInt(value, 10, 8)
+		if err != nil {
+			return errors.New("gender must be an integer")
+		}
+		u.Gender = int8(gender)
+	case "phone":
+		u.Phone = value
+	case "email":
+		u.Email = value
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package usertoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Options 邮件中一次性令牌的配置
+type Options struct {
+	ResetTTL  time.Duration // 重置密码令牌有效期
+	VerifyTTL time.Duration // 验证邮箱令牌有效期
+	ResetURL  string        // 重置密码页面地址，{token} 替换为令牌
+	VerifyURL string        // 验证邮箱页面地址，{token} 替换为令牌
+}
+
+var options = Options{
+	ResetTTL:  30 * time.Minute,
+	VerifyTTL: 48 * time.Hour,
+	ResetURL:  "http://localhost:8080/reset-password?token={token}",
+	VerifyURL: "http://localhost:8080/verify-email?token={token}",
+}
+
+// Setup 设置令牌配置，未设置的项使用默认值
+func Setup(opts Options) {
+	if opts.ResetTTL <= 0 {
+		opts.ResetTTL = options.ResetTTL
+	}
+	if opts.VerifyTTL <= 0 {
+		opts.VerifyTTL = options.VerifyTTL
+	}
+	if opts.ResetURL == "" {
+		opts.ResetURL = options.ResetURL
+	}
+	if opts.VerifyURL == "" {
+		opts.VerifyURL = options.VerifyURL
+	}
+	options = opts
+}
+
+// New 生成令牌，raw 发给用户，hash 存入数据库
+func New() (raw, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	raw = base64.RawURLEncoding.EncodeToString(b)
+	return raw, Hash(raw), nil
+}
+
+// Hash 令牌摘要
+func Hash(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// ResetTTL 重置密码令牌有效期
+func ResetTTL() time.Duration {
+	return options.ResetTTL
+}
+
+// VerifyTTL 验证邮箱令牌有效期
+func VerifyTTL() time.Duration {
+	return options.VerifyTTL
+}
+
+// ResetLink 邮件中的重置密码链接
+func ResetLink(raw string) string {
+	return link(options.ResetURL, raw)
+}
+
+// VerifyLink 邮件中的验证邮箱链接
+func VerifyLink(raw string) string {
+	return link(options.VerifyURL, raw)
+}
+
+func link(template, raw string) string {
+	return strings.ReplaceAll(template, "{token}", url.QueryEscape(raw))
+}
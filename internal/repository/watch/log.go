@@ -0,0 +1,122 @@
+package watch
+
+import (
+	"http_grpc/internal/repository/outbox"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Change 日志中的一条用户变更，ID 为它在日志中的位置，断线重连时作为恢复点
+type Change struct {
+	ID    string
+	Event outbox.Event
+}
+
+// position 日志位置，格式与 Redis Stream ID 相同（<毫秒>-<序号>），可按大小比较
+type position struct {
+	ms, seq uint64
+}
+
+func parsePosition(id string) (position, bool) {
+	ms, seq, ok := strings.Cut(id, "-")
+	if !ok {
+		return position{}, false
+	}
+	var p position
+	var err error
+	if p.ms, err = strconv.ParseUint(ms, 10, 64); err != nil {
+		return position{}, false
+	}
+	if p.seq, err = strconv.ParseUint(seq, 10, 64); err != nil {
+		return position{}, false
+	}
+	return p, true
+}
+
+func (p position) String() string {
+	return strconv.FormatUint(p.ms, 10) + "-" + strconv.FormatUint(p.seq, 10)
+}
+
+func (p position) less(q position) bool {
+	return p.ms < q.ms || (p.ms == q.ms && p.seq < q.seq)
+}
+
+type entry struct {
+	pos    position
+	change Change
+}
+
+// ring 有界的变更日志，保留最近 size 条；新变更写入时唤醒等待中的观察者
+type ring struct {
+	mu     sync.Mutex
+	size   int
+	items  []entry
+	floor  position // 可恢复的最早位置：最后一条被淘汰的变更，或日志的起点
+	last   position // 最新位置
+	notify chan struct{}
+}
+
+func newRing(size int, start position) *ring {
+	return &ring{size: size, floor: start, last: start, notify: make(chan struct{})}
+}
+
+// append 写入指定位置的变更，不晚于最新位置的变更视为重复并忽略
+func (r *ring) append(p position, e outbox.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.last.less(p) {
+		return
+	}
+	r.push(p, e)
+}
+
+// appendNext 以最新位置的下一个序号写入变更，用于内存日志
+func (r *ring) appendNext(e outbox.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.push(position{ms: r.last.ms, seq: r.last.seq + 1}, e)
+}
+
+func (r *ring) push(p position, e outbox.Event) {
+	r.items = append(r.items, entry{pos: p, change: Change{ID: p.String(), Event: e}})
+	if len(r.items) > r.size {
+		r.floor = r.items[0].pos
+		r.items = r.items[1:]
+	}
+	r.last = p
+	close(r.notify)
+	r.notify = make(chan struct{})
+}
+
+// latest 当前最新位置
+func (r *ring) latest() position {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+// valid 位置是否仍可恢复：之后的变更全部还在日志中
+func (r *ring) valid(p position) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !p.less(r.floor) && !r.last.less(p)
+}
+
+// since 返回 p 之后的变更；没有新变更时返回的通道在下一次写入时关闭。
+// p 已不可恢复时 ok 为 false
+func (r *ring) since(p position) (changes []entry, wake <-chan struct{}, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p.less(r.floor) || r.last.less(p) {
+		return nil, nil, false
+	}
+	i := len(r.items)
+	for i > 0 && p.less(r.items[i-1].pos) {
+		i--
+	}
+	if i == len(r.items) {
+		return nil, r.notify, true
+	}
+	return append([]entry(nil), r.items[i:]...), nil, true
+}
@@ -0,0 +1,88 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"http_grpc/internal/repository/outbox"
+	"log"
+	"time"
+)
+
+// tailBlock 读取新变更时每次阻塞的时长
+const tailBlock = 5 * time.Second
+
+// appendStream 把变更追加到 Stream，按近似长度裁剪到 Size 条
+func appendStream(ctx context.Context, e outbox.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return opts.Client.XAdd(ctx, &redis.XAddArgs{
+		Stream: opts.Stream,
+		MaxLen: int64(opts.Size),
+		Approx: true,
+		Values: map[string]interface{}{"event": string(payload)},
+	}).Err()
+}
+
+// preload 载入 Stream 中最近 Size 条变更，使重启后的实例也能为断线的观察者恢复
+func preload(ctx context.Context) error {
+	messages, err := opts.Client.XRevRangeN(ctx, opts.Stream, "+", "-", int64(opts.Size)).Result()
+	if err != nil {
+		return err
+	}
+	start := position{}
+	// Stream 已被裁剪时，最早一条之前的位置不可恢复
+	if len(messages) == opts.Size {
+		start, _ = parsePosition(messages[len(messages)-1].ID)
+	}
+	l := newRing(opts.Size, start)
+	for i := len(messages) - 1; i >= 0; i-- {
+		appendMessage(l, messages[i])
+	}
+	history = l
+	return nil
+}
+
+// tail 持续读取 Stream 中的新变更写入本实例的日志，每个实例只占用一个 Redis 连接
+func tail(ctx context.Context) {
+	for ctx.Err() == nil {
+		streams, err := opts.Client.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{opts.Stream, history.latest().String()},
+			Count:   100,
+			Block:   tailBlock,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Failed to read watch stream: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		for _, s := range streams {
+			for _, msg := range s.Messages {
+				appendMessage(history, msg)
+			}
+		}
+	}
+}
+
+func appendMessage(l *ring, msg redis.XMessage) {
+	p, ok := parsePosition(msg.ID)
+	if !ok {
+		return
+	}
+	payload, _ := msg.Values["event"].(string)
+	e, err := outbox.Decode([]byte(payload))
+	if err != nil {
+		log.Printf("Dropping malformed watch event %s: %v", msg.ID, err)
+		return
+	}
+	l.append(p, e)
+}
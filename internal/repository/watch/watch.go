@@ -0,0 +1,220 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"http_grpc/internal/repository/outbox"
+	"sync/atomic"
+	"time"
+)
+
+// 变更日志的存储方式
+const (
+	BackendMemory = "memory" // 只保存在本实例，重启后清空
+	BackendRedis  = "redis"  // 写入 Redis Stream，各实例读取后推送，重启后可继续恢复
+)
+
+// DefaultStream Redis 模式下的变更日志 Stream
+const DefaultStream = "user_watch"
+
+// 推送的消息类型
+const (
+	KindChange    = "change"    // 用户变更
+	KindHeartbeat = "heartbeat" // 心跳，ID 为当前位置
+	KindReset     = "reset"     // 恢复点已不在日志中，调用方应重新拉取列表
+)
+
+// Types 推送的事件类型
+var Types = []string{outbox.TypeUserCreated, outbox.TypeUserUpdated, outbox.TypeUserDeleted}
+
+var (
+	ErrTooManyWatchers = errors.New("too many watchers")
+	ErrClosed          = errors.New("watch closed")
+)
+
+// Options 用户变更推送配置
+type Options struct {
+	Backend     string        // memory | redis，默认 memory
+	Client      *redis.Client // redis 模式使用
+	Stream      string        // redis 模式的 Stream 名称，默认 user_watch
+	Size        int           // 保留的变更条数，决定断线后可恢复的范围，默认 1000
+	Heartbeat   time.Duration // 心跳间隔，默认 15s
+	MaxWatchers int           // 本实例同时连接的观察者上限，默认 100
+}
+
+var (
+	opts     = Options{Backend: BackendMemory, Size: 1000, Heartbeat: 15 * time.Second, MaxWatchers: 100}
+	history  = newRing(opts.Size, position{ms: uint64(time.Now().UnixMilli())})
+	done     = make(chan struct{})
+	watchers int32
+)
+
+// Setup 设置推送参数，未设置的字段使用默认值；需在 Start 之前调用
+func Setup(o Options) error {
+	switch o.Backend {
+	case "":
+		o.Backend = BackendMemory
+	case BackendMemory:
+	case BackendRedis:
+		if o.Client == nil {
+			return errors.New("watch: redis backend requires a client")
+		}
+	default:
+		return fmt.Errorf("unknown watch backend %q", o.Backend)
+	}
+	if o.Stream == "" {
+		o.Stream = DefaultStream
+	}
+	if o.Size <= 0 {
+		o.Size = 1000
+	}
+	if o.Heartbeat <= 0 {
+		o.Heartbeat = 15 * time.Second
+	}
+	if o.MaxWatchers <= 0 {
+		o.MaxWatchers = 100
+	}
+	opts = o
+	// 内存日志以启动时间作为位置的毫秒部分，重启前的恢复点都早于日志起点
+	history = newRing(o.Size, position{ms: uint64(time.Now().UnixMilli())})
+	return nil
+}
+
+// Start 开始接收变更：redis 模式下先载入 Stream 中最近的变更，再持续读取新变更。
+// ctx 结束时全部观察者返回 ErrClosed，避免长连接拖慢停机
+func Start(ctx context.Context) error {
+	if opts.Backend == BackendRedis {
+		if err := preload(ctx); err != nil {
+			return err
+		}
+		go tail(ctx)
+	}
+	closed := make(chan struct{})
+	done = closed
+	go func() {
+		<-ctx.Done()
+		close(closed)
+	}()
+	return nil
+}
+
+// Publisher 把用户的创建、修改、删除事件写入变更日志，作为 outbox 的发布方式之一；
+// 中继重试时可能重复写入，观察者可按事件 ID 去重
+type Publisher struct{}
+
+func (Publisher) Publish(ctx context.Context, e outbox.Event) error {
+	if !watched(e.Type) {
+		return nil
+	}
+	if opts.Backend == BackendRedis {
+		return appendStream(ctx, e)
+	}
+	history.appendNext(e)
+	return nil
+}
+
+// Filter 推送条件，零值表示不限制
+type Filter struct {
+	Types   []string
+	UserIDs []int64
+}
+
+// Match 事件是否满足条件
+func (f Filter) Match(e outbox.Event) bool {
+	if len(f.Types) > 0 && !contains(f.Types, e.Type) {
+		return false
+	}
+	if len(f.UserIDs) == 0 {
+		return true
+	}
+	for _, id := range f.UserIDs {
+		if id == e.UserID {
+			return true
+		}
+	}
+	return false
+}
+
+// Message 推送给观察者的消息，Kind 为 KindChange 时 Event 有值
+type Message struct {
+	Kind  string
+	ID    string
+	Event *outbox.Event
+}
+
+// Watch 推送 lastID 之后满足条件的变更，直到 ctx 结束、服务停机（返回 ErrClosed）或 send 返回错误。
+// lastID 为空时从当前位置开始；第一条消息是心跳，lastID 已不可恢复时改为 reset。
+// 被条件过滤的变更同样推进位置，心跳携带当前位置供重连使用
+func Watch(ctx context.Context, lastID string, filter Filter, send func(Message) error) error {
+	if n := atomic.AddInt32(&watchers, 1); int(n) > opts.MaxWatchers {
+		atomic.AddInt32(&watchers, -1)
+		return ErrTooManyWatchers
+	}
+	defer atomic.AddInt32(&watchers, -1)
+
+	l, closed := history, done
+	cursor, first := l.latest(), KindHeartbeat
+	if lastID != "" {
+		if p, ok := parsePosition(lastID); ok && l.valid(p) {
+			cursor = p
+		} else {
+			first = KindReset
+		}
+	}
+	if err := send(Message{Kind: first, ID: cursor.String()}); err != nil {
+		return err
+	}
+
+	heartbeat := time.NewTicker(opts.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		changes, wake, ok := l.since(cursor)
+		if !ok {
+			// 观察者落后太多，未读的变更已被淘汰
+			cursor = l.latest()
+			if err := send(Message{Kind: KindReset, ID: cursor.String()}); err != nil {
+				return err
+			}
+			continue
+		}
+		for i := range changes {
+			cursor = changes[i].pos
+			if !filter.Match(changes[i].change.Event) {
+				continue
+			}
+			c := changes[i].change
+			if err := send(Message{Kind: KindChange, ID: c.ID, Event: &c.Event}); err != nil {
+				return err
+			}
+		}
+		if len(changes) > 0 {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-closed:
+			return ErrClosed
+		case <-wake:
+		case <-heartbeat.C:
+			if err := send(Message{Kind: KindHeartbeat, ID: cursor.String()}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func watched(eventType string) bool {
+	return contains(Types, eventType)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,359 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/outbox"
+	"http_grpc/pkg/pool"
+	"io"
+	"log"
+	mathrand "math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TypeTest 测试投递使用的事件类型，只发给被测试的 Webhook
+const TypeTest = "webhook.test"
+
+// maxResponseBody 投递日志中保存的响应内容长度
+const maxResponseBody = 1024
+
+const (
+	// leaseTimeout 发送记录写入后仍未完成的最长时间，超过后视为实例已退出或任务被丢弃，重新发送
+	leaseTimeout = 10 * time.Minute
+	// queueFullDelay 协程池队列已满时推迟发送的时间
+	queueFullDelay = 30 * time.Second
+	// scanInterval 检查到期重试的间隔
+	scanInterval = 5 * time.Second
+	// scanBatchSize 每次检查最多处理的到期记录数
+	scanBatchSize = 100
+)
+
+// Options Webhook 投递配置
+type Options struct {
+	Workers     int           // 专用协程池的协程数，默认 4
+	QueueSize   int           // 专用协程池的队列长度，队列满时推迟发送，默认 100
+	Timeout     time.Duration // 单次请求超时，默认 10s
+	MaxAttempts int           // 最多发送次数（含首次），默认 6
+	BaseDelay   time.Duration // 首次重试的等待时间，之后翻倍，默认 30s
+	MaxDelay    time.Duration // 重试等待上限，默认 1h
+}
+
+var (
+	opts   Options
+	client *http.Client
+	// workers 只执行投递，接收方无响应时不会占用处理请求与中继使用的协程池
+	workers *pool.RoutinePool
+)
+
+func init() {
+	Setup(Options{})
+}
+
+// Setup 设置投递参数并创建专用协程池，未设置的字段使用默认值
+func Setup(o Options) {
+	if o.Workers <= 0 {
+		o.Workers = 4
+	}
+	if o.QueueSize <= 0 {
+		o.QueueSize = 100
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 10 * time.Second
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 6
+	}
+	if o.BaseDelay <= 0 {
+		o.BaseDelay = 30 * time.Second
+	}
+	if o.MaxDelay <= 0 {
+		o.MaxDelay = time.Hour
+	}
+	opts = o
+	client = newClient(o.Timeout)
+	if workers != nil {
+		workers.Shutdown()
+	}
+	workers = pool.NewPool(o.Workers, o.QueueSize)
+	workers.Run()
+}
+
+// StartRetries 定期发送到期的重试，以及超时未完成的发送（实例退出或队列已满时留下的）。
+// 重试时间保存在数据库中，重启后继续；多实例部署时每条记录只由一个实例领取。ctx 结束时退出
+func StartRetries(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(scanInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				retryDue(time.Now())
+			}
+		}
+	}()
+}
+
+// newClient 不跟随重定向：接收方应直接返回 2xx
+func newClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Dispatcher 把领域事件投递给订阅了该事件的 Webhook，作为 outbox 的发布方式之一。
+// 同一事件重复发布时，每个 Webhook 只投递一次
+type Dispatcher struct{}
+
+// Publish 为匹配的 Webhook 记录投递并交给协程池发送，发送结果不影响返回值
+func (Dispatcher) Publish(_ context.Context, e outbox.Event) error {
+	hooks, err := store.ListEnabledWebhooks()
+	if err != nil {
+		return err
+	}
+	var payload []byte
+	for i := range hooks {
+		hook := &hooks[i]
+		if !Matches(hook.Events, e.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(e); err != nil {
+				return err
+			}
+		}
+		key := strconv.FormatInt(hook.ID, 10) + ":" + e.ID
+		d := newDelivery(hook.ID, e.ID, e.Type, string(payload))
+		d.DedupKey = &key
+		created, err := store.AddDelivery(d)
+		if err != nil {
+			return err
+		}
+		if created {
+			enqueue(d)
+		}
+	}
+	return nil
+}
+
+// Matches 事件类型是否在订阅列表中，列表为空或包含 * 时匹配全部
+func Matches(filter, eventType string) bool {
+	if filter == "" {
+		return true
+	}
+	for _, t := range strings.Split(filter, ",") {
+		if t = strings.TrimSpace(t); t == "*" || t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// SendTest 向 Webhook 发送一个测试事件，不检查订阅列表与启用状态
+func SendTest(hook *model.Webhook) (*model.WebhookDelivery, error) {
+	data, err := json.Marshal(map[string]int64{"webhookId": hook.ID})
+	if err != nil {
+		return nil, err
+	}
+	e := outbox.Event{ID: newID(), Type: TypeTest, OccurredAt: time.Now().UTC(), Data: data}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	d := newDelivery(hook.ID, e.ID, e.Type, string(payload))
+	d.Redelivery = true
+	return d, start(d)
+}
+
+// Redeliver 以新的投递 ID 重新发送某次投递的内容，重试次数重新计算
+func Redeliver(previous *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	d := newDelivery(previous.WebhookID, previous.EventID, previous.EventType, previous.Payload)
+	d.Redelivery = true
+	return d, start(d)
+}
+
+// start 写入发送记录并加入协程池
+func start(d *model.WebhookDelivery) error {
+	if _, err := store.AddDelivery(d); err != nil {
+		return err
+	}
+	enqueue(d)
+	return nil
+}
+
+// newDelivery 新的发送记录，NextAttemptAt 为租约：到期仍未完成时重新发送
+func newDelivery(webhookID int64, eventID, eventType, payload string) *model.WebhookDelivery {
+	lease := scheduleAt(time.Now(), leaseTimeout)
+	return &model.WebhookDelivery{
+		WebhookID:     webhookID,
+		DeliveryID:    newID(),
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       payload,
+		Attempt:       1,
+		Status:        model.DeliveryPending,
+		CreateTime:    time.Now(),
+		NextAttemptAt: &lease,
+	}
+}
+
+// enqueue 加入专用协程池，不阻塞调用方；队列已满时推迟发送，由 StartRetries 稍后领取
+func enqueue(d *model.WebhookDelivery) {
+	queued := workers.TryAddTask(pool.Task{
+		Job: func() error {
+			return deliver(d)
+		},
+	})
+	if queued || d.NextAttemptAt == nil {
+		return
+	}
+	later := scheduleAt(time.Now(), queueFullDelay)
+	if _, err := store.ClaimDelivery(d.ID, *d.NextAttemptAt, &later); err != nil {
+		log.Printf("Failed to defer webhook delivery %s: %v", d.DeliveryID, err)
+	}
+	log.Printf("Webhook queue full, delivery %s deferred", d.DeliveryID)
+}
+
+// deliver 发送一次并保存结果，失败且未达到次数上限时按退避时间记录下一次发送的时间
+func deliver(d *model.WebhookDelivery) error {
+	var hook model.Webhook
+	err := store.GetWebhook(d.WebhookID, &hook)
+	d.NextAttemptAt = nil
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		d.Status, d.Error = model.DeliveryFailed, "webhook deleted"
+		return store.FinishDelivery(d)
+	case err != nil:
+		d.Status, d.Error = model.DeliveryFailed, err.Error()
+	case !hook.Enabled && !d.Redelivery:
+		d.Status, d.Error = model.DeliveryFailed, "webhook disabled"
+		return store.FinishDelivery(d)
+	default:
+		send(&hook, d)
+	}
+	if d.Status == model.DeliveryFailed && d.Attempt < opts.MaxAttempts {
+		next := scheduleAt(time.Now(), backoff(d.Attempt))
+		d.NextAttemptAt = &next
+	}
+	if err := store.FinishDelivery(d); err != nil {
+		log.Printf("Failed to record webhook delivery %d: %v", d.ID, err)
+	}
+	return nil
+}
+
+// send 发送签名后的请求，2xx 视为成功
+func send(hook *model.Webhook, d *model.WebhookDelivery) {
+	body := []byte(d.Payload)
+	now := time.Now()
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		d.Status, d.Error = model.DeliveryFailed, err.Error()
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "UserCenter-Webhook/1.0")
+	req.Header.Set(HeaderWebhookID, strconv.FormatInt(hook.ID, 10))
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderDelivery, d.DeliveryID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, now.Unix(), body))
+
+	resp, err := client.Do(req)
+	d.DurationMs = time.Since(now).Milliseconds()
+	if err != nil {
+		d.Status, d.Error = model.DeliveryFailed, truncate(err.Error(), 512)
+		return
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	d.ResponseCode = resp.StatusCode
+	d.ResponseBody = strings.ToValidUTF8(string(respBody), "")
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		d.Status = model.DeliverySucceeded
+		return
+	}
+	d.Status, d.Error = model.DeliveryFailed, fmt.Sprintf("unexpected status %d", resp.StatusCode)
+}
+
+// retryDue 领取到期的记录：failed 行写入下一次发送记录，超时未完成的 pending 行重新发送。
+// 领取时先把 nextAttemptAt 延后一个租约，处理失败或实例退出时租约到期后再次领取
+func retryDue(now time.Time) {
+	due, err := store.ListDueDeliveries(now, scanBatchSize)
+	if err != nil {
+		log.Printf("Failed to list due webhook deliveries: %v", err)
+		return
+	}
+	for i := range due {
+		d := &due[i]
+		lease := scheduleAt(now, leaseTimeout)
+		claimed, err := store.ClaimDelivery(d.ID, *d.NextAttemptAt, &lease)
+		if err != nil {
+			log.Printf("Failed to claim webhook delivery %d: %v", d.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		d.NextAttemptAt = &lease
+		if d.Status == model.DeliveryPending {
+			enqueue(d)
+			continue
+		}
+		retry := newDelivery(d.WebhookID, d.EventID, d.EventType, d.Payload)
+		retry.DeliveryID = d.DeliveryID
+		retry.Attempt = d.Attempt + 1
+		retry.Redelivery = d.Redelivery
+		if err := start(retry); err != nil {
+			log.Printf("Failed to start webhook retry %s: %v", retry.DeliveryID, err)
+			continue
+		}
+		if _, err := store.ClaimDelivery(d.ID, lease, nil); err != nil {
+			log.Printf("Failed to finish webhook retry %s: %v", retry.DeliveryID, err)
+		}
+	}
+}
+
+// backoff 第 attempt 次失败后的等待时间：BaseDelay 翻倍，不超过 MaxDelay，加减 20% 的随机抖动
+func backoff(attempt int) time.Duration {
+	d := opts.BaseDelay
+	for i := 1; i < attempt && d < opts.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > opts.MaxDelay {
+		d = opts.MaxDelay
+	}
+	jitter := time.Duration(mathrand.Int63n(int64(d)/5*2+1)) - d/5
+	return d + jitter
+}
+
+// scheduleAt 计划时间精确到毫秒，与数据库中保存的值一致，领取时才能按原值比较
+func scheduleAt(now time.Time, after time.Duration) time.Time {
+	return now.Add(after).Truncate(time.Millisecond)
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
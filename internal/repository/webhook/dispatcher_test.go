@@ -0,0 +1,329 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/outbox"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// memoryStore 测试用的内存存储，行为与数据库实现一致
+type memoryStore struct {
+	mu         sync.Mutex
+	hooks      map[int64]model.Webhook
+	deliveries []model.WebhookDelivery
+}
+
+func useMemoryStore(t *testing.T, hooks ...model.Webhook) *memoryStore {
+	m := &memoryStore{hooks: map[int64]model.Webhook{}}
+	for _, h := range hooks {
+		m.hooks[h.ID] = h
+	}
+	previous := store
+	store = m
+	t.Cleanup(func() { store = previous })
+	return m
+}
+
+func (m *memoryStore) ListEnabledWebhooks() ([]model.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var hooks []model.Webhook
+	for _, h := range m.hooks {
+		if h.Enabled {
+			hooks = append(hooks, h)
+		}
+	}
+	return hooks, nil
+}
+
+func (m *memoryStore) GetWebhook(id int64, hook *model.Webhook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.hooks[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	*hook = h
+	return nil
+}
+
+func (m *memoryStore) AddDelivery(d *model.WebhookDelivery) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if d.DedupKey != nil {
+		for _, existing := range m.deliveries {
+			if existing.DedupKey != nil && *existing.DedupKey == *d.DedupKey {
+				return false, nil
+			}
+		}
+	}
+	d.ID = int64(len(m.deliveries) + 1)
+	m.deliveries = append(m.deliveries, *d)
+	return true, nil
+}
+
+func (m *memoryStore) FinishDelivery(d *model.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	row := &m.deliveries[d.ID-1]
+	row.Status, row.ResponseCode, row.ResponseBody = d.Status, d.ResponseCode, d.ResponseBody
+	row.Error, row.DurationMs, row.NextAttemptAt = d.Error, d.DurationMs, d.NextAttemptAt
+	return nil
+}
+
+func (m *memoryStore) ListDueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []model.WebhookDelivery
+	for _, d := range m.deliveries {
+		if d.NextAttemptAt != nil && !d.NextAttemptAt.After(now) && len(due) < limit {
+			due = append(due, d)
+		}
+	}
+	return due, nil
+}
+
+func (m *memoryStore) ClaimDelivery(id int64, due time.Time, next *time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	row := &m.deliveries[id-1]
+	if row.NextAttemptAt == nil || !row.NextAttemptAt.Equal(due) {
+		return false, nil
+	}
+	row.NextAttemptAt = next
+	return true, nil
+}
+
+func (m *memoryStore) snapshot() []model.WebhookDelivery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]model.WebhookDelivery(nil), m.deliveries...)
+}
+
+// waitFinished 等待 n 条发送记录全部完成
+func (m *memoryStore) waitFinished(t *testing.T, n int) []model.WebhookDelivery {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		rows := m.snapshot()
+		finished := 0
+		for _, d := range rows {
+			if d.Status != model.DeliveryPending {
+				finished++
+			}
+		}
+		if len(rows) >= n && finished >= n {
+			return rows
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d finished deliveries, got %+v", n, m.snapshot())
+	return nil
+}
+
+func setupTest(t *testing.T, o Options) {
+	if o.Timeout == 0 {
+		o.Timeout = 2 * time.Second
+	}
+	Setup(o)
+	t.Cleanup(func() { Setup(Options{}) })
+}
+
+func testEvent(t *testing.T) outbox.Event {
+	data, _ := json.Marshal(outbox.UserData{UserAccount: "alice"})
+	return outbox.Event{ID: newID(), Type: outbox.TypeUserCreated, UserID: 7, OccurredAt: time.Now().UTC(), Data: data}
+}
+
+func TestDeliverySignedAndLogged(t *testing.T) {
+	const secret = "whsec_test"
+	var received atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := Verify(secret, r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderSignature), body, time.Minute, time.Now()); err != nil {
+			t.Errorf("signature verification failed: %v", err)
+		}
+		if err := Verify("whsec_other", r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderSignature), body, time.Minute, time.Now()); err != ErrSignatureMismatch {
+			t.Errorf("signature with wrong secret: got %v, want ErrSignatureMismatch", err)
+		}
+		if got := r.Header.Get(HeaderEvent); got != outbox.TypeUserCreated {
+			t.Errorf("event header = %q", got)
+		}
+		if got := r.Header.Get(HeaderWebhookID); got != "1" {
+			t.Errorf("webhook id header = %q", got)
+		}
+		var e outbox.Event
+		if err := json.Unmarshal(body, &e); err != nil || e.UserID != 7 {
+			t.Errorf("unexpected body %s: %v", body, err)
+		}
+		received.Add(1)
+		w.WriteHeader(http.StatusAccepted)
+		io.WriteString(w, strings.Repeat("x", 2*maxResponseBody))
+	}))
+	defer receiver.Close()
+
+	setupTest(t, Options{})
+	s := useMemoryStore(t,
+		model.Webhook{ID: 1, URL: receiver.URL, Secret: secret, Enabled: true},
+		model.Webhook{ID: 2, URL: receiver.URL, Secret: secret, Enabled: true, Events: outbox.TypeUserDeleted},
+	)
+
+	e := testEvent(t)
+	// 同一事件重复发布时只投递一次，未订阅该事件的 Webhook 不投递
+	for i := 0; i < 2; i++ {
+		if err := (Dispatcher{}).Publish(context.Background(), e); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+	rows := s.waitFinished(t, 1)
+	if len(rows) != 1 || received.Load() != 1 {
+		t.Fatalf("got %d deliveries and %d requests, want 1", len(rows), received.Load())
+	}
+	d := rows[0]
+	if d.WebhookID != 1 || d.EventID != e.ID || d.EventType != e.Type || d.Attempt != 1 {
+		t.Errorf("unexpected delivery %+v", d)
+	}
+	if d.Status != model.DeliverySucceeded || d.ResponseCode != http.StatusAccepted || d.Error != "" {
+		t.Errorf("status = %s, code = %d, error = %q", d.Status, d.ResponseCode, d.Error)
+	}
+	if len(d.ResponseBody) != maxResponseBody {
+		t.Errorf("response body length = %d, want %d", len(d.ResponseBody), maxResponseBody)
+	}
+	if d.NextAttemptAt != nil {
+		t.Errorf("succeeded delivery still scheduled at %v", d.NextAttemptAt)
+	}
+}
+
+func TestDeliveryRetriesOn5xx(t *testing.T) {
+	var calls atomic.Int32
+	var deliveryIDs sync.Map
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deliveryIDs.Store(r.Header.Get(HeaderDelivery), true)
+		if calls.Add(1) == 1 {
+			http.Error(w, "temporarily unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	setupTest(t, Options{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Second})
+	s := useMemoryStore(t, model.Webhook{ID: 1, URL: receiver.URL, Secret: "whsec_test", Enabled: true})
+
+	if err := (Dispatcher{}).Publish(context.Background(), testEvent(t)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	first := s.waitFinished(t, 1)[0]
+	if first.Status != model.DeliveryFailed || first.ResponseCode != http.StatusServiceUnavailable {
+		t.Fatalf("first attempt: status = %s, code = %d", first.Status, first.ResponseCode)
+	}
+	if first.Error != "unexpected status 503" || !strings.Contains(first.ResponseBody, "temporarily unavailable") {
+		t.Errorf("first attempt: error = %q, body = %q", first.Error, first.ResponseBody)
+	}
+	if first.NextAttemptAt == nil {
+		t.Fatal("failed attempt has no retry scheduled")
+	}
+
+	// 未到重试时间时不发送
+	retryDue(time.Now().Add(-time.Minute))
+	if got := len(s.snapshot()); got != 1 {
+		t.Fatalf("retry sent before it was due: %d deliveries", got)
+	}
+	retryDue(first.NextAttemptAt.Add(time.Millisecond))
+	rows := s.waitFinished(t, 2)
+	second := rows[1]
+	if second.Attempt != 2 || second.DeliveryID != first.DeliveryID || second.Status != model.DeliverySucceeded {
+		t.Errorf("second attempt: %+v", second)
+	}
+	if rows[0].NextAttemptAt != nil {
+		t.Errorf("retried attempt still scheduled at %v", rows[0].NextAttemptAt)
+	}
+	count := 0
+	deliveryIDs.Range(func(_, _ interface{}) bool { count++; return true })
+	if count != 1 {
+		t.Errorf("retries used %d delivery ids, want 1", count)
+	}
+}
+
+func TestDeliveryStopsAfterMaxAttempts(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	setupTest(t, Options{MaxAttempts: 2, BaseDelay: time.Second, MaxDelay: time.Second})
+	s := useMemoryStore(t, model.Webhook{ID: 1, URL: receiver.URL, Secret: "whsec_test", Enabled: true})
+
+	if err := (Dispatcher{}).Publish(context.Background(), testEvent(t)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	first := s.waitFinished(t, 1)[0]
+	retryDue(first.NextAttemptAt.Add(time.Millisecond))
+	rows := s.waitFinished(t, 2)
+	if last := rows[1]; last.Status != model.DeliveryFailed || last.Attempt != 2 || last.NextAttemptAt != nil {
+		t.Errorf("last attempt: status = %s, attempt = %d, next = %v", last.Status, last.Attempt, last.NextAttemptAt)
+	}
+	retryDue(time.Now().Add(24 * time.Hour))
+	if got := len(s.snapshot()); got != 2 {
+		t.Errorf("sent %d attempts, want 2", got)
+	}
+}
+
+func TestStalePendingDeliveryResent(t *testing.T) {
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer receiver.Close()
+
+	setupTest(t, Options{})
+	s := useMemoryStore(t, model.Webhook{ID: 1, URL: receiver.URL, Secret: "whsec_test", Enabled: true})
+
+	// 模拟写入记录后实例退出：记录仍为 pending，租约到期后重新发送
+	d := newDelivery(1, newID(), outbox.TypeUserCreated, `{}`)
+	if _, err := s.AddDelivery(d); err != nil {
+		t.Fatal(err)
+	}
+	retryDue(time.Now())
+	if got := s.snapshot()[0].Status; got != model.DeliveryPending {
+		t.Fatalf("delivery resent before its lease expired: %s", got)
+	}
+	retryDue(d.NextAttemptAt.Add(time.Millisecond))
+	rows := s.waitFinished(t, 1)
+	if len(rows) != 1 || rows[0].Status != model.DeliverySucceeded || calls.Load() != 1 {
+		t.Errorf("got %+v after %d requests", rows, calls.Load())
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	setupTest(t, Options{BaseDelay: 10 * time.Second, MaxDelay: time.Minute})
+	for attempt, want := range map[int]time.Duration{1: 10 * time.Second, 2: 20 * time.Second, 3: 40 * time.Second, 4: time.Minute, 10: time.Minute} {
+		got := backoff(attempt)
+		if got < want*8/10 || got > want*12/10 {
+			t.Errorf("backoff(%d) = %v, want %v ± 20%%", attempt, got, want)
+		}
+	}
+}
+
+func TestVerifyRejectsExpiredTimestamp(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	old := time.Now().Add(-10 * time.Minute).Unix()
+	signature := Sign("whsec_test", old, body)
+	if err := Verify("whsec_test", strconv.FormatInt(old, 10), signature, body, 5*time.Minute, time.Now()); err != ErrTimestampExpired {
+		t.Errorf("got %v, want ErrTimestampExpired", err)
+	}
+	if err := Verify("whsec_test", strconv.FormatInt(old, 10), signature, []byte(`{"id":"2"}`), time.Hour, time.Now()); err != ErrSignatureMismatch {
+		t.Errorf("tampered body: got %v, want ErrSignatureMismatch", err)
+	}
+}
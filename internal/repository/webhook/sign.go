@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 投递请求的请求头
+const (
+	HeaderWebhookID = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery" // 同一次投递的重试相同
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// signatureVersion 签名格式版本，更换算法时递增
+const signatureVersion = "v1"
+
+var (
+	ErrSignatureMismatch = errors.New("webhook signature mismatch")
+	ErrTimestampExpired  = errors.New("webhook timestamp outside tolerance")
+)
+
+// GenerateSecret 生成签名密钥
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Sign 计算签名：v1=hex(HMAC-SHA256(secret, "<timestamp>.<body>"))，时间戳为 Unix 秒。
+// 时间戳参与签名，接收方据此拒绝重放的旧请求
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 供接收方校验签名与时间戳，timestamp、signature 为请求头中的值，
+// 时间戳与 now 相差超过 tolerance 时拒绝
+func Verify(secret, timestamp, signature string, body []byte, tolerance time.Duration, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrSignatureMismatch
+	}
+	if d := now.Sub(time.Unix(ts, 0)); d > tolerance || d < -tolerance {
+		return ErrTimestampExpired
+	}
+	expected := Sign(secret, ts, body)
+	// 允许以逗号分隔的多个签名，任一匹配即可，便于今后增加签名版本
+	for _, candidate := range strings.Split(signature, ",") {
+		if hmac.Equal([]byte(strings.TrimSpace(candidate)), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrSignatureMismatch
+}
@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"http_grpc/internal/repository/model"
+	"time"
+)
+
+// deliveryStore 投递用到的持久化操作，默认使用数据库
+type deliveryStore interface {
+	ListEnabledWebhooks() ([]model.Webhook, error)
+	GetWebhook(id int64, hook *model.Webhook) error
+	AddDelivery(d *model.WebhookDelivery) (bool, error)
+	FinishDelivery(d *model.WebhookDelivery) error
+	ListDueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error)
+	ClaimDelivery(id int64, due time.Time, next *time.Time) (bool, error)
+}
+
+var store deliveryStore = modelStore{}
+
+type modelStore struct{}
+
+func (modelStore) ListEnabledWebhooks() ([]model.Webhook, error) {
+	return model.ListEnabledWebhooks()
+}
+
+func (modelStore) GetWebhook(id int64, hook *model.Webhook) error {
+	return model.GetWebhook(id, hook)
+}
+
+func (modelStore) AddDelivery(d *model.WebhookDelivery) (bool, error) {
+	return model.AddWebhookDelivery(d)
+}
+
+func (modelStore) FinishDelivery(d *model.WebhookDelivery) error {
+	return model.FinishWebhookDelivery(d)
+}
+
+func (modelStore) ListDueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	return model.ListDueWebhookDeliveries(now, limit)
+}
+
+func (modelStore) ClaimDelivery(id int64, due time.Time, next *time.Time) (bool, error) {
+	return model.ClaimWebhookDelivery(id, due, next)
+}
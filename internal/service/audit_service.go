@@ -0,0 +1,88 @@
+package service
+
+import (
+	"gorm.io/gorm"
+	"http_grpc/internal/repository/audit"
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/outbox"
+	"http_grpc/pkg/database"
+	"http_grpc/pkg/errs"
+	"http_grpc/pkg/validator"
+	"log"
+	"time"
+)
+
+const auditMaxPageSize = 100
+
+// audited 在同一事务中执行修改并写入审计事件，审计写入失败时修改一并回滚。
+// target 在 fn 执行后读取，新建用户时可由 fn 填入 ID；fn 写入的领域事件在提交后唤醒 outbox 中继发布
+func audited(meta audit.Meta, action string, target *model.User, fn func(tx *gorm.DB) (audit.Changes, error)) error {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		changes, err := fn(tx)
+		if err != nil {
+			return err
+		}
+		return model.AddAuditEvent(tx, newAuditEvent(meta, action, target, changes))
+	})
+	if err == nil {
+		outbox.Notify()
+	}
+	return err
+}
+
+// recordAudit 记录不修改数据库的事件（登录、解锁、撤销会话），写入失败只记日志
+func recordAudit(meta audit.Meta, action string, target *model.User, changes audit.Changes) {
+	if err := model.AddAuditEvent(database.DB, newAuditEvent(meta, action, target, changes)); err != nil {
+		log.Printf("Failed to record audit event %s: %v", action, err)
+	}
+}
+
+// auditTarget 读取审计事件的目标用户，读取失败时只记录 ID
+func auditTarget(id int64) *model.User {
+	var user model.User
+	if err := model.GetUserByID(id, &user); err != nil {
+		return &model.User{ID: id}
+	}
+	return &user
+}
+
+func newAuditEvent(meta audit.Meta, action string, target *model.User, changes audit.Changes) *model.AuditEvent {
+	event := &model.AuditEvent{
+		CreateTime:   time.Now(),
+		Action:       action,
+		ActorID:      meta.ActorID,
+		ActorAccount: meta.ActorAccount,
+		Transport:    meta.Transport,
+		IP:           meta.IP,
+		UserAgent:    meta.UserAgent,
+		RequestID:    meta.RequestID,
+		Changes:      changes.JSON(),
+	}
+	if target != nil {
+		event.TargetID = target.ID
+		event.TargetAccount = target.UserAccount
+	}
+	return event
+}
+
+// ListAuditEvents 按条件分页查询审计事件（管理员）
+func (s *UserService) ListAuditEvents(filter model.AuditFilter, page, size int) ([]model.AuditEvent, int64, error) {
+	var c validator.Collector
+	if page < 1 {
+		c.Add("page", "must be a positive integer")
+	}
+	if size < 1 || size > auditMaxPageSize {
+		c.Add("size", "must be between 1 and 100")
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		c.Add("to", "must be after from")
+	}
+	if err := c.Err(); err != nil {
+		return nil, 0, err
+	}
+	events, total, err := model.ListAuditEvents(filter, page, size)
+	if err != nil {
+		return nil, 0, errs.Wrap(errs.Unavailable, "failed to list audit events", err)
+	}
+	return events, total, nil
+}
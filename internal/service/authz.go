@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"http_grpc/internal/repository/session"
+	"http_grpc/internal/repository/token"
+	"http_grpc/pkg/errs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CallerContext HTTP 请求的 context，会话 Cookie 登录的调用方也写入其中，
+// 使 HTTP 与 gRPC 通过同一组 Authorize 函数鉴权
+func CallerContext(c *gin.Context) context.Context {
+	ctx := c.Request.Context()
+	if token.FromContext(ctx) != nil {
+		return ctx
+	}
+	store := session.Current(c)
+	userID, ok := store.Values[session.KeyUserID].(int64)
+	if !ok {
+		return ctx
+	}
+	account, _ := store.Values[session.KeyUserAccount].(string)
+	role, _ := store.Values[session.KeyUserRole].(int)
+	return token.WithIdentity(ctx, &token.Identity{UserID: userID, UserAccount: account, UserRole: role})
+}
+
+// AuthorizeCaller 要求调用方已认证，且为目标用户本人或管理员
+func AuthorizeCaller(ctx context.Context, targetUserID int64) error {
+	id := token.FromContext(ctx)
+	if id == nil {
+		return errs.New(errs.Unauthenticated, "Unauthorized")
+	}
+	if id.UserID != targetUserID && id.UserRole != roleAdmin {
+		return errs.New(errs.PermissionDenied, "Forbidden")
+	}
+	return nil
+}
+
+// AuthorizeSelf 要求调用方已认证且就是目标用户本人
+func AuthorizeSelf(ctx context.Context, targetUserID int64) error {
+	id := token.FromContext(ctx)
+	if id == nil {
+		return errs.New(errs.Unauthenticated, "Unauthorized")
+	}
+	if id.UserID != targetUserID {
+		return errs.New(errs.PermissionDenied, "Forbidden")
+	}
+	return nil
+}
+
+// AuthorizeAdmin 要求调用方已认证且为管理员
+func AuthorizeAdmin(ctx context.Context) error {
+	id := token.FromContext(ctx)
+	if id == nil {
+		return errs.New(errs.Unauthenticated, "Unauthorized")
+	}
+	if id.UserRole != roleAdmin {
+		return errs.New(errs.PermissionDenied, "Forbidden")
+	}
+	return nil
+}
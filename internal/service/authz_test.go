@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"http_grpc/internal/repository/token"
+	"http_grpc/pkg/errs"
+	"testing"
+)
+
+func TestAdminOnlyOperationsRejectOtherCallers(t *testing.T) {
+	s := &UserService{} // 鉴权失败时不会提交任务，无需协程池
+	self := token.WithIdentity(context.Background(), &token.Identity{UserID: 7, UserRole: 0})
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want errs.Kind
+	}{
+		{"anonymous", context.Background(), errs.Unauthenticated},
+		{"self", self, errs.PermissionDenied},
+	}
+	for _, tc := range cases {
+		if err := s.DeleteUser(tc.ctx, 7); !errs.Is(err, tc.want) {
+			t.Errorf("DeleteUser as %s: got %v, want %v", tc.name, err, tc.want)
+		}
+		if err := s.SuspendUser(tc.ctx, 7); !errs.Is(err, tc.want) {
+			t.Errorf("SuspendUser as %s: got %v, want %v", tc.name, err, tc.want)
+		}
+		if _, err := s.RevokeUserSessions(tc.ctx, 7); !errs.Is(err, tc.want) {
+			t.Errorf("RevokeUserSessions as %s: got %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestAuthorizeCaller(t *testing.T) {
+	user := token.WithIdentity(context.Background(), &token.Identity{UserID: 7})
+	admin := token.WithIdentity(context.Background(), &token.Identity{UserID: 1, UserRole: roleAdmin})
+	if err := AuthorizeCaller(user, 7); err != nil {
+		t.Errorf("self: %v", err)
+	}
+	if err := AuthorizeCaller(user, 8); !errs.Is(err, errs.PermissionDenied) {
+		t.Errorf("other user: got %v, want PermissionDenied", err)
+	}
+	if err := AuthorizeCaller(admin, 8); err != nil {
+		t.Errorf("admin: %v", err)
+	}
+}
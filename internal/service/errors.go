@@ -0,0 +1,27 @@
+package service
+
+import (
+	"database/sql/driver"
+	"errors"
+	"gorm.io/gorm"
+	"http_grpc/pkg/errs"
+	"net"
+)
+
+// dbError 数据库错误转换为领域错误，notFound 为记录不存在时的提示；已是领域错误时原样返回
+func dbError(err error, notFound string) error {
+	var netErr net.Error
+	var domainErr *errs.Error
+	switch {
+	case errors.As(err, &domainErr):
+		return err
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return errs.New(errs.NotFound, notFound)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return errs.Wrap(errs.AlreadyExists, "user already exists", err)
+	case errors.Is(err, driver.ErrBadConn), errors.As(err, &netErr):
+		return errs.Wrap(errs.Unavailable, "database unavailable", err)
+	default:
+		return errs.Wrap(errs.Internal, "database error", err)
+	}
+}
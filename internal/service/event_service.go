@@ -0,0 +1,58 @@
+package service
+
+import (
+	"encoding/json"
+	"gorm.io/gorm"
+	"http_grpc/internal/repository/audit"
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/outbox"
+	"log"
+	"strings"
+)
+
+// addDomainEvent 在修改所在的事务中写入领域事件，由 outbox 中继在提交后发布
+func addDomainEvent(tx *gorm.DB, meta audit.Meta, eventType string, userID int64, data interface{}) error {
+	e, err := outbox.New(eventType, userID, meta.RequestID, data)
+	if err != nil {
+		return err
+	}
+	return model.AddOutboxEvents(tx, e)
+}
+
+// userEventData 用户创建、删除事件的内容
+func userEventData(user *model.User) outbox.UserData {
+	return outbox.UserData{
+		UserAccount: user.UserAccount,
+		Username:    user.Username,
+		Email:       user.Email,
+		UserRole:    user.UserRole,
+	}
+}
+
+// addUserUpdated 有字段变化时写入 UserUpdated 事件
+func addUserUpdated(tx *gorm.DB, meta audit.Meta, userID int64, changes audit.Changes) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	return addDomainEvent(tx, meta, outbox.TypeUserUpdated, userID, outbox.UpdatedData{Changes: json.RawMessage(changes.JSON())})
+}
+
+// addPasswordChanged 写入 PasswordChanged 事件，原因取审计动作的后缀（change、reset、force_reset）
+func addPasswordChanged(tx *gorm.DB, meta audit.Meta, action string, userID int64) error {
+	reason := strings.TrimPrefix(action, "password.")
+	return addDomainEvent(tx, meta, outbox.TypePasswordChanged, userID, outbox.PasswordChangedData{Reason: reason})
+}
+
+// recordLogin 登录成功后记录审计事件与 UserLoggedIn 事件，失败只记日志，不影响登录
+func recordLogin(meta audit.Meta, user *model.User, mfa bool) {
+	err := audited(meta, audit.ActionLogin, user, func(tx *gorm.DB) (audit.Changes, error) {
+		return nil, addDomainEvent(tx, meta, outbox.TypeUserLoggedIn, user.ID, outbox.LoggedInData{
+			IP:        meta.IP,
+			Transport: meta.Transport,
+			MFA:       mfa,
+		})
+	})
+	if err != nil {
+		log.Printf("Failed to record login of user %d: %v", user.ID, err)
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"http_grpc/internal/repository/audit"
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/userexport"
+	"http_grpc/pkg/errs"
+	"http_grpc/pkg/validator"
+	"time"
+)
+
+// ExportRequest 导出用户的参数
+type ExportRequest struct {
+	Format  string   // 仅用于审计记录，gRPC 为空
+	Columns []string // 为空时导出除敏感字段外的全部列
+}
+
+// ExportColumns 校验并返回要导出的列，调用方据此写出表头
+func ExportColumns(names []string) ([]string, error) {
+	columns, err := userexport.ParseColumns(names)
+	if err != nil {
+		var c validator.Collector
+		c.Add("columns", err.Error())
+		return nil, c.Err()
+	}
+	return columns, nil
+}
+
+// ExportUsers 按 id 顺序把未删除的用户逐个交给 emit（管理员），返回导出的行数。
+// 与列表接口的过滤条件相同，但不分页；emit 返回错误或 ctx 结束时停止，已导出的行不会撤回
+func (s *UserService) ExportUsers(ctx context.Context, req ExportRequest, emit func(user *model.User) error) (int64, error) {
+	columns, err := ExportColumns(req.Columns)
+	if err != nil {
+		return 0, err
+	}
+	if !userexport.TryAcquire() {
+		return 0, errs.RetryLater("too many exports in progress", 30*time.Second)
+	}
+	defer userexport.Release()
+
+	var rows int64
+	err = model.EachUser(ctx, columns, func(user *model.User) error {
+		if err := emit(user); err != nil {
+			return err
+		}
+		rows++
+		return nil
+	})
+	changes := audit.Changes{}.Set("columns", nil, columns).Set("rows", nil, rows)
+	if req.Format != "" {
+		changes.Set("format", nil, req.Format)
+	}
+	if err != nil {
+		changes.Set("error", nil, truncateReason(err.Error()))
+	}
+	recordAudit(audit.FromContext(ctx), audit.ActionUserExport, nil, changes)
+	if err != nil {
+		return rows, dbError(err, "user not found")
+	}
+	return rows, nil
+}
@@ -0,0 +1,550 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"http_grpc/internal/repository/audit"
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/outbox"
+	"http_grpc/internal/repository/password"
+	"http_grpc/internal/repository/userimport"
+	"http_grpc/pkg/database"
+	"http_grpc/pkg/errs"
+	"http_grpc/pkg/validator"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 账号已存在时的处理方式
+const (
+	DuplicateSkip   = "skip"   // 跳过该行
+	DuplicateUpdate = "update" // 用该行的非空资料字段更新已有用户，不修改密码
+	DuplicateFail   = "fail"   // 该行记为失败（默认）
+)
+
+// 每行的导入结果
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+)
+
+// importStaleAfter 后台任务超过该时长没有更新进度时视为已中断（如服务重启）
+const importStaleAfter = 10 * time.Minute
+
+// ImportOptions 导入参数
+type ImportOptions struct {
+	DryRun      bool   `json:"dryRun"`      // 只校验并给出每行的预期结果，不写入
+	OnDuplicate string `json:"onDuplicate"` // skip | update | fail，默认 fail
+}
+
+// ImportRowResult 一行的导入结果，试运行时为预期结果
+type ImportRowResult struct {
+	Row         int                   `json:"row"`
+	Line        int                   `json:"line,omitempty"`
+	UserAccount string                `json:"userAccount,omitempty"`
+	Status      string                `json:"status"` // created | updated | skipped | failed
+	UserID      int64                 `json:"userId,omitempty"`
+	Reason      string                `json:"reason,omitempty"` // 跳过或失败的原因
+	Violations  []validator.Violation `json:"violations,omitempty"`
+}
+
+// ImportReport 导入结果汇总与逐行结果
+type ImportReport struct {
+	DryRun  bool              `json:"dryRun"`
+	Total   int               `json:"total"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Skipped int               `json:"skipped"`
+	Failed  int               `json:"failed"`
+	Error   string            `json:"error,omitempty"` // 读取中断的原因，此前的行已处理
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+// ImportJobStatus 后台导入任务的状态，结束后附带逐行结果
+type ImportJobStatus struct {
+	*model.ImportJob
+	Rows []ImportRowResult `json:"rows,omitempty"`
+}
+
+// ImportUsers 同步导入（管理员），next 逐行返回数据，返回 io.EOF 表示结束。
+// 数据按批在事务中写入，单行的问题只记入该行结果；next 返回其他错误时停止读取，
+// 已读取的行照常写入，报告与错误一并返回
+func (s *UserService) ImportUsers(ctx context.Context, opts ImportOptions, next func() (userimport.Record, error)) (*ImportReport, error) {
+	if err := validateImportOptions(opts); err != nil {
+		return nil, err
+	}
+	if !userimport.TryAcquire() {
+		return nil, errs.RetryLater("too many imports in progress", 30*time.Second)
+	}
+	defer userimport.Release()
+	im := newUserImport(audit.FromContext(ctx), opts)
+	err := im.run(next)
+	return &im.report, err
+}
+
+// StartImportJob 创建后台导入任务（管理员），path 为暂存的上传文件，任务结束后删除。
+// 格式或表头错误时直接返回错误，不创建任务
+func (s *UserService) StartImportJob(ctx context.Context, opts ImportOptions, format, path string) (*model.ImportJob, error) {
+	if err := validateImportOptions(opts); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		os.Remove(path)
+		return nil, errs.Wrap(errs.Internal, "failed to open import file", err)
+	}
+	cleanup := func() {
+		f.Close()
+		os.Remove(path)
+	}
+	reader, err := userimport.NewReader(format, f)
+	if err != nil {
+		cleanup()
+		return nil, errs.New(errs.InvalidArgument, err.Error())
+	}
+	if !userimport.TryAcquire() {
+		cleanup()
+		return nil, errs.RetryLater("too many imports in progress", 30*time.Second)
+	}
+
+	if opts.OnDuplicate == "" {
+		opts.OnDuplicate = DuplicateFail
+	}
+	meta := audit.FromContext(ctx)
+	now := time.Now()
+	job := &model.ImportJob{
+		ID:          newImportJobID(),
+		Status:      model.ImportJobRunning,
+		Format:      format,
+		DryRun:      opts.DryRun,
+		OnDuplicate: opts.OnDuplicate,
+		ActorID:     meta.ActorID,
+		CreateTime:  now,
+		UpdateTime:  now,
+	}
+	if err := model.AddImportJob(job); err != nil {
+		userimport.Release()
+		cleanup()
+		return nil, dbError(err, "import job not found")
+	}
+
+	// 任务可能持续数分钟，使用独立的协程而不占用协程池
+	record := *job
+	go func() {
+		defer userimport.Release()
+		defer cleanup()
+		im := newUserImport(meta, opts)
+		im.progress = func(r *ImportReport) {
+			copyImportCounts(&record, r)
+			record.UpdateTime = time.Now()
+			if err := model.SaveImportJobProgress(&record); err != nil {
+				log.Printf("Failed to save import job %s progress: %v", record.ID, err)
+			}
+		}
+		runErr := im.run(reader.Next)
+
+		copyImportCounts(&record, &im.report)
+		record.Status = model.ImportJobSucceeded
+		if runErr != nil {
+			record.Status, record.Error = model.ImportJobFailed, truncateReason(runErr.Error())
+		}
+		rows, err := json.Marshal(im.report.Rows)
+		if err != nil {
+			record.Status, record.Error = model.ImportJobFailed, "failed to encode report"
+		}
+		record.Report = string(rows)
+		finished := time.Now()
+		record.UpdateTime, record.FinishTime = finished, &finished
+		if err := model.SaveImportJobProgress(&record); err != nil {
+			log.Printf("Failed to save import job %s result: %v", record.ID, err)
+		}
+	}()
+	return job, nil
+}
+
+// GetImportJob 查询后台导入任务；长时间没有进度的执行中任务标记为已中断
+func (s *UserService) GetImportJob(id string) (*ImportJobStatus, error) {
+	var job model.ImportJob
+	if err := model.GetImportJob(id, &job); err != nil {
+		return nil, dbError(err, "import job not found")
+	}
+	if job.Status == model.ImportJobRunning && time.Since(job.UpdateTime) > importStaleAfter {
+		const reason = "import was interrupted, rows before the last progress update were processed"
+		if stale, err := model.FailStaleImportJob(id, time.Now().Add(-importStaleAfter), reason); err == nil && stale {
+			if err := model.GetImportJob(id, &job); err != nil {
+				return nil, dbError(err, "import job not found")
+			}
+		}
+	}
+	status := &ImportJobStatus{ImportJob: &job}
+	if job.Report != "" {
+		if err := json.Unmarshal([]byte(job.Report), &status.Rows); err != nil {
+			return nil, errs.Wrap(errs.Internal, "failed to decode import report", err)
+		}
+	}
+	return status, nil
+}
+
+func validateImportOptions(opts ImportOptions) error {
+	var c validator.Collector
+	switch opts.OnDuplicate {
+	case "", DuplicateSkip, DuplicateUpdate, DuplicateFail:
+	default:
+		c.Add("onDuplicate", "must be one of skip, update, fail")
+	}
+	return c.Err()
+}
+
+// pendingRow 等待写入的行，index 为其在报告中的位置
+type pendingRow struct {
+	index int
+	user  model.User
+}
+
+// userImport 一次导入的状态：逐行校验，攒够一批后查询已有账号并在同一事务中写入
+type userImport struct {
+	meta     audit.Meta
+	opts     ImportOptions
+	report   ImportReport
+	batch    []pendingRow
+	seen     map[string]int             // 本次导入中出现过的账号（小写）及其行号
+	progress func(report *ImportReport) // 每批写入后调用
+}
+
+func newUserImport(meta audit.Meta, opts ImportOptions) *userImport {
+	if opts.OnDuplicate == "" {
+		opts.OnDuplicate = DuplicateFail
+	}
+	return &userImport{
+		meta:   meta,
+		opts:   opts,
+		report: ImportReport{DryRun: opts.DryRun, Rows: []ImportRowResult{}},
+		seen:   map[string]int{},
+	}
+}
+
+// run 读取全部数据，读取失败时仍写入已读取的行
+func (im *userImport) run(next func() (userimport.Record, error)) error {
+	var readErr error
+	for {
+		rec, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			readErr = err
+			break
+		}
+		if im.report.Total >= userimport.MaxRows() {
+			readErr = fmt.Errorf("import is limited to %d rows", userimport.MaxRows())
+			break
+		}
+		im.add(rec)
+	}
+	im.flush()
+	if readErr != nil {
+		im.report.Error = fmt.Sprintf("stopped after row %d: %v", im.report.Total, readErr)
+	}
+	return readErr
+}
+
+// add 校验一行，通过后加入当前批次
+func (im *userImport) add(rec userimport.Record) {
+	if rec.Row == 0 {
+		rec.Row = im.report.Total + 1
+	}
+	im.report.Total++
+	im.report.Rows = append(im.report.Rows, ImportRowResult{Row: rec.Row, Line: rec.Line, UserAccount: rec.User.UserAccount})
+	index := len(im.report.Rows) - 1
+	if rec.Err != nil {
+		im.fail(index, rec.Err.Error(), nil)
+		return
+	}
+
+	var c validator.Collector
+	if c.Require(accountRule.Field, rec.User.UserAccount) {
+		c.Apply(accountRule, rec.User.UserAccount)
+	}
+	validateProfile(&c, &rec.User)
+	if err := c.Err(); err != nil {
+		im.fail(index, "invalid row", err)
+		return
+	}
+	// 账号按数据库的排序规则不区分大小写，ALICE 与 alice 视为同一账号
+	key := strings.ToLower(rec.User.UserAccount)
+	if first, ok := im.seen[key]; ok {
+		im.fail(index, fmt.Sprintf("duplicate of row %d", first), nil)
+		return
+	}
+	im.seen[key] = rec.Row
+
+	im.batch = append(im.batch, pendingRow{index: index, user: rec.User})
+	if len(im.batch) >= userimport.BatchSize() {
+		im.flush()
+	}
+}
+
+// importOp 一行计划执行的写入
+type importOp struct {
+	row     pendingRow
+	current *model.User // 非空表示更新已有用户
+	fields  []string
+	changes audit.Changes
+}
+
+// flush 处理当前批次：按已有账号决定新建、更新、跳过或失败，然后在一个事务中写入
+func (im *userImport) flush() {
+	if len(im.batch) == 0 {
+		return
+	}
+	batch := im.batch
+	im.batch = nil
+	defer func() {
+		if im.progress != nil {
+			im.progress(&im.report)
+		}
+	}()
+
+	accounts := make([]string, len(batch))
+	for i, row := range batch {
+		accounts[i] = row.user.UserAccount
+	}
+	users, err := model.ListUsersByAccounts(accounts)
+	if err != nil {
+		log.Printf("Failed to look up imported accounts: %v", err)
+		for _, row := range batch {
+			im.fail(row.index, "failed to look up existing accounts", nil)
+		}
+		return
+	}
+	existing := map[string]*model.User{}
+	for i := range users {
+		key := strings.ToLower(users[i].UserAccount)
+		if _, ok := existing[key]; !ok {
+			existing[key] = &users[i]
+		}
+	}
+
+	var ops []importOp
+	for _, row := range batch {
+		if op, ok := im.plan(row, existing[strings.ToLower(row.user.UserAccount)]); ok {
+			ops = append(ops, op)
+		}
+	}
+	if im.opts.DryRun {
+		for _, op := range ops {
+			im.finish(op)
+		}
+		return
+	}
+	if err := hashImportPasswords(ops); err != nil {
+		log.Printf("Failed to hash imported passwords: %v", err)
+		for _, op := range ops {
+			im.fail(op.row.index, "failed to hash password", nil)
+		}
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		for i := range ops {
+			if err := im.write(tx, &ops[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to write import batch: %v", err)
+		for _, op := range ops {
+			im.fail(op.row.index, "database error, batch rolled back", nil)
+		}
+		return
+	}
+	outbox.Notify()
+	for _, op := range ops {
+		im.finish(op)
+		// 与单个创建、修改一致，新邮箱需要验证；邮件在后台发送，不拖慢导入
+		if op.current == nil || containsField(op.fields, "email") {
+			queueEmailVerification(op.row.user)
+		}
+	}
+}
+
+// plan 决定一行的处理方式，不需要写入时直接记录结果并返回 false
+func (im *userImport) plan(row pendingRow, current *model.User) (importOp, bool) {
+	op := importOp{row: row}
+	if current == nil {
+		var c validator.Collector
+		if c.Require(passwordRule.Field, row.user.UserPassword) {
+			for _, problem := range password.Check(row.user.UserAccount, row.user.UserPassword) {
+				c.Add(passwordRule.Field, problem)
+			}
+		}
+		if err := c.Err(); err != nil {
+			im.fail(row.index, "invalid row", err)
+			return op, false
+		}
+		return op, true
+	}
+
+	switch {
+	case im.opts.OnDuplicate == DuplicateSkip:
+		im.skip(row.index, current.ID, "account already exists")
+		return op, false
+	case im.opts.OnDuplicate == DuplicateFail:
+		im.fail(row.index, "account already exists", nil)
+		return op, false
+	case current.IsDelete != 0:
+		im.fail(row.index, "account belongs to a deleted user", nil)
+		return op, false
+	}
+
+	updated := row.user
+	updated.ID = current.ID
+	updated.UserAccount = current.UserAccount
+	updated.UserPassword = current.UserPassword
+	fields := selectNonZeroFields(&updated)
+	if updated.Email != "" && updated.Email != current.Email {
+		updated.EmailVerified = false
+		fields = append(fields, "emailVerified")
+	} else {
+		updated.EmailVerified = current.EmailVerified
+	}
+	changes := audit.Diff(current, &updated).Only(fields...)
+	if len(changes) == 0 {
+		im.skip(row.index, current.ID, "no changes")
+		return op, false
+	}
+	op.row.user, op.current, op.changes = updated, current, changes
+	// 只写入有变化的字段
+	op.fields = op.fields[:0]
+	for _, field := range fields {
+		if _, ok := changes[field]; ok {
+			op.fields = append(op.fields, field)
+		}
+	}
+	return op, true
+}
+
+// write 在事务中写入一行及其审计记录与领域事件
+func (im *userImport) write(tx *gorm.DB, op *importOp) error {
+	u := &op.row.user
+	if op.current != nil {
+		if err := model.UpdateUser(tx, u, op.fields); err != nil {
+			return err
+		}
+		if err := addUserUpdated(tx, im.meta, u.ID, op.changes); err != nil {
+			return err
+		}
+		return model.AddAuditEvent(tx, newAuditEvent(im.meta, audit.ActionUserUpdate, op.current, op.changes))
+	}
+
+	u.ID, u.UserRole, u.UserStatus, u.EmailVerified = 0, 0, model.UserStatusNormal, false
+	if err := model.AddUser(tx, u); err != nil {
+		return err
+	}
+	if err := model.AddPasswordHistory(tx, u.ID, u.UserPassword, password.HistorySize()); err != nil {
+		return err
+	}
+	if err := addDomainEvent(tx, im.meta, outbox.TypeUserCreated, u.ID, userEventData(u)); err != nil {
+		return err
+	}
+	return model.AddAuditEvent(tx, newAuditEvent(im.meta, audit.ActionUserCreate, u, audit.Diff(nil, u)))
+}
+
+// hashImportPasswords 并行计算新用户的密码摘要，bcrypt 是导入中最耗时的部分
+func hashImportPasswords(ops []importOp) error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	for i := range ops {
+		if ops[i].current != nil {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(u *model.User) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			hash, err := password.Hash(u.UserPassword)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			u.UserPassword = hash
+		}(&ops[i].row.user)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+func (im *userImport) finish(op importOp) {
+	r := &im.report.Rows[op.row.index]
+	r.UserID = op.row.user.ID
+	if op.current != nil {
+		r.Status = ImportUpdated
+		im.report.Updated++
+		return
+	}
+	r.Status = ImportCreated
+	im.report.Created++
+}
+
+func (im *userImport) skip(index int, userID int64, reason string) {
+	r := &im.report.Rows[index]
+	r.Status, r.UserID, r.Reason = ImportSkipped, userID, reason
+	im.report.Skipped++
+}
+
+func (im *userImport) fail(index int, reason string, err error) {
+	r := &im.report.Rows[index]
+	r.Status, r.Reason = ImportFailed, reason
+	var invalid *validator.Error
+	if errors.As(err, &invalid) {
+		r.Violations = invalid.Violations
+	}
+	im.report.Failed++
+}
+
+func copyImportCounts(job *model.ImportJob, r *ImportReport) {
+	job.Total, job.Created, job.Updated, job.Skipped, job.Failed = r.Total, r.Created, r.Updated, r.Skipped, r.Failed
+}
+
+func containsField(fields []string, name string) bool {
+	for _, f := range fields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+func truncateReason(s string) string {
+	if len(s) > 512 {
+		return s[:512]
+	}
+	return s
+}
+
+func newImportJobID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"http_grpc/internal/repository/audit"
+	"http_grpc/internal/repository/lockout"
+	"http_grpc/internal/repository/model"
+	"http_grpc/pkg/errs"
+	"http_grpc/pkg/pool"
+	"log"
+	"strings"
+)
+
+// errInvalidCredentials 账号不存在与密码错误返回同一个错误，避免枚举账号
+var errInvalidCredentials = errs.New(errs.Unauthenticated, "invalid account or password")
+
+// checkLoginAllowed 登录前检查锁定与 IP 限流；Redis 不可用时放行，不影响正常登录
+func checkLoginAllowed(ctx context.Context, account, clientIP string) error {
+	block, err := lockout.Check(ctx, account, clientIP)
+	if err != nil {
+		log.Printf("Login throttle check failed: %v", err)
+		return nil
+	}
+	if block != nil {
+		return errs.RetryLater("too many failed login attempts, try again later", block.RetryAfter)
+	}
+	return nil
+}
+
+// checkRecoveryAllowed 按邮箱与 IP 限制找回密码请求；邮箱不区分大小写，Redis 不可用时放行
+func checkRecoveryAllowed(ctx context.Context, email, clientIP string) error {
+	block, err := lockout.Recovery(ctx, strings.ToLower(email), clientIP)
+	if err != nil {
+		log.Printf("Password recovery throttle check failed: %v", err)
+		return nil
+	}
+	if block != nil {
+		return errs.RetryLater("too many password reset requests, try again later", block.RetryAfter)
+	}
+	return nil
+}
+
+// loginFailed 记录失败并按失败次数延迟返回，账号是否存在都走同一条路径；
+// target 的 ID 在账号不存在时为 0
+func loginFailed(ctx context.Context, target *model.User, clientIP string) error {
+	recordLoginFailure(ctx, target, clientIP)
+	return errInvalidCredentials
+}
+
+// recordLoginFailure 记录审计事件，并把失败计入账号与 IP 的窗口；密码错误与两步验证码错误共用
+func recordLoginFailure(ctx context.Context, target *model.User, clientIP string) {
+	recordAudit(audit.FromContext(ctx), audit.ActionLoginFailed, target, nil)
+	f, err := lockout.Fail(ctx, target.UserAccount, clientIP)
+	if err != nil {
+		log.Printf("Failed to record login failure: %v", err)
+		return
+	}
+	lockout.Wait(ctx, f.Delay)
+}
+
+// loginSucceeded 登录全部步骤完成后清除账号的失败计数
+func loginSucceeded(ctx context.Context, account string) {
+	if err := lockout.Succeed(ctx, account); err != nil {
+		log.Printf("Failed to reset login failures: %v", err)
+	}
+}
+
+// UnlockUser 解除账号的登录锁定（管理员），返回账号之前是否处于锁定状态
+func (s *UserService) UnlockUser(ctx context.Context, id int64) (bool, error) {
+	taskData := pool.TaskDataPool.Get().(*pool.TaskData)
+	defer pool.TaskDataPool.Put(taskData)
+	taskData.Reset()
+	if err := model.GetUserByID(id, &taskData.UserData); err != nil {
+		return false, dbError(err, "user not found")
+	}
+	locked, err := lockout.Unlock(ctx, taskData.UserData.UserAccount)
+	if err != nil {
+		return false, errs.Wrap(errs.Unavailable, "failed to unlock account", err)
+	}
+	recordAudit(audit.FromContext(ctx), audit.ActionUserUnlock, &taskData.UserData, audit.Changes{}.Set("locked", locked, false))
+	return locked, nil
+}
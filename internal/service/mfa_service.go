@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"http_grpc/internal/repository/audit"
+	"http_grpc/internal/repository/mfa"
+	"http_grpc/internal/repository/model"
+	"http_grpc/pkg/errs"
+	"http_grpc/pkg/pool"
+	"time"
+)
+
+// roleAdmin 管理员角色
+const roleAdmin = 1
+
+// MFAEnrollment 登记两步验证时返回的密钥，确认前不生效
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"` // otpauth:// 地址，渲染为二维码
+}
+
+// secondFactor 密码验证通过后决定是否需要第二步：已启用两步验证时创建登录挑战
+func (s *UserService) secondFactor(user *model.User) (*LoginResult, error) {
+	result := &LoginResult{UserID: user.ID, UserAccount: user.UserAccount, UserRole: user.UserRole}
+
+	var setting model.UserMFA
+	err := model.GetUserMFA(user.ID, &setting)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, dbError(err, "user not found")
+	}
+	if err == nil && setting.Enabled {
+		challenge, err := mfa.NewChallenge(context.Background(), user.ID)
+		if err != nil {
+			return nil, errs.Wrap(errs.Unavailable, "failed to create mfa challenge", err)
+		}
+		return &LoginResult{UserID: user.ID, Challenge: challenge}, nil
+	}
+
+	if user.UserRole == roleAdmin && mfa.RequiredForAdmin() {
+		result.UserRole = 0
+		result.MFAEnrollmentRequired = true
+	}
+	return result, nil
+}
+
+// grantedRole 构造调用方身份时使用的角色：取用户当前角色，不超过登录或创建凭证时授予的 maxRole；
+// 要求管理员启用两步验证而该管理员未启用时降为普通用户
+func grantedRole(user *model.User, maxRole int) (int, error) {
+	role := user.UserRole
+	if maxRole < role {
+		role = maxRole
+	}
+	if role != roleAdmin || !mfa.RequiredForAdmin() {
+		return role, nil
+	}
+	var setting model.UserMFA
+	err := model.GetUserMFA(user.ID, &setting)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, dbError(err, "user not found")
+	}
+	if err != nil || !setting.Enabled {
+		return 0, nil
+	}
+	return role, nil
+}
+
+// VerifyMFA 登录第二步：校验挑战令牌与验证码（TOTP 或恢复码）。
+// 验证码错误与密码错误一样计入账号与 clientIP 的失败窗口，账号锁定期间拒绝验证
+func (s *UserService) VerifyMFA(ctx context.Context, challenge, code, clientIP string) (*LoginResult, error) {
+	userID, err := mfa.Attempt(ctx, challenge)
+	if err != nil {
+		if errors.Is(err, mfa.ErrChallengeInvalid) {
+			return nil, errs.New(errs.Unauthenticated, "mfa challenge invalid or expired")
+		}
+		return nil, errs.Wrap(errs.Unavailable, "failed to verify mfa challenge", err)
+	}
+
+	taskData := pool.TaskDataPool.Get().(*pool.TaskData)
+	defer pool.TaskDataPool.Put(taskData)
+	taskData.Reset()
+	user := &taskData.UserData
+	if err := model.GetUserByID(userID, user); err != nil {
+		return nil, dbError(err, "user not found")
+	}
+	// 取得挑战令牌后账号被停用：与挑战失效的响应相同
+	if user.UserStatus == model.UserStatusSuspended {
+		return nil, errs.New(errs.Unauthenticated, "mfa challenge invalid or expired")
+	}
+	if err := checkLoginAllowed(ctx, user.UserAccount, clientIP); err != nil {
+		return nil, err
+	}
+
+	if err := verifySecondFactor(userID, code); err != nil {
+		if errs.Is(err, errs.Unauthenticated) {
+			recordLoginFailure(ctx, user, clientIP)
+		}
+		return nil, err
+	}
+	if !mfa.Complete(ctx, challenge) {
+		return nil, errs.New(errs.Unauthenticated, "mfa challenge invalid or expired")
+	}
+	loginSucceeded(ctx, user.UserAccount)
+	recordLogin(audit.FromContext(ctx), user, true)
+	return &LoginResult{UserID: user.ID, UserAccount: user.UserAccount, UserRole: user.UserRole}, nil
+}
+
+// EnrollMFA 生成新的 TOTP 密钥，调用 ConfirmMFA 验证一次后才会启用
+func (s *UserService) EnrollMFA(userID int64) (*MFAEnrollment, error) {
+	taskData := pool.TaskDataPool.Get().(*pool.TaskData)
+	defer pool.TaskDataPool.Put(taskData)
+	taskData.Reset()
+	if err := model.GetUserByID(userID, &taskData.UserData); err != nil {
+		return nil, dbError(err, "user not found")
+	}
+
+	var setting model.UserMFA
+	err := model.GetUserMFA(userID, &setting)
+	if err == nil && setting.Enabled {
+		return nil, errs.New(errs.Conflict, "two-factor authentication already enabled")
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, dbError(err, "user not found")
+	}
+
+	secret, err := mfa.GenerateSecret()
+	if err != nil {
+		return nil, errs.Wrap(errs.Internal, "failed to generate secret", err)
+	}
+	if err := model.SaveUserMFA(&model.UserMFA{UserID: userID, Secret: secret}); err != nil {
+		return nil, dbError(err, "user not found")
+	}
+	return &MFAEnrollment{Secret: secret, URI: mfa.ProvisioningURI(secret, taskData.UserData.UserAccount)}, nil
+}
+
+// ConfirmMFA 用验证器应用生成的验证码确认登记，启用两步验证并返回一次性恢复码
+func (s *UserService) ConfirmMFA(ctx context.Context, userID int64, code string) ([]string, error) {
+	var setting model.UserMFA
+	if err := model.GetUserMFA(userID, &setting); err != nil {
+		return nil, dbError(err, "two-factor enrollment not started")
+	}
+	if setting.Enabled {
+		return nil, errs.New(errs.Conflict, "two-factor authentication already enabled")
+	}
+	step, ok := mfa.Validate(setting.Secret, code, 0, time.Now())
+	if !ok {
+		return nil, errs.New(errs.InvalidArgument, "invalid verification code")
+	}
+	codes, hashes, err := mfa.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, errs.Wrap(errs.Internal, "failed to generate recovery codes", err)
+	}
+	err = audited(audit.FromContext(ctx), audit.ActionMFAEnable, auditTarget(userID), func(tx *gorm.DB) (audit.Changes, error) {
+		if err := model.EnableUserMFA(tx, userID, step, hashes); err != nil {
+			return nil, err
+		}
+		return audit.Changes{}.Set("enabled", false, true), nil
+	})
+	if err != nil {
+		return nil, dbError(err, "user not found")
+	}
+	return codes, nil
+}
+
+// DisableMFA 关闭两步验证；本人需提供验证码或恢复码，管理员重置时 force 为 true
+func (s *UserService) DisableMFA(ctx context.Context, userID int64, code string, force bool) error {
+	if !force {
+		if err := verifySecondFactor(userID, code); err != nil {
+			return err
+		}
+	}
+	err := audited(audit.FromContext(ctx), audit.ActionMFADisable, auditTarget(userID), func(tx *gorm.DB) (audit.Changes, error) {
+		if err := model.DeleteUserMFA(tx, userID); err != nil {
+			return nil, err
+		}
+		return audit.Changes{}.Set("enabled", true, false), nil
+	})
+	if err != nil {
+		return dbError(err, "user not found")
+	}
+	return nil
+}
+
+// verifySecondFactor 校验 TOTP 验证码，不匹配时尝试作为恢复码使用
+func verifySecondFactor(userID int64, code string) error {
+	invalid := errs.New(errs.Unauthenticated, "invalid verification code")
+	var setting model.UserMFA
+	if err := model.GetUserMFA(userID, &setting); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.New(errs.NotFound, "two-factor authentication not enabled")
+		}
+		return dbError(err, "user not found")
+	}
+	if !setting.Enabled {
+		return errs.New(errs.NotFound, "two-factor authentication not enabled")
+	}
+
+	if step, ok := mfa.Validate(setting.Secret, code, setting.LastUsedStep, time.Now()); ok {
+		advanced, err := model.AdvanceMFAStep(userID, step)
+		if err != nil {
+			return dbError(err, "user not found")
+		}
+		if !advanced {
+			return invalid
+		}
+		return nil
+	}
+
+	used, err := model.UseRecoveryCode(userID, mfa.HashRecoveryCode(code))
+	if err != nil {
+		return dbError(err, "user not found")
+	}
+	if !used {
+		return invalid
+	}
+	return nil
+}
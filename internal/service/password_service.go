@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"http_grpc/internal/repository/audit"
+	"http_grpc/internal/repository/lockout"
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/password"
+	"http_grpc/pkg/errs"
+	"http_grpc/pkg/pool"
+	"http_grpc/pkg/validator"
+	"log"
+)
+
+// UpdatePassword 本人修改密码：校验当前密码与密码策略，成功后撤销其他会话与刷新令牌，
+// keepSessionID 为发起修改的会话（可为空）。当前密码错误计入登录失败次数
+func (s *UserService) UpdatePassword(ctx context.Context, id int64, currentPassword, newPassword, keepSessionID string) error {
+	var c validator.Collector
+	if id <= 0 {
+		c.Add("id", "must be a positive integer")
+	}
+	c.Require("currentPassword", currentPassword)
+	if err := c.Err(); err != nil {
+		return err
+	}
+
+	taskData := pool.TaskDataPool.Get().(*pool.TaskData)
+	defer pool.TaskDataPool.Put(taskData)
+	taskData.Reset()
+	user := &taskData.UserData
+	if err := model.GetUserByID(id, user); err != nil {
+		return dbError(err, "user not found")
+	}
+	if err := checkLoginAllowed(ctx, user.UserAccount, ""); err != nil {
+		return err
+	}
+	if !password.Verify(user.UserPassword, currentPassword) {
+		if f, err := lockout.Fail(ctx, user.UserAccount, ""); err != nil {
+			log.Printf("Failed to record password failure: %v", err)
+		} else {
+			lockout.Wait(ctx, f.Delay)
+		}
+		return errs.New(errs.PermissionDenied, "current password is incorrect")
+	}
+	if err := validateNewPassword("newPassword", user, newPassword); err != nil {
+		return err
+	}
+	return applyNewPassword(audit.FromContext(ctx), audit.ActionPasswordChange, user, newPassword, keepSessionID, "")
+}
+
+// ForceResetPassword 管理员为用户设置新密码，不需要当前密码；撤销该用户的全部会话并解除登录锁定
+func (s *UserService) ForceResetPassword(ctx context.Context, id int64, newPassword string) error {
+	if id <= 0 {
+		var c validator.Collector
+		c.Add("id", "must be a positive integer")
+		return c.Err()
+	}
+	taskData := pool.TaskDataPool.Get().(*pool.TaskData)
+	defer pool.TaskDataPool.Put(taskData)
+	taskData.Reset()
+	user := &taskData.UserData
+	if err := model.GetUserByID(id, user); err != nil {
+		return dbError(err, "user not found")
+	}
+	if err := validateNewPassword("newPassword", user, newPassword); err != nil {
+		return err
+	}
+	if err := applyNewPassword(audit.FromContext(ctx), audit.ActionPasswordForce, user, newPassword, "", ""); err != nil {
+		return err
+	}
+	if _, err := lockout.Unlock(ctx, user.UserAccount); err != nil {
+		log.Printf("Failed to unlock account after password reset: %v", err)
+	}
+	return nil
+}
+
+// validateNewPassword 按密码策略检查新密码；user 已存在时同时检查最近使用过的密码
+func validateNewPassword(field string, user *model.User, plain string) error {
+	var c validator.Collector
+	if !c.Require(field, plain) {
+		return c.Err()
+	}
+	for _, problem := range password.Check(user.UserAccount, plain) {
+		c.Add(field, problem)
+	}
+	if c.Err() == nil && user.ID > 0 && password.HistorySize() > 0 {
+		reused, err := passwordReused(user, plain)
+		if err != nil {
+			return dbError(err, "user not found")
+		}
+		if reused {
+			c.Add(field, fmt.Sprintf("must differ from the last %d passwords", password.HistorySize()))
+		}
+	}
+	return c.Err()
+}
+
+// passwordReused 新密码是否与当前密码或最近的历史密码相同
+func passwordReused(user *model.User, plain string) (bool, error) {
+	if user.UserPassword != "" && password.Verify(user.UserPassword, plain) {
+		return true, nil
+	}
+	hashes, err := model.ListPasswordHistory(user.ID, password.HistorySize())
+	if err != nil {
+		return false, err
+	}
+	for _, hash := range hashes {
+		if password.Verify(hash, plain) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// applyNewPassword 保存新密码并记录审计事件，撤销 keepSessionID 以外的会话、刷新令牌与未使用的重置链接。
+// verifiedEmail 非空时在同一事务中把该邮箱标记为已验证（通过重置邮件设置密码）
+func applyNewPassword(meta audit.Meta, action string, user *model.User, plain, keepSessionID, verifiedEmail string) error {
+	err := audited(meta, action, user, func(tx *gorm.DB) (audit.Changes, error) {
+		if err := setPassword(tx, user.ID, plain); err != nil {
+			return nil, err
+		}
+		// 密码只记录发生了变化，不记录摘要
+		changes := audit.Changes{}.Set("userPassword", audit.Redacted, audit.Redacted)
+		if verifiedEmail != "" && !user.EmailVerified {
+			verified, err := model.MarkEmailVerified(tx, user.ID, verifiedEmail)
+			if err != nil {
+				return nil, dbError(err, "user not found")
+			}
+			if verified {
+				changes.Set("emailVerified", false, true)
+			}
+		}
+		return changes, addPasswordChanged(tx, meta, action, user.ID)
+	})
+	if err != nil {
+		return dbError(err, "user not found")
+	}
+	if err := model.RevokeUserTokens(user.ID, model.TokenPurposePasswordReset); err != nil {
+		log.Printf("Failed to revoke reset tokens: %v", err)
+	}
+	if err := revokeSessions(user.ID, keepSessionID); err != nil {
+		log.Printf("Failed to revoke sessions after password change: %v", err)
+	}
+	return nil
+}
+
+// setPassword 生成摘要并保存，同时记入密码历史；db 可为事务
+func setPassword(db *gorm.DB, userID int64, plain string) error {
+	hash, err := password.Hash(plain)
+	if err != nil {
+		return errs.Wrap(errs.Internal, "failed to hash password", err)
+	}
+	if err := model.SetUserPassword(db, userID, hash, password.HistorySize()); err != nil {
+		return dbError(err, "user not found")
+	}
+	return nil
+}
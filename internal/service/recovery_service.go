@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"http_grpc/internal/repository/audit"
+	"http_grpc/internal/repository/lockout"
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/usertoken"
+	"http_grpc/pkg/errs"
+	"http_grpc/pkg/mailer"
+	"http_grpc/pkg/pool"
+	"log"
+	"time"
+)
+
+// 发送邮件时用到的数据库操作，测试中替换为内存实现
+var (
+	listUsersByEmail = model.ListUsersByEmail
+	addUserToken     = model.AddUserToken
+)
+
+// ForgotPassword 向使用该邮箱的账号发送重置密码邮件，clientIP 用于按 IP 限流（可为空）
+// 无论邮箱是否存在都返回成功，查询与发送在邮件协程池中完成，响应不会暴露账号是否存在；
+// 同一邮箱或 IP 的请求过多时返回 ResourceExhausted
+func (s *UserService) ForgotPassword(ctx context.Context, email, clientIP string) error {
+	if err := ValidateEmail(email); err != nil {
+		return err
+	}
+	if err := checkRecoveryAllowed(ctx, email, clientIP); err != nil {
+		return err
+	}
+	queued := pool.MailPool.TryAddTask(pool.Task{
+		Job: func() error {
+			return sendPasswordReset(email)
+		},
+	})
+	if !queued {
+		log.Printf("Mail queue full, dropped password reset email")
+	}
+	return nil
+}
+
+// ResetPassword 使用邮件中的令牌设置新密码，成功后撤销该用户的全部会话与刷新令牌并解除登录锁定
+func (s *UserService) ResetPassword(ctx context.Context, raw, newPassword string) error {
+	if raw == "" {
+		return errs.New(errs.InvalidArgument, "token is required")
+	}
+	hash := usertoken.Hash(raw)
+	var tok model.UserToken
+	found, err := model.FindUserToken(model.TokenPurposePasswordReset, hash, &tok)
+	if err != nil {
+		return dbError(err, "token not found")
+	}
+	if !found {
+		return errs.New(errs.Unauthenticated, "reset token invalid or expired")
+	}
+
+	taskData := pool.TaskDataPool.Get().(*pool.TaskData)
+	defer pool.TaskDataPool.Put(taskData)
+	taskData.Reset()
+	user := &taskData.UserData
+	if err := model.GetUserByID(tok.UserID, user); err != nil {
+		return dbError(err, "user not found")
+	}
+	if user.IsDelete == 1 {
+		return errs.New(errs.Unauthenticated, "reset token invalid or expired")
+	}
+	// 先检查密码策略再使用令牌，新密码不合格时令牌仍可再次使用
+	if err := validateNewPassword("newPassword", user, newPassword); err != nil {
+		return err
+	}
+	consumed, err := model.ConsumeUserToken(model.TokenPurposePasswordReset, hash, &tok)
+	if err != nil {
+		return dbError(err, "token not found")
+	}
+	if !consumed {
+		return errs.New(errs.Unauthenticated, "reset token invalid or expired")
+	}
+
+	// 能收到重置邮件即证明邮箱属于该用户
+	if err := applyNewPassword(audit.FromContext(ctx), audit.ActionPasswordReset, user, newPassword, "", tok.Email); err != nil {
+		return err
+	}
+	if _, err := lockout.Unlock(ctx, user.UserAccount); err != nil {
+		log.Printf("Failed to unlock account after password reset: %v", err)
+	}
+	return nil
+}
+
+// VerifyEmail 使用邮件中的令牌验证邮箱，令牌发出后邮箱已修改时验证失败
+func (s *UserService) VerifyEmail(ctx context.Context, raw string) error {
+	if raw == "" {
+		return errs.New(errs.InvalidArgument, "token is required")
+	}
+	var tok model.UserToken
+	ok, err := model.ConsumeUserToken(model.TokenPurposeEmailVerify, usertoken.Hash(raw), &tok)
+	if err != nil {
+		return dbError(err, "token not found")
+	}
+	if !ok {
+		return errs.New(errs.Unauthenticated, "verification token invalid or expired")
+	}
+	target := auditTarget(tok.UserID)
+	meta := audit.FromContext(ctx)
+	err = audited(meta, audit.ActionEmailVerify, target, func(tx *gorm.DB) (audit.Changes, error) {
+		updated, err := model.MarkEmailVerified(tx, tok.UserID, tok.Email)
+		if err != nil {
+			return nil, dbError(err, "user not found")
+		}
+		if !updated {
+			return nil, errs.New(errs.Conflict, "email address has changed since the token was sent")
+		}
+		changes := audit.Changes{}.Set("emailVerified", target.EmailVerified, true)
+		return changes, addUserUpdated(tx, meta, tok.UserID, changes)
+	})
+	if err != nil {
+		return dbError(err, "user not found")
+	}
+	return nil
+}
+
+// sendPasswordReset 为使用该邮箱的每个正常账号发送重置邮件
+func sendPasswordReset(email string) error {
+	users, err := listUsersByEmail(email)
+	if err != nil {
+		return err
+	}
+	for i := range users {
+		user := &users[i]
+		if user.UserStatus == model.UserStatusSuspended {
+			continue
+		}
+		raw, err := issueUserToken(user, model.TokenPurposePasswordReset, usertoken.ResetTTL())
+		if err != nil {
+			return err
+		}
+		err = mailer.Send(context.Background(), mailer.Message{
+			To:      user.Email,
+			Subject: "Reset your password",
+			Body: fmt.Sprintf("A password reset was requested for account %s.\n\n"+
+				"Open the link below within %s to choose a new password:\n%s\n\n"+
+				"If you did not request this, you can ignore this email; your password has not been changed.\n",
+				user.UserAccount, formatTTL(usertoken.ResetTTL()), usertoken.ResetLink(raw)),
+		})
+		if err != nil {
+			return fmt.Errorf("send password reset email: %w", err)
+		}
+	}
+	return nil
+}
+
+// queueEmailVerification 把验证邮件交给邮件协程池发送，队列已满时放弃并记录日志，用户可重新请求验证邮件
+func queueEmailVerification(user model.User) {
+	if user.Email == "" {
+		return
+	}
+	queued := pool.MailPool.TryAddTask(pool.Task{
+		Job: func() error {
+			return sendEmailVerification(&user)
+		},
+	})
+	if !queued {
+		log.Printf("Mail queue full, dropped verification email for user %d", user.ID)
+	}
+}
+
+// sendEmailVerification 发送验证邮箱的邮件，邮箱为空时不发送
+func sendEmailVerification(user *model.User) error {
+	if user.Email == "" {
+		return nil
+	}
+	raw, err := issueUserToken(user, model.TokenPurposeEmailVerify, usertoken.VerifyTTL())
+	if err != nil {
+		return err
+	}
+	err = mailer.Send(context.Background(), mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Please confirm that %s is the email address for account %s.\n\n"+
+			"Open the link below within %s:\n%s\n",
+			user.Email, user.UserAccount, formatTTL(usertoken.VerifyTTL()), usertoken.VerifyLink(raw)),
+	})
+	if err != nil {
+		return fmt.Errorf("send verification email: %w", err)
+	}
+	return nil
+}
+
+// issueUserToken 生成一次性令牌并保存摘要，返回发给用户的令牌
+func issueUserToken(user *model.User, purpose string, ttl time.Duration) (string, error) {
+	raw, hash, err := usertoken.New()
+	if err != nil {
+		return "", err
+	}
+	err = addUserToken(&model.UserToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		Hash:      hash,
+		Email:     user.Email,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// formatTTL 邮件中展示的有效期
+func formatTTL(d time.Duration) string {
+	if d >= time.Hour && d%time.Hour == 0 {
+		return fmt.Sprintf("%d hours", d/time.Hour)
+	}
+	return fmt.Sprintf("%d minutes", d/time.Minute)
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"http_grpc/internal/repository/lockout"
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/usertoken"
+	"http_grpc/pkg/errs"
+	"http_grpc/pkg/mailer"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// deadlineMailer 记录发送时 ctx 是否带有截止时间，邮件交给 MemoryMailer 保存
+type deadlineMailer struct {
+	*mailer.MemoryMailer
+	mu        sync.Mutex
+	deadlines []time.Duration
+}
+
+func (m *deadlineMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.mu.Lock()
+	if deadline, ok := ctx.Deadline(); ok {
+		m.deadlines = append(m.deadlines, time.Until(deadline))
+	} else {
+		m.deadlines = append(m.deadlines, 0)
+	}
+	m.mu.Unlock()
+	return m.MemoryMailer.Send(ctx, msg)
+}
+
+// recoveryFixture 内存中的用户、令牌、邮件与 Redis
+type recoveryFixture struct {
+	mailer  *deadlineMailer
+	mu      sync.Mutex
+	tokens  []model.UserToken
+	lookups atomic.Int32 // 邮件协程池中已执行的查询次数
+}
+
+func setupRecovery(t *testing.T, users []model.User, opts lockout.Options) *recoveryFixture {
+	t.Helper()
+	f := &recoveryFixture{mailer: &deadlineMailer{MemoryMailer: mailer.NewMemory()}}
+	prevList, prevAdd := listUsersByEmail, addUserToken
+	listUsersByEmail = func(email string) ([]model.User, error) {
+		defer f.lookups.Add(1)
+		var found []model.User
+		for _, u := range users {
+			if strings.EqualFold(u.Email, email) {
+				found = append(found, u)
+			}
+		}
+		return found, nil
+	}
+	addUserToken = func(tok *model.UserToken) error {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.tokens = append(f.tokens, *tok)
+		return nil
+	}
+	prevMailer := mailer.Current()
+	mailer.Use(f.mailer)
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	opts.Client = client
+	lockout.Setup(opts)
+
+	t.Cleanup(func() {
+		listUsersByEmail, addUserToken = prevList, prevAdd
+		mailer.Use(prevMailer)
+		lockout.Setup(lockout.Options{})
+		client.Close()
+	})
+	return f
+}
+
+// wait 等待邮件协程池完成 lookups 次查询并发出 messages 封邮件，
+// 测试结束前必须等待，避免任务在恢复默认实现后才执行
+func (f *recoveryFixture) wait(t *testing.T, lookups, messages int) []mailer.Message {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		msgs := f.mailer.Messages()
+		if int(f.lookups.Load()) >= lookups && len(msgs) >= messages {
+			return msgs
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d lookups and %d messages, got %d and %d",
+		lookups, messages, f.lookups.Load(), len(f.mailer.Messages()))
+	return nil
+}
+
+func TestForgotPasswordSendsResetLink(t *testing.T) {
+	users := []model.User{
+		{ID: 1, UserAccount: "alice", Email: "alice@example.com"},
+		{ID: 2, UserAccount: "alice2", Email: "alice@example.com", UserStatus: model.UserStatusSuspended},
+	}
+	f := setupRecovery(t, users, lockout.Options{})
+
+	if err := (&UserService{}).ForgotPassword(context.Background(), "alice@example.com", "192.0.2.1"); err != nil {
+		t.Fatalf("ForgotPassword: %v", err)
+	}
+	// 停用的账号不发送
+	msgs := f.wait(t, 1, 1)
+	if len(msgs) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(msgs))
+	}
+	msg := msgs[0]
+	if msg.To != "alice@example.com" || msg.Subject != "Reset your password" {
+		t.Errorf("unexpected message to %q with subject %q", msg.To, msg.Subject)
+	}
+	if !strings.Contains(msg.Body, "account alice.") {
+		t.Errorf("body does not name the account: %s", msg.Body)
+	}
+
+	if len(f.tokens) != 1 {
+		t.Fatalf("issued %d tokens, want 1", len(f.tokens))
+	}
+	tok := f.tokens[0]
+	if tok.UserID != 1 || tok.Purpose != model.TokenPurposePasswordReset || tok.Email != "alice@example.com" {
+		t.Errorf("unexpected token %+v", tok)
+	}
+	// 邮件中的链接携带原始令牌，数据库只保存摘要
+	i := strings.Index(msg.Body, "token=")
+	if i < 0 {
+		t.Fatalf("body has no reset link: %s", msg.Body)
+	}
+	raw := strings.Fields(msg.Body[i+len("token="):])[0]
+	if usertoken.Hash(raw) != tok.Hash {
+		t.Errorf("link token does not match stored hash")
+	}
+	if strings.Contains(msg.Body, tok.Hash) {
+		t.Errorf("body leaks the token hash")
+	}
+
+	if d := f.mailer.deadlines[0]; d <= 0 || d > 30*time.Second {
+		t.Errorf("mail sent without a deadline (remaining %v)", d)
+	}
+}
+
+func TestForgotPasswordUnknownEmailSendsNothing(t *testing.T) {
+	f := setupRecovery(t, nil, lockout.Options{})
+	if err := (&UserService{}).ForgotPassword(context.Background(), "nobody@example.com", "192.0.2.1"); err != nil {
+		t.Fatalf("ForgotPassword: %v", err)
+	}
+	if n := len(f.wait(t, 1, 0)); n != 0 {
+		t.Errorf("sent %d messages for an unknown email", n)
+	}
+}
+
+func TestForgotPasswordLimitedPerEmail(t *testing.T) {
+	users := []model.User{
+		{ID: 1, UserAccount: "alice", Email: "alice@example.com"},
+		{ID: 2, UserAccount: "bob", Email: "bob@example.com"},
+	}
+	f := setupRecovery(t, users, lockout.Options{MaxRecoveryPerEmail: 2, MaxRecoveryPerIP: 100})
+	s := &UserService{}
+	ctx := context.Background()
+
+	// 邮箱不区分大小写，换 IP 也计入同一邮箱
+	for i, email := range []string{"alice@example.com", "Alice@Example.com"} {
+		if err := s.ForgotPassword(ctx, email, fmt.Sprintf("192.0.2.%d", i+1)); err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+	}
+	err := s.ForgotPassword(ctx, "ALICE@example.com", "192.0.2.9")
+	if !errs.Is(err, errs.ResourceExhausted) {
+		t.Fatalf("third request: got %v, want ResourceExhausted", err)
+	}
+	if e := errs.From(err); e.RetryAfter <= 0 {
+		t.Errorf("RetryAfter = %v, want > 0", e.RetryAfter)
+	}
+	if err := s.ForgotPassword(ctx, "bob@example.com", "192.0.2.9"); err != nil {
+		t.Errorf("other email blocked: %v", err)
+	}
+
+	if n := len(f.wait(t, 3, 3)); n != 3 {
+		t.Errorf("sent %d messages, want 3", n)
+	}
+}
+
+func TestForgotPasswordLimitedPerIP(t *testing.T) {
+	f := setupRecovery(t, nil, lockout.Options{MaxRecoveryPerEmail: 100, MaxRecoveryPerIP: 2})
+	s := &UserService{}
+	ctx := context.Background()
+
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		if err := s.ForgotPassword(ctx, email, "198.51.100.1"); err != nil {
+			t.Fatalf("%s: %v", email, err)
+		}
+	}
+	if err := s.ForgotPassword(ctx, "c@example.com", "198.51.100.1"); !errs.Is(err, errs.ResourceExhausted) {
+		t.Fatalf("third request from the same IP: got %v, want ResourceExhausted", err)
+	}
+	if err := s.ForgotPassword(ctx, "c@example.com", "198.51.100.2"); err != nil {
+		t.Errorf("other IP blocked: %v", err)
+	}
+	if n := len(f.wait(t, 3, 0)); n != 0 {
+		t.Errorf("sent %d messages for unknown emails", n)
+	}
+}
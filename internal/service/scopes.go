@@ -0,0 +1,51 @@
+package service
+
+// API Key 的权限范围
+const (
+	ScopeUsersRead     = "users:read"     // 查询用户、用户列表（列表仍要求管理员）
+	ScopeUsersWrite    = "users:write"    // 修改资料、修改密码、删除、停用、解锁
+	ScopeSessionsAdmin = "sessions:admin" // 查看与撤销会话
+	ScopeAuditRead     = "audit:read"     // 查询审计事件（仍要求管理员）
+	ScopeWebhooksAdmin = "webhooks:admin" // 管理 Webhook 与查看投递日志（仍要求管理员）
+)
+
+// Scopes 全部可授予的权限范围
+var Scopes = []string{ScopeUsersRead, ScopeUsersWrite, ScopeSessionsAdmin, ScopeAuditRead, ScopeWebhooksAdmin}
+
+// MethodScopes UserService 各方法需要的权限范围，未列出的方法不对 API Key 开放
+var MethodScopes = map[string]string{
+	"GetUserByID":           ScopeUsersRead,
+	"GetUserByAccount":      ScopeUsersRead,
+	"ListUsers":             ScopeUsersRead,
+	"WatchUsers":            ScopeUsersRead,
+	"ExportUsers":           ScopeUsersRead,
+	"BulkCreateUsers":       ScopeUsersWrite,
+	"GetImportJob":          ScopeUsersWrite,
+	"UpdateUser":            ScopeUsersWrite,
+	"UpdatePassword":        ScopeUsersWrite,
+	"ForceResetPassword":    ScopeUsersWrite,
+	"DeleteUser":            ScopeUsersWrite,
+	"SuspendUser":           ScopeUsersWrite,
+	"UnlockUser":            ScopeUsersWrite,
+	"ListSessions":          ScopeSessionsAdmin,
+	"RevokeSession":         ScopeSessionsAdmin,
+	"RevokeUserSessions":    ScopeSessionsAdmin,
+	"ListAuditEvents":       ScopeAuditRead,
+	"CreateWebhook":         ScopeWebhooksAdmin,
+	"ListWebhooks":          ScopeWebhooksAdmin,
+	"GetWebhook":            ScopeWebhooksAdmin,
+	"UpdateWebhook":         ScopeWebhooksAdmin,
+	"DeleteWebhook":         ScopeWebhooksAdmin,
+	"TestWebhook":           ScopeWebhooksAdmin,
+	"ListWebhookDeliveries": ScopeWebhooksAdmin,
+	"RedeliverWebhook":      ScopeWebhooksAdmin,
+}
+
+func knownScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
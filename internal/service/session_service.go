@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"http_grpc/internal/repository/audit"
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/session"
+	"http_grpc/internal/repository/token"
+	"http_grpc/pkg/database"
+	"http_grpc/pkg/errs"
+	"log"
+)
+
+// ListSessions 列出用户的活跃会话，currentID 为发起请求的会话（可为空）
+func (s *UserService) ListSessions(ctx context.Context, userID int64, currentID string) ([]session.Info, error) {
+	sessions, err := session.ListUserSessions(ctx, userID, currentID)
+	if err != nil {
+		return nil, errs.Wrap(errs.Unavailable, "failed to list sessions", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession 撤销用户的单个会话，id 为会话列表中返回的 ID
+func (s *UserService) RevokeSession(ctx context.Context, userID int64, id string) error {
+	found, err := session.RevokeSession(ctx, userID, id)
+	if err != nil {
+		return errs.Wrap(errs.Unavailable, "failed to revoke session", err)
+	}
+	if !found {
+		return errs.New(errs.NotFound, "session not found")
+	}
+	recordAudit(audit.FromContext(ctx), audit.ActionSessionRevoke, auditTarget(userID), audit.Changes{}.Set("sessionId", id, nil))
+	return nil
+}
+
+// RevokeUserSessions 撤销用户的全部会话、刷新令牌与 API Key（管理员），返回撤销的会话数量
+func (s *UserService) RevokeUserSessions(ctx context.Context, userID int64) (int, error) {
+	if err := AuthorizeAdmin(ctx); err != nil {
+		return 0, err
+	}
+	n, err := session.RevokeUserSessions(ctx, userID, "")
+	if err != nil {
+		return n, errs.Wrap(errs.Unavailable, "failed to revoke sessions", err)
+	}
+	if _, err := token.RevokeUser(ctx, userID); err != nil {
+		return n, errs.Wrap(errs.Unavailable, "failed to revoke refresh tokens", err)
+	}
+	keys, err := model.RevokeUserAPIKeys(database.DB, userID)
+	if err != nil {
+		return n, errs.Wrap(errs.Unavailable, "failed to revoke api keys", err)
+	}
+	recordAudit(audit.FromContext(ctx), audit.ActionSessionRevoke, auditTarget(userID),
+		audit.Changes{}.Set("sessions", n, 0).Set("apiKeys", keys, 0))
+	return n, nil
+}
+
+// revokeSessions 在异步任务中撤销会话与刷新令牌（修改密码、停用、删除账号之后）
+func revokeSessions(userID int64, keepID string) error {
+	ctx := context.Background()
+	n, err := session.RevokeUserSessions(ctx, userID, keepID)
+	if err != nil {
+		return err
+	}
+	families, err := token.RevokeUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if n > 0 || families > 0 {
+		log.Printf("Revoked %d sessions and %d refresh token families of user %d", n, families, userID)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/token"
+	"http_grpc/pkg/errs"
+	"http_grpc/pkg/pool"
+)
+
+// IssueTokens 登录成功后签发访问令牌与刷新令牌
+func (s *UserService) IssueTokens(ctx context.Context, result *LoginResult) (*token.Pair, error) {
+	if !token.Enabled() {
+		return nil, errs.New(errs.Unavailable, "token authentication is disabled")
+	}
+	pair, err := token.IssuePair(ctx, token.Identity{
+		UserID:      result.UserID,
+		UserAccount: result.UserAccount,
+		UserRole:    result.UserRole,
+	})
+	if err != nil {
+		return nil, errs.Wrap(errs.Unavailable, "failed to issue tokens", err)
+	}
+	return pair, nil
+}
+
+// RefreshTokens 轮换刷新令牌并按用户当前信息签发新的访问令牌
+func (s *UserService) RefreshTokens(ctx context.Context, refreshToken string) (*token.Pair, error) {
+	if !token.Enabled() {
+		return nil, errs.New(errs.Unavailable, "token authentication is disabled")
+	}
+	grant, next, err := token.Rotate(ctx, refreshToken)
+	if err != nil {
+		if errors.Is(err, token.ErrTokenReused) || errors.Is(err, token.ErrInvalidToken) {
+			return nil, errs.Wrap(errs.Unauthenticated, "invalid refresh token", err)
+		}
+		return nil, errs.Wrap(errs.Unavailable, "failed to refresh tokens", err)
+	}
+
+	taskData := pool.TaskDataPool.Get().(*pool.TaskData)
+	defer pool.TaskDataPool.Put(taskData)
+	taskData.Reset()
+
+	// 账号已删除或停用时撤销刚换发的令牌族
+	user := &taskData.UserData
+	if err := model.GetUserByID(grant.UserID, user); err != nil || user.IsDelete == 1 || user.UserStatus == model.UserStatusSuspended {
+		_ = token.RevokeRefresh(ctx, next)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dbError(err, "user not found")
+		}
+		return nil, errs.New(errs.Unauthenticated, "invalid refresh token")
+	}
+
+	// 角色不超过登录时授予的角色，管理员在此期间关闭两步验证时同样降级
+	role, err := grantedRole(user, grant.MaxRole)
+	if err != nil {
+		return nil, err
+	}
+	pair, err := token.Complete(token.Identity{
+		UserID:      user.ID,
+		UserAccount: user.UserAccount,
+		UserRole:    role,
+	}, next)
+	if err != nil {
+		return nil, errs.Wrap(errs.Unavailable, "failed to issue tokens", err)
+	}
+	return pair, nil
+}
+
+// RevokeRefreshToken 退出登录时撤销刷新令牌
+func (s *UserService) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	if !token.Enabled() || refreshToken == "" {
+		return nil
+	}
+	if err := token.RevokeRefresh(ctx, refreshToken); err != nil {
+		return errs.Wrap(errs.Unavailable, "failed to revoke refresh token", err)
+	}
+	return nil
+}
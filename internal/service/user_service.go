@@ -1,11 +1,18 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"http_grpc/internal/repository/audit"
 	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/outbox"
+	"http_grpc/internal/repository/password"
 	"http_grpc/internal/repository/session"
+	"http_grpc/internal/repository/token"
+	"http_grpc/pkg/database"
+	"http_grpc/pkg/errs"
 	"http_grpc/pkg/pool"
 	"http_grpc/pkg/utils"
 )
@@ -18,75 +25,239 @@ func NewUserService(routinePool *pool.RoutinePool) *UserService {
 	return &UserService{routinePool: routinePool}
 }
 
-func (s *UserService) CreateUser(user *model.User) error {
-	if user.UserAccount == "" || user.UserPassword == "" {
-		return errors.New("account and password are required")
+func (s *UserService) CreateUser(ctx context.Context, user *model.User) error {
+	if err := ValidateCreateUser(user); err != nil {
+		return err
 	}
 
+	if exists, err := model.FindUserByAccount(user.UserAccount); err != nil {
+		return dbError(err, "user not found")
+	} else if exists {
+		return errs.New(errs.AlreadyExists, "account already exists")
+	}
+
+	hash, err := password.Hash(user.UserPassword)
+	if err != nil {
+		return errs.Wrap(errs.Internal, "failed to hash password", err)
+	}
+
+	// user 来自对象池，异步任务需持有副本；ctx 随请求结束，只保留审计信息
+	newUser := *user
+	newUser.UserPassword = hash
+	meta := audit.FromContext(ctx)
 	s.routinePool.AddTask(pool.Task{
 		Job: func() error {
-			if result, err := model.FindUserByAccount(user.UserAccount); err != nil {
+			newUser.EmailVerified = false
+			err := audited(meta, audit.ActionUserCreate, &newUser, func(tx *gorm.DB) (audit.Changes, error) {
+				if err := model.AddUser(tx, &newUser); err != nil {
+					return nil, err
+				}
+				if err := model.AddPasswordHistory(tx, newUser.ID, hash, password.HistorySize()); err != nil {
+					return nil, err
+				}
+				if err := addDomainEvent(tx, meta, outbox.TypeUserCreated, newUser.ID, userEventData(&newUser)); err != nil {
+					return nil, err
+				}
+				return audit.Diff(nil, &newUser), nil
+			})
+			if err != nil {
 				return err
-			} else if result {
-				return gorm.ErrDuplicatedKey
 			}
-			return model.AddUser(user)
+			// 邮件交给邮件协程池，不占用处理请求的协程
+			queueEmailVerification(newUser)
+			return nil
 		},
 	})
 	return nil
 }
 
-func (s *UserService) Login(account, password string) (int64, string, error) {
+// LoginResult 登录成功后的用户身份
+type LoginResult struct {
+	UserID      int64
+	UserAccount string
+	UserRole    int
+
+	// Challenge 非空表示已启用两步验证，需要调用 VerifyMFA 完成登录，此时其余字段只有 UserID 有效
+	Challenge string
+	// MFAEnrollmentRequired 管理员未启用两步验证，本次登录只授予普通用户权限
+	MFAEnrollmentRequired bool
+}
+
+// Login 校验账号密码，clientIP 用于按 IP 统计失败次数（可为空）
+// 账号不存在、已停用与密码错误返回相同的错误并经过相同的比较与延迟，锁定期间返回 ResourceExhausted
+func (s *UserService) Login(ctx context.Context, account, plain, clientIP string) (*LoginResult, error) {
+	if err := ValidateLogin(account, plain); err != nil {
+		return nil, err
+	}
+	if err := checkLoginAllowed(ctx, account, clientIP); err != nil {
+		return nil, err
+	}
 
 	taskData := pool.TaskDataPool.Get().(*pool.TaskData)
 	defer pool.TaskDataPool.Put(taskData)
 	taskData.Reset()
 
+	// 账号不存在时 stored 为空，password.Verify 仍会做一次同样耗时的比较
+	stored := ""
 	err := model.GetUserByAccount(account, &taskData.UserData)
-	if err != nil {
-		return -1, "", err
+	if err == nil {
+		stored = taskData.UserData.UserPassword
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, dbError(err, "user not found")
 	}
-	if taskData.UserData.UserPassword != password {
-		return -1, "", errors.New("incorrect password")
+	// 停用的账号与密码错误返回相同的结果，不向调用方确认密码是否正确
+	suspended := taskData.UserData.UserStatus == model.UserStatusSuspended
+	if !password.Verify(stored, plain) || err != nil || suspended {
+		return nil, loginFailed(ctx, &model.User{ID: taskData.UserData.ID, UserAccount: account}, clientIP)
 	}
-	return taskData.UserData.ID, taskData.UserData.UserAccount, nil
+	// 旧版本的明文密码或 bcrypt 成本变化时，用本次输入的密码重新生成摘要
+	if password.NeedsRehash(stored) {
+		userID := taskData.UserData.ID
+		s.routinePool.AddTask(pool.Task{
+			Job: func() error {
+				return setPassword(database.DB, userID, plain)
+			},
+		})
+	}
+	result, err := s.secondFactor(&taskData.UserData)
+	// 需要两步验证时在 VerifyMFA 成功后清除失败计数并记录登录
+	if err == nil && result.Challenge == "" {
+		loginSucceeded(ctx, account)
+		recordLogin(audit.FromContext(ctx), &taskData.UserData, false)
+	}
+	return result, err
 }
 
 func (s *UserService) GetUserByID(id int64, user *model.User) error {
-	return model.GetUserByID(id, user)
+	if err := model.GetUserByID(id, user); err != nil {
+		return dbError(err, "user not found")
+	}
+	return nil
 }
 
 func (s *UserService) GetUserByAccount(account string, user *model.User) error {
-	return model.GetUserByAccount(account, user)
+	if err := ValidateAccount(account); err != nil {
+		return err
+	}
+	if err := model.GetUserByAccount(account, user); err != nil {
+		return dbError(err, "user not found")
+	}
+	return nil
 }
 
-func (s *UserService) UpdatePassword(id int64, newPassword string) {
+func (s *UserService) ListUsers(page, size int) ([]model.User, error) {
+	users, err := model.ListUsers(page, size)
+	if err != nil {
+		return nil, dbError(err, "user not found")
+	}
+	return users, nil
+}
+
+// DeleteUser 删除账号（管理员）并撤销其全部会话与 API Key，鉴权通过后异步执行
+func (s *UserService) DeleteUser(ctx context.Context, id int64) error {
+	if err := AuthorizeAdmin(ctx); err != nil {
+		return err
+	}
+	meta := audit.FromContext(ctx)
 	s.routinePool.AddTask(pool.Task{
 		Job: func() error {
-			return model.UpdateUserPassword(id, newPassword)
+			var user model.User
+			if err := model.GetUserByID(id, &user); err != nil {
+				return err
+			}
+			err := audited(meta, audit.ActionUserDelete, &user, func(tx *gorm.DB) (audit.Changes, error) {
+				if err := model.DeleteUser(tx, id); err != nil {
+					return nil, err
+				}
+				if _, err := model.RevokeUserAPIKeys(tx, id); err != nil {
+					return nil, err
+				}
+				if err := addDomainEvent(tx, meta, outbox.TypeUserDeleted, id, userEventData(&user)); err != nil {
+					return nil, err
+				}
+				return audit.Changes{}.Set("isDelete", user.IsDelete, 1), nil
+			})
+			if err != nil {
+				return err
+			}
+			return revokeSessions(id, "")
 		},
 	})
+	return nil
 }
 
-func (s *UserService) ListUsers(page, size int) ([]model.User, error) {
-	return model.ListUsers(page, size)
-}
-
-func (s *UserService) DeleteUser(id int64) {
+// SuspendUser 停用账号（管理员）并撤销其全部会话与 API Key，鉴权通过后异步执行
+func (s *UserService) SuspendUser(ctx context.Context, id int64) error {
+	if err := AuthorizeAdmin(ctx); err != nil {
+		return err
+	}
+	meta := audit.FromContext(ctx)
 	s.routinePool.AddTask(pool.Task{
 		Job: func() error {
-			return model.DeleteUser(id)
+			var user model.User
+			if err := model.GetUserByID(id, &user); err != nil {
+				return err
+			}
+			err := audited(meta, audit.ActionUserSuspend, &user, func(tx *gorm.DB) (audit.Changes, error) {
+				if err := model.UpdateUserStatus(tx, id, model.UserStatusSuspended); err != nil {
+					return nil, err
+				}
+				if _, err := model.RevokeUserAPIKeys(tx, id); err != nil {
+					return nil, err
+				}
+				changes := audit.Changes{}.Set("userStatus", user.UserStatus, model.UserStatusSuspended)
+				return changes, addUserUpdated(tx, meta, id, changes)
+			})
+			if err != nil {
+				return err
+			}
+			return revokeSessions(id, "")
 		},
 	})
+	return nil
 }
 
-func (s *UserService) UpdateUser(user *model.User) {
+func (s *UserService) UpdateUser(ctx context.Context, user *model.User) error {
+	if err := ValidateUpdateUser(user); err != nil {
+		return err
+	}
+	// user 来自对象池，异步任务需持有副本
+	updated := *user
+	meta := audit.FromContext(ctx)
 	s.routinePool.AddTask(pool.Task{
 		Job: func() error {
-			fields := selectNonZeroFields(user)
-			return model.UpdateUser(user, fields)
+			fields := selectNonZeroFields(&updated)
+			var current model.User
+			if err := model.GetUserByID(updated.ID, &current); err != nil {
+				return err
+			}
+			updated.UserAccount = current.UserAccount
+			// 邮箱变更后需要重新验证
+			emailChanged := updated.Email != "" && current.Email != updated.Email
+			if emailChanged {
+				updated.EmailVerified = false
+				fields = append(fields, "emailVerified")
+			} else {
+				updated.EmailVerified = current.EmailVerified
+			}
+			err := audited(meta, audit.ActionUserUpdate, &current, func(tx *gorm.DB) (audit.Changes, error) {
+				if err := model.UpdateUser(tx, &updated, fields); err != nil {
+					return nil, err
+				}
+				// 字段名与 JSON 名一致，只比较本次更新的字段
+				changes := audit.Diff(&current, &updated).Only(fields...)
+				return changes, addUserUpdated(tx, meta, current.ID, changes)
+			})
+			if err != nil {
+				return err
+			}
+			if emailChanged {
+				queueEmailVerification(updated)
+			}
+			return nil
 		},
 	})
+	return nil
 }
 
 func selectNonZeroFields(user *model.User) []string {
@@ -109,30 +280,28 @@ func selectNonZeroFields(user *model.User) []string {
 	return fields
 }
 
-// CheckUserAuthorization 检查当前用户是否有权限
-func (s *UserService) CheckUserAuthorization(c *gin.Context, targetUserID int64) (bool, error) {
-	store := session.GetSession(c)
+// CurrentIdentity 当前调用方：优先使用访问令牌，其次使用会话 Cookie
+func CurrentIdentity(c *gin.Context) (userID int64, userRole int, ok bool) {
+	if id := token.FromContext(c.Request.Context()); id != nil {
+		return id.UserID, id.UserRole, true
+	}
+	store := session.Current(c)
+	userID, ok = store.Values[session.KeyUserID].(int64)
+	userRole, _ = store.Values[session.KeyUserRole].(int)
+	return userID, userRole, ok
+}
 
-	// 对单个用户进行操作
+// CheckUserAuthorization 检查当前用户是否有权限，无权限时直接写回错误响应；
+// targetUserID 为 -1 时要求管理员，否则要求本人或管理员，规则与 gRPC 相同
+func (s *UserService) CheckUserAuthorization(c *gin.Context, targetUserID int64) (bool, error) {
+	ctx := CallerContext(c)
+	err := AuthorizeAdmin(ctx)
 	if targetUserID != -1 {
-		currentUserID, ok := store.Values["userID"].(int64)
-		if !ok {
-			utils.Fail(c, utils.UnauthorizedCode, "Unauthorized")
-			return false, nil
-		}
-		// 如果是本人，允许
-		if currentUserID == targetUserID {
-			return true, nil
-		}
-	}
-
-	// 如果是管理员（userRole == 1），允许对全体用户操作
-	userRole, ok := store.Values["userRole"].(int)
-	if ok && userRole == 1 {
-		return true, nil
-	}
-
-	// 否则无权限
-	utils.Fail(c, utils.UnauthorizedCode, "Unauthorized")
-	return false, nil
+		err = AuthorizeCaller(ctx, targetUserID)
+	}
+	if err != nil {
+		utils.FailErr(c, err)
+		return false, err
+	}
+	return true, nil
 }
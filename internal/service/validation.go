@@ -0,0 +1,83 @@
+package service
+
+import (
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/password"
+	"http_grpc/pkg/validator"
+	"regexp"
+)
+
+// 用户字段校验规则，HTTP 与 gRPC 共用同一份声明
+var (
+	accountRule  = validator.Rule{Field: "userAccount", Checks: []validator.Check{validator.Length(4, 32), validator.Pattern(regexp.MustCompile(`^[A-Za-z0-9_]+$`), "may only contain letters, digits and underscores")}}
+	passwordRule = validator.Rule{Field: "userPassword"} // 强度要求由 password.Check 按密码策略检查
+	usernameRule = validator.Rule{Field: "username", Checks: []validator.Check{validator.Length(1, 64)}}
+	avatarRule   = validator.Rule{Field: "avatarUrl", Checks: []validator.Check{validator.Length(1, 1024), validator.HTTPURL()}}
+	phoneRule    = validator.Rule{Field: "phone", Checks: []validator.Check{validator.Pattern(regexp.MustCompile(`^\+?[0-9]{6,20}$`), "must be 6-20 digits with an optional leading +")}}
+	emailRule    = validator.Rule{Field: "email", Checks: []validator.Check{validator.Length(3, 254), validator.Email()}}
+)
+
+const (
+	genderMin = 0 // 0-未知
+	genderMax = 2 // 1-男 2-女
+)
+
+// validateProfile 校验可选的资料字段
+func validateProfile(c *validator.Collector, user *model.User) {
+	c.Apply(usernameRule, user.Username)
+	c.Apply(avatarRule, user.AvatarUrl)
+	c.Range("gender", int(user.Gender), genderMin, genderMax)
+	c.Apply(phoneRule, user.Phone)
+	c.Apply(emailRule, user.Email)
+}
+
+// ValidateCreateUser 创建用户参数校验
+func ValidateCreateUser(user *model.User) error {
+	var c validator.Collector
+	if c.Require(accountRule.Field, user.UserAccount) {
+		c.Apply(accountRule, user.UserAccount)
+	}
+	if c.Require(passwordRule.Field, user.UserPassword) {
+		for _, problem := range password.Check(user.UserAccount, user.UserPassword) {
+			c.Add(passwordRule.Field, problem)
+		}
+	}
+	validateProfile(&c, user)
+	return c.Err()
+}
+
+// ValidateUpdateUser 更新用户资料参数校验
+func ValidateUpdateUser(user *model.User) error {
+	var c validator.Collector
+	if user.ID <= 0 {
+		c.Add("id", "must be a positive integer")
+	}
+	validateProfile(&c, user)
+	return c.Err()
+}
+
+// ValidateLogin 登录参数校验
+func ValidateLogin(account, plain string) error {
+	var c validator.Collector
+	c.Require(accountRule.Field, account)
+	c.Require(passwordRule.Field, plain)
+	return c.Err()
+}
+
+// ValidateAccount 账号查询参数校验
+func ValidateAccount(account string) error {
+	var c validator.Collector
+	if c.Require(accountRule.Field, account) {
+		c.Apply(accountRule, account)
+	}
+	return c.Err()
+}
+
+// ValidateEmail 邮箱参数校验
+func ValidateEmail(email string) error {
+	var c validator.Collector
+	if c.Require(emailRule.Field, email) {
+		c.Apply(emailRule, email)
+	}
+	return c.Err()
+}
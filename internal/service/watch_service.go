@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"http_grpc/internal/repository/watch"
+	"http_grpc/pkg/errs"
+	"http_grpc/pkg/validator"
+	"strings"
+	"time"
+)
+
+// watchMaxUserIDs 一次订阅最多指定的用户数
+const watchMaxUserIDs = 100
+
+// WatchRequest 订阅用户变更的条件，零值表示不限制
+type WatchRequest struct {
+	Types       []string // user.created | user.updated | user.deleted
+	UserIDs     []int64
+	LastEventID string // 断线重连时传入最后收到的消息 ID
+}
+
+// WatchUsers 推送用户的创建、修改与删除（管理员），调用方负责鉴权；
+// 持续到 ctx 结束或 send 返回错误，服务停机时返回 Unavailable，客户端应携带最后的消息 ID 重连
+func (s *UserService) WatchUsers(ctx context.Context, req WatchRequest, send func(watch.Message) error) error {
+	if err := validateWatch(req); err != nil {
+		return err
+	}
+	err := watch.Watch(ctx, req.LastEventID, watch.Filter{Types: req.Types, UserIDs: req.UserIDs}, send)
+	switch {
+	case errors.Is(err, watch.ErrTooManyWatchers):
+		return errs.RetryLater("too many watchers, try again later", 5*time.Second)
+	case errors.Is(err, watch.ErrClosed):
+		return errs.New(errs.Unavailable, "server is shutting down, reconnect with the last event id")
+	}
+	return err
+}
+
+func validateWatch(req WatchRequest) error {
+	var c validator.Collector
+	for _, t := range req.Types {
+		if !watchedType(t) {
+			c.Add("types", "must be one of "+strings.Join(watch.Types, ", "))
+			break
+		}
+	}
+	if len(req.UserIDs) > watchMaxUserIDs {
+		c.Add("userIds", "must contain at most 100 IDs")
+	}
+	for _, id := range req.UserIDs {
+		if id <= 0 {
+			c.Add("userIds", "must be positive integers")
+			break
+		}
+	}
+	return c.Err()
+}
+
+func watchedType(t string) bool {
+	for _, known := range watch.Types {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
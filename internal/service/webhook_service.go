@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"http_grpc/internal/repository/audit"
+	"http_grpc/internal/repository/model"
+	"http_grpc/internal/repository/outbox"
+	"http_grpc/internal/repository/webhook"
+	"http_grpc/pkg/errs"
+	"http_grpc/pkg/validator"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	webhookURLMaxLength         = 1024
+	webhookDescriptionMaxLength = 256
+	webhookMaxPageSize          = 100
+)
+
+// WebhookRequest 创建或更新 Webhook 的参数；更新时零值字段保持不变
+type WebhookRequest struct {
+	URL          string   `json:"url"`
+	Events       []string `json:"events"`  // 订阅的事件类型，空或 ["*"] 表示全部
+	Enabled      *bool    `json:"enabled"` // 创建时默认启用
+	Description  string   `json:"description"`
+	RotateSecret bool     `json:"rotateSecret"` // 更新时生成新的签名密钥
+}
+
+// CreateWebhook 创建 Webhook（管理员），返回的签名密钥只出现这一次
+func (s *UserService) CreateWebhook(ctx context.Context, req WebhookRequest) (string, *model.Webhook, error) {
+	if err := validateWebhook(req, true); err != nil {
+		return "", nil, err
+	}
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return "", nil, errs.Wrap(errs.Internal, "failed to generate secret", err)
+	}
+	now := time.Now()
+	hook := &model.Webhook{
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      strings.Join(req.Events, ","),
+		Enabled:     req.Enabled == nil || *req.Enabled,
+		Description: req.Description,
+		CreateTime:  now,
+		UpdateTime:  now,
+	}
+	err = audited(audit.FromContext(ctx), audit.ActionWebhookCreate, nil, func(tx *gorm.DB) (audit.Changes, error) {
+		if err := model.AddWebhook(tx, hook); err != nil {
+			return nil, err
+		}
+		return audit.Diff(nil, hook), nil
+	})
+	if err != nil {
+		return "", nil, dbError(err, "webhook not found")
+	}
+	return secret, hook, nil
+}
+
+// ListWebhooks 列出全部 Webhook，不含签名密钥
+func (s *UserService) ListWebhooks() ([]model.Webhook, error) {
+	hooks, err := model.ListWebhooks()
+	if err != nil {
+		return nil, dbError(err, "webhook not found")
+	}
+	return hooks, nil
+}
+
+// GetWebhook 查询单个 Webhook
+func (s *UserService) GetWebhook(id int64) (*model.Webhook, error) {
+	var hook model.Webhook
+	if err := model.GetWebhook(id, &hook); err != nil {
+		return nil, dbError(err, "webhook not found")
+	}
+	return &hook, nil
+}
+
+// UpdateWebhook 更新 Webhook，RotateSecret 为 true 时返回新的签名密钥，否则返回空字符串
+func (s *UserService) UpdateWebhook(ctx context.Context, id int64, req WebhookRequest) (string, *model.Webhook, error) {
+	if err := validateWebhook(req, false); err != nil {
+		return "", nil, err
+	}
+	current, err := s.GetWebhook(id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	updated := *current
+	var fields []string
+	if req.URL != "" {
+		updated.URL = req.URL
+		fields = append(fields, "url")
+	}
+	if req.Events != nil {
+		updated.Events = strings.Join(req.Events, ",")
+		fields = append(fields, "events")
+	}
+	if req.Enabled != nil {
+		updated.Enabled = *req.Enabled
+		fields = append(fields, "enabled")
+	}
+	if req.Description != "" {
+		updated.Description = req.Description
+		fields = append(fields, "description")
+	}
+	secret := ""
+	if req.RotateSecret {
+		if secret, err = webhook.GenerateSecret(); err != nil {
+			return "", nil, errs.Wrap(errs.Internal, "failed to generate secret", err)
+		}
+		updated.Secret = secret
+		fields = append(fields, "secret")
+	}
+	if len(fields) == 0 {
+		return "", current, nil
+	}
+
+	err = audited(audit.FromContext(ctx), audit.ActionWebhookUpdate, nil, func(tx *gorm.DB) (audit.Changes, error) {
+		if err := model.UpdateWebhook(tx, &updated, fields); err != nil {
+			return nil, err
+		}
+		changes := audit.Diff(current, &updated).Set("id", id, id)
+		if req.RotateSecret {
+			changes.Set("secret", audit.Redacted, audit.Redacted)
+		}
+		return changes, nil
+	})
+	if err != nil {
+		return "", nil, dbError(err, "webhook not found")
+	}
+	return secret, &updated, nil
+}
+
+// DeleteWebhook 删除 Webhook，等待中的重试不再发送
+func (s *UserService) DeleteWebhook(ctx context.Context, id int64) error {
+	current, err := s.GetWebhook(id)
+	if err != nil {
+		return err
+	}
+	err = audited(audit.FromContext(ctx), audit.ActionWebhookDelete, nil, func(tx *gorm.DB) (audit.Changes, error) {
+		if err := model.DeleteWebhook(tx, id); err != nil {
+			return nil, err
+		}
+		return audit.Changes{}.Set("id", id, nil).Set("url", current.URL, nil), nil
+	})
+	if err != nil {
+		return dbError(err, "webhook not found")
+	}
+	return nil
+}
+
+// TestWebhook 向 Webhook 发送测试事件，返回投递记录，结果通过投递日志查看
+func (s *UserService) TestWebhook(id int64) (*model.WebhookDelivery, error) {
+	hook, err := s.GetWebhook(id)
+	if err != nil {
+		return nil, err
+	}
+	d, err := webhook.SendTest(hook)
+	if err != nil {
+		return nil, dbError(err, "webhook not found")
+	}
+	return d, nil
+}
+
+// ListWebhookDeliveries 分页查询 Webhook 的投递日志
+func (s *UserService) ListWebhookDeliveries(id int64, page, size int) ([]model.WebhookDelivery, int64, error) {
+	var c validator.Collector
+	if page < 1 {
+		c.Add("page", "must be a positive integer")
+	}
+	if size < 1 || size > webhookMaxPageSize {
+		c.Add("size", "must be between 1 and 100")
+	}
+	if err := c.Err(); err != nil {
+		return nil, 0, err
+	}
+	if _, err := s.GetWebhook(id); err != nil {
+		return nil, 0, err
+	}
+	deliveries, total, err := model.ListWebhookDeliveries(id, page, size)
+	if err != nil {
+		return nil, 0, dbError(err, "webhook not found")
+	}
+	return deliveries, total, nil
+}
+
+// RedeliverWebhook 重新发送某次投递的内容，返回新的投递记录
+func (s *UserService) RedeliverWebhook(id, deliveryID int64) (*model.WebhookDelivery, error) {
+	if _, err := s.GetWebhook(id); err != nil {
+		return nil, err
+	}
+	var previous model.WebhookDelivery
+	if err := model.GetWebhookDelivery(id, deliveryID, &previous); err != nil {
+		return nil, dbError(err, "delivery not found")
+	}
+	d, err := webhook.Redeliver(&previous)
+	if err != nil {
+		return nil, dbError(err, "delivery not found")
+	}
+	return d, nil
+}
+
+func validateWebhook(req WebhookRequest, create bool) error {
+	var c validator.Collector
+	if create {
+		c.Require("url", req.URL)
+	}
+	if req.URL != "" {
+		u, err := url.Parse(req.URL)
+		switch {
+		case len(req.URL) > webhookURLMaxLength:
+			c.Add("url", "must be at most 1024 characters")
+		case err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "":
+			c.Add("url", "must be an absolute http or https URL")
+		case u.User != nil:
+			c.Add("url", "must not contain credentials")
+		}
+	}
+	for _, t := range req.Events {
+		if t != "*" && !knownEventType(t) {
+			c.Add("events", "unknown event type "+t)
+		}
+	}
+	if len(strings.Join(req.Events, ",")) > 512 {
+		c.Add("events", "too many event types")
+	}
+	if len(req.Description) > webhookDescriptionMaxLength {
+		c.Add("description", "must be at most 256 characters")
+	}
+	return c.Err()
+}
+
+func knownEventType(t string) bool {
+	for _, known := range outbox.Types {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
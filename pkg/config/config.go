@@ -5,6 +5,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
 	"log"
+	"path/filepath"
+	"runtime"
+	"time"
 )
 
 type Config struct {
@@ -19,12 +22,154 @@ type Config struct {
 	} `mapstructure:"redis"`
 
 	Grpc struct {
-		Port int `mapstructure:"port"`
+		Port           int           `mapstructure:"port"`
+		Reflection     bool          `mapstructure:"reflection"`      // 是否注册反射服务
+		HealthInterval time.Duration `mapstructure:"health_interval"` // 健康状态刷新间隔
+		AdminPort      int           `mapstructure:"admin_port"`      // channelz 管理端口，0 表示关闭
 	} `mapstructure:"grpc"`
 
 	Http struct {
-		Port int `mapstructure:"port"`
+		Port           int  `mapstructure:"port"`
+		LegacyEnvelope bool `mapstructure:"legacy_envelope"` // 兼容旧版：失败也返回 HTTP 200
+		LegacyHandlers bool `mapstructure:"legacy_handlers"` // 是否保留手写的 /users 路由
 	} `mapstructure:"http"`
+
+	Session struct {
+		Backend        string `mapstructure:"backend"`          // memory | redis | hybrid | sql
+		MigrateOnStart bool   `mapstructure:"migrate_on_start"` // 启动时将旧编码的 Redis 会话升级为当前版本
+		MaxSessions    int    `mapstructure:"max_sessions"`     // 进程内最多保留的会话数，0 不限制
+		MaxMemoryMB    int64  `mapstructure:"max_memory_mb"`    // 进程内会话估算内存上限（MB），0 不限制
+		LazyCreate     bool   `mapstructure:"lazy_create"`      // 未登录的请求不创建会话
+
+		IdleTimeout               time.Duration `mapstructure:"idle_timeout"`                 // 空闲超时
+		AbsoluteTimeout           time.Duration `mapstructure:"absolute_timeout"`             // 自登录起的最长有效期，0 不限制
+		RememberMeIdleTimeout     time.Duration `mapstructure:"remember_me_idle_timeout"`     // "记住我"会话的空闲超时
+		RememberMeAbsoluteTimeout time.Duration `mapstructure:"remember_me_absolute_timeout"` // "记住我"会话的最长有效期
+		RefreshInterval           time.Duration `mapstructure:"refresh_interval"`             // 访问时间回写的最小间隔
+
+		Cookie struct {
+			Domain      string   `mapstructure:"domain"`
+			Secure      bool     `mapstructure:"secure"`       // 仅通过 HTTPS 发送
+			SameSite    string   `mapstructure:"same_site"`    // lax | strict | none
+			SigningKeys []string `mapstructure:"signing_keys"` // HMAC 签名密钥，第一个用于签名
+		} `mapstructure:"cookie"`
+	} `mapstructure:"session"`
+
+	Auth struct {
+		JWT struct {
+			Enabled    bool          `mapstructure:"enabled"`
+			Issuer     string        `mapstructure:"issuer"`
+			Audience   string        `mapstructure:"audience"`
+			AccessTTL  time.Duration `mapstructure:"access_ttl"`  // 访问令牌有效期
+			RefreshTTL time.Duration `mapstructure:"refresh_ttl"` // 刷新令牌有效期
+			Keys       []struct {
+				ID   string `mapstructure:"id"`   // JWT 头部的 kid
+				File string `mapstructure:"file"` // PEM 私钥（Ed25519 或 RSA）
+			} `mapstructure:"keys"` // 第一个用于签名，全部用于校验
+		} `mapstructure:"jwt"`
+		RequireGRPC bool `mapstructure:"require_grpc"` // gRPC 调用必须携带访问令牌
+
+		MFA struct {
+			Issuer          string        `mapstructure:"issuer"`            // 验证器应用中显示的服务名
+			ChallengeTTL    time.Duration `mapstructure:"challenge_ttl"`     // 完成第二步的时限
+			MaxAttempts     int           `mapstructure:"max_attempts"`      // 每次登录允许的验证码尝试次数
+			RequireForAdmin bool          `mapstructure:"require_for_admin"` // 管理员必须启用两步验证
+		} `mapstructure:"mfa"`
+
+		Lockout struct {
+			Window              time.Duration `mapstructure:"window"`                 // 失败次数统计窗口
+			MaxAccountFailures  int           `mapstructure:"max_account_failures"`   // 账号失败上限，达到后锁定
+			MaxIPFailures       int           `mapstructure:"max_ip_failures"`        // IP 失败上限，达到后限流
+			LockoutDuration     time.Duration `mapstructure:"lockout_duration"`       // 账号锁定时长
+			BaseDelay           time.Duration `mapstructure:"base_delay"`             // 首次失败的响应延迟
+			MaxDelay            time.Duration `mapstructure:"max_delay"`              // 响应延迟上限
+			MaxRecoveryPerEmail int           `mapstructure:"max_recovery_per_email"` // 同一邮箱的找回密码请求上限
+			MaxRecoveryPerIP    int           `mapstructure:"max_recovery_per_ip"`    // 同一 IP 的找回密码请求上限
+		} `mapstructure:"lockout"`
+
+		Password struct {
+			MinLength       int  `mapstructure:"min_length"`
+			MaxLength       int  `mapstructure:"max_length"`
+			MinClasses      int  `mapstructure:"min_classes"`      // 小写、大写、数字、符号中至少包含的类别数
+			DisallowAccount bool `mapstructure:"disallow_account"` // 不能包含账号
+			CheckCommon     bool `mapstructure:"check_common"`     // 不能是常见密码
+			HistorySize     int  `mapstructure:"history_size"`     // 不能与最近 N 次的密码相同
+			BcryptCost      int  `mapstructure:"bcrypt_cost"`
+		} `mapstructure:"password"`
+
+		Recovery struct {
+			ResetTTL  time.Duration `mapstructure:"reset_ttl"`  // 重置密码令牌有效期
+			VerifyTTL time.Duration `mapstructure:"verify_ttl"` // 验证邮箱令牌有效期
+			ResetURL  string        `mapstructure:"reset_url"`  // 邮件中的重置密码链接，{token} 替换为令牌
+			VerifyURL string        `mapstructure:"verify_url"` // 邮件中的验证邮箱链接，{token} 替换为令牌
+		} `mapstructure:"recovery"`
+	} `mapstructure:"auth"`
+
+	Mail struct {
+		Driver  string        `mapstructure:"driver"`  // smtp | file | memory
+		From    string        `mapstructure:"from"`    // 发件人
+		Dir     string        `mapstructure:"dir"`     // file 模式下邮件写入的目录
+		Timeout time.Duration `mapstructure:"timeout"` // 单封邮件的发送时限
+		SMTP    struct {
+			Host     string `mapstructure:"host"`
+			Port     int    `mapstructure:"port"`
+			Username string `mapstructure:"username"`
+			Password string `mapstructure:"password"`
+		} `mapstructure:"smtp"`
+	} `mapstructure:"mail"`
+
+	Audit struct {
+		Retention  time.Duration `mapstructure:"retention"`   // 保留时长，0 表示永久保留
+		ArchiveDir string        `mapstructure:"archive_dir"` // 归档目录，为空时过期事件直接删除
+		Interval   time.Duration `mapstructure:"interval"`    // 归档任务执行间隔
+		BatchSize  int           `mapstructure:"batch_size"`  // 每批归档的事件数
+	} `mapstructure:"audit"`
+
+	Events struct {
+		Publisher     string        `mapstructure:"publisher"`      // redis | inprocess | log
+		Stream        string        `mapstructure:"stream"`         // Redis Stream 名称
+		StreamMaxLen  int64         `mapstructure:"stream_max_len"` // Stream 近似最大长度，0 表示不裁剪
+		RelayInterval time.Duration `mapstructure:"relay_interval"` // 中继轮询间隔
+		BatchSize     int           `mapstructure:"batch_size"`     // 每批发布的事件数
+		Retention     time.Duration `mapstructure:"retention"`      // 已发布事件的保留时长
+		MaxAttempts   int           `mapstructure:"max_attempts"`   // 单个事件最多发布次数，达到后停止发布
+	} `mapstructure:"events"`
+
+	Webhooks struct {
+		Timeout     time.Duration `mapstructure:"timeout"`      // 单次投递的请求超时
+		MaxAttempts int           `mapstructure:"max_attempts"` // 最多发送次数（含首次）
+		BaseDelay   time.Duration `mapstructure:"base_delay"`   // 首次重试的等待时间，之后翻倍
+		MaxDelay    time.Duration `mapstructure:"max_delay"`    // 重试等待上限
+		Workers     int           `mapstructure:"workers"`      // 投递专用协程数
+		QueueSize   int           `mapstructure:"queue_size"`   // 投递队列长度，满时推迟发送
+	} `mapstructure:"webhooks"`
+
+	Watch struct {
+		Backend     string        `mapstructure:"backend"`      // memory | redis
+		Stream      string        `mapstructure:"stream"`       // redis 模式的 Stream 名称
+		Size        int           `mapstructure:"size"`         // 保留的变更条数，决定断线后可恢复的范围
+		Heartbeat   time.Duration `mapstructure:"heartbeat"`    // 心跳间隔
+		MaxWatchers int           `mapstructure:"max_watchers"` // 每个实例同时连接的观察者上限
+	} `mapstructure:"watch"`
+
+	Import struct {
+		BatchSize     int    `mapstructure:"batch_size"`     // 每个事务写入的行数
+		MaxRows       int    `mapstructure:"max_rows"`       // 单次导入的行数上限
+		MaxSizeMB     int64  `mapstructure:"max_size_mb"`    // 上传文件大小上限（MB）
+		SyncMaxKB     int64  `mapstructure:"sync_max_kb"`    // 不超过该大小（KB）的文件同步导入
+		MaxConcurrent int    `mapstructure:"max_concurrent"` // 每个实例同时执行的导入数
+		TempDir       string `mapstructure:"temp_dir"`       // 上传文件的暂存目录
+	} `mapstructure:"import"`
+
+	Export struct {
+		MaxConcurrent int `mapstructure:"max_concurrent"` // 每个实例同时执行的导出数
+		FlushRows     int `mapstructure:"flush_rows"`     // 每写出多少行发送给客户端
+	} `mapstructure:"export"`
+
+	Health struct {
+		PoolSaturation float64       `mapstructure:"pool_saturation"` // 协程池队列占用率阈值
+		DrainDelay     time.Duration `mapstructure:"drain_delay"`     // 停机前就绪探针失败的排空时长
+	} `mapstructure:"health"`
 }
 
 var AppConfig *Config
@@ -34,6 +179,10 @@ func InitConfig() error {
 	viper.SetConfigType("yaml")         // 配置类型
 	viper.AddConfigPath(".")            // 当前目录
 	viper.AddConfigPath("./pkg/config") // 支持 config 子目录
+	// 源码所在目录，go test 在各包目录下运行时使用
+	if _, file, _, ok := runtime.Caller(0); ok {
+		viper.AddConfigPath(filepath.Dir(file))
+	}
 
 	if err := viper.ReadInConfig(); err != nil {
 		return fmt.Errorf("读取配置文件失败: %w", err)
@@ -0,0 +1,170 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"http_grpc/pkg/validator"
+	"net/http"
+	"time"
+)
+
+// Kind 领域错误类别，与传输协议无关
+type Kind int
+
+const (
+	Internal Kind = iota
+	NotFound
+	AlreadyExists
+	Unauthenticated
+	PermissionDenied
+	InvalidArgument
+	Conflict
+	Unavailable
+	ResourceExhausted
+)
+
+// errorDomain gRPC ErrorInfo 中的错误域
+const errorDomain = "usercenter"
+
+var kindNames = map[Kind]string{
+	Internal:          "INTERNAL",
+	NotFound:          "NOT_FOUND",
+	AlreadyExists:     "ALREADY_EXISTS",
+	Unauthenticated:   "UNAUTHENTICATED",
+	PermissionDenied:  "PERMISSION_DENIED",
+	InvalidArgument:   "INVALID_ARGUMENT",
+	Conflict:          "CONFLICT",
+	Unavailable:       "UNAVAILABLE",
+	ResourceExhausted: "RESOURCE_EXHAUSTED",
+}
+
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return kindNames[Internal]
+}
+
+// Error 领域错误
+type Error struct {
+	Kind       Kind
+	Message    string
+	Violations []validator.Violation // 仅 InvalidArgument 使用
+	RetryAfter time.Duration         // 仅 ResourceExhausted 使用，客户端应等待的时长
+	Err        error                 // 原始错误，不对外暴露
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// GRPCStatus 使 status.FromError 能直接识别领域错误
+func (e *Error) GRPCStatus() *status.Status {
+	return GRPCStatus(e)
+}
+
+// New 创建领域错误
+func New(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Wrap 包装底层错误
+func Wrap(kind Kind, message string, err error) *Error {
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+// RetryLater 请求过于频繁，after 后可重试
+func RetryLater(message string, after time.Duration) *Error {
+	return &Error{Kind: ResourceExhausted, Message: message, RetryAfter: after}
+}
+
+// From 将任意错误转换为领域错误，参数校验错误转换为 InvalidArgument
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	var invalid *validator.Error
+	if errors.As(err, &invalid) {
+		return &Error{Kind: InvalidArgument, Message: "invalid argument", Violations: invalid.Violations}
+	}
+	return Wrap(Internal, "internal error", err)
+}
+
+// KindOf 获取错误类别
+func KindOf(err error) Kind {
+	return From(err).Kind
+}
+
+// Is 判断错误是否属于指定类别
+func Is(err error, kind Kind) bool {
+	return err != nil && KindOf(err) == kind
+}
+
+// 领域错误 -> HTTP 状态码
+var httpStatus = map[Kind]int{
+	Internal:          http.StatusInternalServerError,
+	NotFound:          http.StatusNotFound,
+	AlreadyExists:     http.StatusConflict,
+	Unauthenticated:   http.StatusUnauthorized,
+	PermissionDenied:  http.StatusForbidden,
+	InvalidArgument:   http.StatusBadRequest,
+	Conflict:          http.StatusConflict,
+	Unavailable:       http.StatusServiceUnavailable,
+	ResourceExhausted: http.StatusTooManyRequests,
+}
+
+// 领域错误 -> gRPC 状态码
+var grpcCodes = map[Kind]codes.Code{
+	Internal:          codes.Internal,
+	NotFound:          codes.NotFound,
+	AlreadyExists:     codes.AlreadyExists,
+	Unauthenticated:   codes.Unauthenticated,
+	PermissionDenied:  codes.PermissionDenied,
+	InvalidArgument:   codes.InvalidArgument,
+	Conflict:          codes.Aborted,
+	Unavailable:       codes.Unavailable,
+	ResourceExhausted: codes.ResourceExhausted,
+}
+
+// HTTPStatus 错误对应的 HTTP 状态码
+func HTTPStatus(err error) int {
+	return httpStatus[KindOf(err)]
+}
+
+// GRPCStatus 错误对应的 gRPC status，附带 ErrorInfo 与 BadRequest 详情
+func GRPCStatus(err error) *status.Status {
+	e := From(err)
+	st := status.New(grpcCodes[e.Kind], e.Message)
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: e.Kind.String(), Domain: errorDomain}}
+	if len(e.Violations) > 0 {
+		br := &errdetails.BadRequest{}
+		for _, v := range e.Violations {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Description: v.Description,
+			})
+		}
+		details = append(details, br)
+	}
+	if e.RetryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(e.RetryAfter)})
+	}
+	if withDetails, detailErr := st.WithDetails(details...); detailErr == nil {
+		return withDetails
+	}
+	return st
+}
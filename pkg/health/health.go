@@ -0,0 +1,135 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CheckFunc 依赖检查函数，返回 nil 表示健康
+type CheckFunc func(ctx context.Context) error
+
+// Result 单项检查结果
+type Result struct {
+	Status    string `json:"status"` // up | down
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latencyMs"`
+}
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Registry 依赖检查注册表，HTTP 探针与 gRPC 健康服务共用
+type Registry struct {
+	lock   sync.RWMutex
+	checks map[string]CheckFunc
+	order  []string
+}
+
+// DefaultRegistry 全局注册表
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry 创建注册表
+func NewRegistry() *Registry {
+	return &Registry{checks: make(map[string]CheckFunc)}
+}
+
+// Register 注册依赖检查，同名覆盖
+func (r *Registry) Register(name string, check CheckFunc) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.checks[name]; !ok {
+		r.order = append(r.order, name)
+	}
+	r.checks[name] = check
+}
+
+// Names 已注册的检查项，按注册顺序
+func (r *Registry) Names() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return append([]string(nil), r.order...)
+}
+
+// Check 并发执行指定检查项（为空时执行全部），返回每项结果与整体是否健康
+func (r *Registry) Check(ctx context.Context, names ...string) (map[string]Result, bool) {
+	if len(names) == 0 {
+		names = r.Names()
+	}
+
+	r.lock.RLock()
+	checks := make(map[string]CheckFunc, len(names))
+	for _, name := range names {
+		checks[name] = r.checks[name]
+	}
+	r.lock.RUnlock()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		healthy = true
+		results = make(map[string]Result, len(checks))
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check CheckFunc) {
+			defer wg.Done()
+			result := run(ctx, check)
+			mu.Lock()
+			results[name] = result
+			if result.Status != StatusUp {
+				healthy = false
+			}
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+	return results, healthy
+}
+
+func run(ctx context.Context, check CheckFunc) Result {
+	if check == nil {
+		return Result{Status: StatusDown, Error: "check not registered"}
+	}
+	start := time.Now()
+	err := check(ctx)
+	result := Result{Status: StatusUp, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Register 注册到全局注册表
+func Register(name string, check CheckFunc) {
+	DefaultRegistry.Register(name, check)
+}
+
+// 停机排空：开始后就绪探针与 gRPC 健康状态均返回不可用
+var (
+	draining  atomic.Bool
+	drainOnce sync.Once
+	drainCh   = make(chan struct{})
+)
+
+// StartDraining 进入排空状态，只能进入一次
+func StartDraining() {
+	drainOnce.Do(func() {
+		draining.Store(true)
+		close(drainCh)
+	})
+}
+
+// Draining 是否处于排空状态
+func Draining() bool {
+	return draining.Load()
+}
+
+// DrainStarted 进入排空状态时关闭的通道
+func DrainStarted() <-chan struct{} {
+	return drainCh
+}
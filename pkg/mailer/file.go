@@ -0,0 +1,31 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// FileMailer 把邮件写入目录中的 .eml 文件，可直接用邮件客户端打开
+type FileMailer struct {
+	dir  string
+	from string
+	seq  atomic.Uint64
+}
+
+// NewFile 创建文件发送方式，目录不存在时自动创建
+func NewFile(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	now := time.Now()
+	name := fmt.Sprintf("%s-%04d.eml", now.Format("20060102T150405.000000000"), m.seq.Add(1))
+	return os.WriteFile(filepath.Join(m.dir, name), encode(m.from, msg, now), 0o600)
+}
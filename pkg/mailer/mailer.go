@@ -0,0 +1,88 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message 一封纯文本邮件
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer 邮件发送方式
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// 可选的发送方式
+const (
+	DriverSMTP   = "smtp"   // 通过 SMTP 服务器发送
+	DriverFile   = "file"   // 写入目录中的 .eml 文件，用于开发环境
+	DriverMemory = "memory" // 保存在内存中，用于测试
+)
+
+var (
+	currentMu sync.RWMutex
+	current   Mailer = NewMemory()
+	timeout          = 30 * time.Second
+)
+
+// Use 设置全局使用的发送方式
+func Use(m Mailer) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	current = m
+}
+
+// Current 当前的发送方式
+func Current() Mailer {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current
+}
+
+// SetTimeout 设置单封邮件的发送时限，d <= 0 时保持默认的 30s
+func SetTimeout(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	timeout = d
+}
+
+// Send 使用当前的发送方式发送邮件，超过发送时限时放弃，邮件服务器无响应时不会一直占用调用方
+func Send(ctx context.Context, msg Message) error {
+	currentMu.RLock()
+	m, d := current, timeout
+	currentMu.RUnlock()
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	return m.Send(ctx, msg)
+}
+
+// headerValue 去掉换行，防止在邮件头中注入其他字段
+var headerValue = strings.NewReplacer("\r", "", "\n", "")
+
+// encode 生成 RFC 5322 邮件内容，主题按 RFC 2047 编码以支持中文
+func encode(from string, msg Message, now time.Time) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", headerValue.Replace(msg.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	// SMTP 要求使用 CRLF 换行
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return b.Bytes()
+}
@@ -0,0 +1,103 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPOptions SMTP 服务器配置
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	Username string // 为空时不认证
+	Password string
+	From     string // 发件人，如 "UserCenter <no-reply@example.com>"
+}
+
+// SMTPMailer 通过 SMTP 发送，服务器支持时使用 STARTTLS
+type SMTPMailer struct {
+	opts SMTPOptions
+}
+
+// NewSMTP 创建 SMTP 发送方式
+func NewSMTP(opts SMTPOptions) (*SMTPMailer, error) {
+	if opts.Host == "" {
+		return nil, errors.New("mailer: smtp host is required")
+	}
+	if _, err := mail.ParseAddress(opts.From); err != nil {
+		return nil, fmt.Errorf("mailer: invalid from address %q: %w", opts.From, err)
+	}
+	if opts.Port == 0 {
+		opts.Port = 587
+	}
+	return &SMTPMailer{opts: opts}, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if strings.ContainsAny(msg.To, "\r\n") {
+		return errors.New("mailer: invalid recipient")
+	}
+	from, _ := mail.ParseAddress(m.opts.From)
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("mailer: invalid recipient %q: %w", msg.To, err)
+	}
+
+	addr := net.JoinHostPort(m.opts.Host, strconv.Itoa(m.opts.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, m.opts.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	// net/smtp 只在 TLS 连接或本机地址上发送明文密码，未启用 STARTTLS 的远程服务器会认证失败
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(tlsConfig(m.opts.Host)); err != nil {
+			return err
+		}
+	}
+	if m.opts.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.opts.Username, m.opts.Password, m.opts.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(encode(m.opts.From, msg, time.Now())); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// tlsConfig STARTTLS 使用的 TLS 配置
+func tlsConfig(host string) *tls.Config {
+	return &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+}
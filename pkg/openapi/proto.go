@@ -0,0 +1,155 @@
+package openapi
+
+import (
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"regexp"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{([^}=]+)(=[^}]*)?}`)
+
+// wrapper 类型在 protojson 中直接编码为对应的标量
+var wrapperSchemas = map[protoreflect.FullName]*Schema{
+	"google.protobuf.StringValue": {Type: "string", Nullable: true},
+	"google.protobuf.BoolValue":   {Type: "boolean", Nullable: true},
+	"google.protobuf.Int32Value":  {Type: "integer", Format: "int32", Nullable: true},
+	"google.protobuf.UInt32Value": {Type: "integer", Format: "int32", Nullable: true},
+	"google.protobuf.Int64Value":  {Type: "string", Format: "int64", Nullable: true},
+	"google.protobuf.UInt64Value": {Type: "string", Format: "int64", Nullable: true},
+	"google.protobuf.DoubleValue": {Type: "number", Nullable: true},
+	"google.protobuf.FloatValue":  {Type: "number", Nullable: true},
+	"google.protobuf.Timestamp":   {Type: "string", Format: "date-time"},
+}
+
+// AddProtoFile 根据 google.api.http 注解将 proto 文件中的 RPC 登记为 HTTP 操作
+// errorSchema 为所有操作默认错误响应的 schema
+func (d *Document) AddProtoFile(fd protoreflect.FileDescriptor, errorSchema *Schema) {
+	services := fd.Services()
+	for i := 0; i < services.Len(); i++ {
+		svc := services.Get(i)
+		methods := svc.Methods()
+		for j := 0; j < methods.Len(); j++ {
+			m := methods.Get(j)
+			rule, ok := proto.GetExtension(m.Options(), annotations.E_Http).(*annotations.HttpRule)
+			if !ok || rule == nil {
+				continue
+			}
+			method, path := httpPattern(rule)
+			if method == "" {
+				continue
+			}
+			d.AddOperation(method, path, d.protoOperation(svc, m, rule, path, errorSchema))
+		}
+	}
+}
+
+func httpPattern(rule *annotations.HttpRule) (string, string) {
+	switch p := rule.Pattern.(type) {
+	case *annotations.HttpRule_Get:
+		return "GET", p.Get
+	case *annotations.HttpRule_Post:
+		return "POST", p.Post
+	case *annotations.HttpRule_Put:
+		return "PUT", p.Put
+	case *annotations.HttpRule_Patch:
+		return "PATCH", p.Patch
+	case *annotations.HttpRule_Delete:
+		return "DELETE", p.Delete
+	}
+	return "", ""
+}
+
+func (d *Document) protoOperation(svc protoreflect.ServiceDescriptor, m protoreflect.MethodDescriptor, rule *annotations.HttpRule, path string, errorSchema *Schema) *Operation {
+	op := &Operation{
+		Tags:        []string{string(svc.Name())},
+		OperationID: string(svc.Name()) + "_" + string(m.Name()),
+		Summary:     string(m.Name()),
+		Responses: map[string]*Response{
+			"200":     {Description: "OK", Content: JSONContent(d.messageRef(m.Output()))},
+			"default": {Description: "Error", Content: map[string]*MediaType{"application/problem+json": {Schema: errorSchema}}},
+		},
+	}
+
+	input := m.Input()
+	pathParams := make(map[string]bool)
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		pathParams[match[1]] = true
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   d.fieldSchema(input.Fields().ByName(protoreflect.Name(match[1]))),
+		})
+	}
+
+	if rule.Body == "*" {
+		op.RequestBody = &RequestBody{Required: true, Content: JSONContent(d.messageRef(input))}
+		return op
+	}
+	// 无请求体时，其余标量字段作为 query 参数
+	fields := input.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		if pathParams[string(f.Name())] || f.Kind() == protoreflect.MessageKind {
+			continue
+		}
+		op.Parameters = append(op.Parameters, Parameter{Name: string(f.Name()), In: "query", Schema: d.fieldSchema(f)})
+	}
+	return op
+}
+
+// messageRef 登记消息 schema 并返回引用
+func (d *Document) messageRef(md protoreflect.MessageDescriptor) *Schema {
+	if s, ok := wrapperSchemas[md.FullName()]; ok {
+		return s
+	}
+	name := string(md.FullName())
+	if _, ok := d.Components.Schemas[name]; ok {
+		return Ref(name)
+	}
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	d.Components.Schemas[name] = s // 先占位，避免递归消息死循环
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		s.Properties[string(f.Name())] = d.fieldSchema(f)
+	}
+	return Ref(name)
+}
+
+func (d *Document) fieldSchema(f protoreflect.FieldDescriptor) *Schema {
+	if f == nil {
+		return &Schema{Type: "string"}
+	}
+	if f.IsMap() {
+		return &Schema{Type: "object", AdditionalProperties: d.singularSchema(f.MapValue())}
+	}
+	if f.IsList() {
+		return &Schema{Type: "array", Items: d.singularSchema(f)}
+	}
+	return d.singularSchema(f)
+}
+
+func (d *Document) singularSchema(f protoreflect.FieldDescriptor) *Schema {
+	switch f.Kind() {
+	case protoreflect.BoolKind:
+		return &Schema{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &Schema{Type: "integer", Format: "int32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// protojson 将 64 位整数编码为字符串
+		return &Schema{Type: "string", Format: "int64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return &Schema{Type: "number"}
+	case protoreflect.StringKind, protoreflect.EnumKind:
+		return &Schema{Type: "string"}
+	case protoreflect.BytesKind:
+		return &Schema{Type: "string", Format: "byte"}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return d.messageRef(f.Message())
+	}
+	return &Schema{}
+}
@@ -1,6 +1,7 @@
 package pool
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -9,6 +10,7 @@ import (
 var (
 	SessionPool       *RoutinePool
 	HandlerWorkerPool *RoutinePool
+	MailPool          *RoutinePool // 发送邮件，与请求处理隔离，邮件服务器变慢时不占用 HandlerWorkerPool
 )
 
 // Task 需要处理的任务
@@ -70,6 +72,16 @@ func (p *RoutinePool) AddTask(task Task) {
 	p.TaskQueue <- task
 }
 
+// TryAddTask 队列未满时添加任务并返回 true，否则立即返回 false
+func (p *RoutinePool) TryAddTask(task Task) bool {
+	select {
+	case p.TaskQueue <- task:
+		return true
+	default:
+		return false
+	}
+}
+
 // Shutdown 优雅关闭协程池
 func (p *RoutinePool) Shutdown() {
 	p.closeOnce.Do(func() {
@@ -97,4 +109,28 @@ func init() {
 
 	SessionPool = NewPool(5, 10)
 	SessionPool.Run()
+
+	MailPool = NewPool(2, 1000)
+	MailPool.Run()
+}
+
+// Saturation 任务队列占用率，0~1
+func (p *RoutinePool) Saturation() float64 {
+	if cap(p.TaskQueue) == 0 {
+		return 0
+	}
+	return float64(len(p.TaskQueue)) / float64(cap(p.TaskQueue))
+}
+
+// SaturationCheck 队列占用率达到阈值时返回错误，用于就绪探针
+func (p *RoutinePool) SaturationCheck(threshold float64) func(ctx context.Context) error {
+	if threshold <= 0 || threshold > 1 {
+		threshold = 1
+	}
+	return func(ctx context.Context) error {
+		if s := p.Saturation(); s >= threshold {
+			return fmt.Errorf("task queue saturated: %.0f%%", s*100)
+		}
+		return nil
+	}
 }
@@ -2,7 +2,12 @@ package utils
 
 import (
 	"github.com/gin-gonic/gin"
+	"http_grpc/pkg/errs"
+	"http_grpc/pkg/validator"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // Response 通用响应结构
@@ -12,15 +17,32 @@ type Response struct {
 	Data interface{} `json:"data,omitempty"`
 }
 
+// Problem RFC 7807 problem+json 错误响应
+type Problem struct {
+	Type       string                `json:"type"`
+	Title      string                `json:"title"`
+	Status     int                   `json:"status"`
+	Detail     string                `json:"detail,omitempty"`
+	Instance   string                `json:"instance,omitempty"`
+	Code       string                `json:"code,omitempty"`
+	Violations []validator.Violation `json:"violations,omitempty"`
+}
+
 const (
 	SuccessCode      = 0
 	BadRequestCode   = 400
 	UnauthorizedCode = 401
+	ForbiddenCode    = 403
 	NotFoundCode     = 404
 	ServerErrorCode  = 500
 	DuplicateCode    = 409
 )
 
+const problemContentType = "application/problem+json"
+
+// LegacyEnvelope 兼容模式：失败时仍返回 HTTP 200，错误码放在响应体中
+var LegacyEnvelope bool
+
 // Success 成功返回
 func Success(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusOK, Response{
@@ -30,11 +52,61 @@ func Success(c *gin.Context, data interface{}) {
 	})
 }
 
-// Fail 失败返回
+// Fail 失败返回，code 为对应的 HTTP 状态码
 func Fail(c *gin.Context, code int, msg string) {
-	c.JSON(http.StatusOK, Response{
-		Code: code,
-		Msg:  msg,
-		Data: nil,
+	if LegacyEnvelope {
+		c.JSON(http.StatusOK, Response{
+			Code: code,
+			Msg:  msg,
+			Data: nil,
+		})
+		return
+	}
+	writeProblem(c, Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(code),
+		Status: code,
+		Detail: msg,
+	})
+}
+
+// FailErr 根据领域错误返回对应的 HTTP 状态码与 problem+json
+func FailErr(c *gin.Context, err error) {
+	e := errs.From(err)
+	code := errs.HTTPStatus(e)
+	if e.RetryAfter > 0 {
+		c.Header("Retry-After", RetryAfterSeconds(e.RetryAfter))
+	}
+	if LegacyEnvelope {
+		var data interface{}
+		if len(e.Violations) > 0 {
+			data = gin.H{"violations": e.Violations}
+		}
+		c.JSON(http.StatusOK, Response{
+			Code: code,
+			Msg:  e.Message,
+			Data: data,
+		})
+		return
+	}
+	writeProblem(c, Problem{
+		Type:       "about:blank",
+		Title:      http.StatusText(code),
+		Status:     code,
+		Detail:     e.Message,
+		Code:       e.Kind.String(),
+		Violations: e.Violations,
 	})
 }
+
+// RetryAfterSeconds Retry-After 头的秒数，不足一秒按一秒
+func RetryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+func writeProblem(c *gin.Context, p Problem) {
+	p.Instance = c.Request.URL.Path
+	// gin 的 JSON 渲染不会覆盖已设置的 Content-Type
+	c.Header("Content-Type", problemContentType)
+	c.JSON(p.Status, p)
+}
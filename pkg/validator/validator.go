@@ -0,0 +1,148 @@
+package validator
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Violation 单个字段的校验失败信息
+type Violation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// Error 参数校验错误，汇总所有字段的违规项
+type Error struct {
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, v.Field+": "+v.Description)
+	}
+	return "invalid argument: " + strings.Join(parts, "; ")
+}
+
+// Check 单条校验规则，返回空字符串表示通过
+type Check func(value string) string
+
+// Rule 字段规则：字段名 + 校验链
+type Rule struct {
+	Field  string
+	Checks []Check
+}
+
+// Collector 收集多个字段的违规项
+type Collector struct {
+	violations []Violation
+}
+
+// Require 必填校验
+func (c *Collector) Require(field, value string) bool {
+	if value == "" {
+		c.Add(field, "is required")
+		return false
+	}
+	return true
+}
+
+// Apply 对非空值执行规则中的全部校验
+func (c *Collector) Apply(rule Rule, value string) {
+	if value == "" {
+		return
+	}
+	for _, check := range rule.Checks {
+		if desc := check(value); desc != "" {
+			c.Add(rule.Field, desc)
+			return
+		}
+	}
+}
+
+// Add 追加一条违规项
+func (c *Collector) Add(field, description string) {
+	c.violations = append(c.violations, Violation{Field: field, Description: description})
+}
+
+// Err 没有违规项时返回 nil
+func (c *Collector) Err() error {
+	if len(c.violations) == 0 {
+		return nil
+	}
+	return &Error{Violations: c.violations}
+}
+
+// Length 字符长度区间
+func Length(min, max int) Check {
+	return func(value string) string {
+		n := utf8.RuneCountInString(value)
+		if n < min || n > max {
+			return fmt.Sprintf("length must be between %d and %d", min, max)
+		}
+		return ""
+	}
+}
+
+// Pattern 正则匹配
+func Pattern(re *regexp.Regexp, description string) Check {
+	return func(value string) string {
+		if !re.MatchString(value) {
+			return description
+		}
+		return ""
+	}
+}
+
+// Email 邮箱格式
+func Email() Check {
+	return func(value string) string {
+		addr, err := mail.ParseAddress(value)
+		if err != nil || addr.Address != value {
+			return "must be a valid email address"
+		}
+		return ""
+	}
+}
+
+// HTTPURL http/https 链接
+func HTTPURL() Check {
+	return func(value string) string {
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "must be a valid http or https URL"
+		}
+		return ""
+	}
+}
+
+// Strength 密码强度：至少包含字母和数字
+func Strength() Check {
+	return func(value string) string {
+		var hasLetter, hasDigit bool
+		for _, r := range value {
+			switch {
+			case unicode.IsLetter(r):
+				hasLetter = true
+			case unicode.IsDigit(r):
+				hasDigit = true
+			}
+		}
+		if !hasLetter || !hasDigit {
+			return "must contain both letters and digits"
+		}
+		return ""
+	}
+}
+
+// Range 整数区间校验
+func (c *Collector) Range(field string, value, min, max int) {
+	if value < min || value > max {
+		c.Add(field, fmt.Sprintf("must be between %d and %d", min, max))
+	}
+}
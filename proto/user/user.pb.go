@@ -7,6 +7,7 @@
 package user
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
@@ -27,7 +28,13 @@ type User struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	UserAccount   string                 `protobuf:"bytes,2,opt,name=userAccount,proto3" json:"userAccount,omitempty"`
-	UserPassword  string                 `protobuf:"bytes,3,opt,name=userPassword,proto3" json:"userPassword,omitempty"`
+	UserPassword  string                 `protobuf:"bytes,3,opt,name=userPassword,proto3" json:"userPassword,omitempty"` // 只用于创建，查询时不返回
+	Username      string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	AvatarUrl     string                 `protobuf:"bytes,5,opt,name=avatarUrl,proto3" json:"avatarUrl,omitempty"`
+	Gender        int32                  `protobuf:"varint,6,opt,name=gender,proto3" json:"gender,omitempty"`
+	Phone         string                 `protobuf:"bytes,7,opt,name=phone,proto3" json:"phone,omitempty"`
+	Email         string                 `protobuf:"bytes,8,opt,name=email,proto3" json:"email,omitempty"`
+	EmailVerified bool                   `protobuf:"varint,9,opt,name=emailVerified,proto3" json:"emailVerified,omitempty"` // 只读
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -83,6 +90,48 @@ func (x *User) GetUserPassword() string {
 	return ""
 }
 
+func (x *User) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *User) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *User) GetGender() int32 {
+	if x != nil {
+		return x.Gender
+	}
+	return 0
+}
+
+func (x *User) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetEmailVerified() bool {
+	if x != nil {
+		return x.EmailVerified
+	}
+	return false
+}
+
 // 通用响应
 type CommonResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -182,12 +231,18 @@ func (x *LoginRequest) GetUserPassword() string {
 }
 
 type LoginResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        int64                  `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
-	UserAccount   string                 `protobuf:"bytes,2,opt,name=userAccount,proto3" json:"userAccount,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	UserId      int64                  `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	UserAccount string                 `protobuf:"bytes,2,opt,name=userAccount,proto3" json:"userAccount,omitempty"`
+	Message     string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Tokens      *TokenPair             `protobuf:"bytes,4,opt,name=tokens,proto3" json:"tokens,omitempty"` // 开启令牌认证时返回
+	// 已启用两步验证时只返回 userId 与 challengeToken，调用 VerifyMfa 完成登录
+	MfaRequired    bool   `protobuf:"varint,5,opt,name=mfaRequired,proto3" json:"mfaRequired,omitempty"`
+	ChallengeToken string `protobuf:"bytes,6,opt,name=challengeToken,proto3" json:"challengeToken,omitempty"`
+	// 管理员未启用两步验证，本次登录只授予普通用户权限
+	MfaEnrollmentRequired bool `protobuf:"varint,7,opt,name=mfaEnrollmentRequired,proto3" json:"mfaEnrollmentRequired,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
 func (x *LoginResponse) Reset() {
@@ -241,28 +296,57 @@ func (x *LoginResponse) GetMessage() string {
 	return ""
 }
 
-// ID 请求
-type IdRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *LoginResponse) GetTokens() *TokenPair {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
 }
 
-func (x *IdRequest) Reset() {
-	*x = IdRequest{}
+func (x *LoginResponse) GetMfaRequired() bool {
+	if x != nil {
+		return x.MfaRequired
+	}
+	return false
+}
+
+func (x *LoginResponse) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+func (x *LoginResponse) GetMfaEnrollmentRequired() bool {
+	if x != nil {
+		return x.MfaEnrollmentRequired
+	}
+	return false
+}
+
+// 两步验证登录的第二步，code 为 TOTP 验证码或恢复码
+type VerifyMfaRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChallengeToken string                 `protobuf:"bytes,1,opt,name=challengeToken,proto3" json:"challengeToken,omitempty"`
+	Code           string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *VerifyMfaRequest) Reset() {
+	*x = VerifyMfaRequest{}
 	mi := &file_proto_user_user_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *IdRequest) String() string {
+func (x *VerifyMfaRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IdRequest) ProtoMessage() {}
+func (*VerifyMfaRequest) ProtoMessage() {}
 
-func (x *IdRequest) ProtoReflect() protoreflect.Message {
+func (x *VerifyMfaRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_user_user_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -274,40 +358,47 @@ func (x *IdRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IdRequest.ProtoReflect.Descriptor instead.
-func (*IdRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use VerifyMfaRequest.ProtoReflect.Descriptor instead.
+func (*VerifyMfaRequest) Descriptor() ([]byte, []int) {
 	return file_proto_user_user_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *IdRequest) GetId() int64 {
+func (x *VerifyMfaRequest) GetChallengeToken() string {
 	if x != nil {
-		return x.Id
+		return x.ChallengeToken
 	}
-	return 0
+	return ""
 }
 
-// Account 请求
-type AccountRequest struct {
+func (x *VerifyMfaRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+// 重置密码与验证邮箱
+type ForgotPasswordRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserAccount   string                 `protobuf:"bytes,1,opt,name=userAccount,proto3" json:"userAccount,omitempty"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AccountRequest) Reset() {
-	*x = AccountRequest{}
+func (x *ForgotPasswordRequest) Reset() {
+	*x = ForgotPasswordRequest{}
 	mi := &file_proto_user_user_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AccountRequest) String() string {
+func (x *ForgotPasswordRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AccountRequest) ProtoMessage() {}
+func (*ForgotPasswordRequest) ProtoMessage() {}
 
-func (x *AccountRequest) ProtoReflect() protoreflect.Message {
+func (x *ForgotPasswordRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_user_user_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -319,41 +410,40 @@ func (x *AccountRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AccountRequest.ProtoReflect.Descriptor instead.
-func (*AccountRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ForgotPasswordRequest.ProtoReflect.Descriptor instead.
+func (*ForgotPasswordRequest) Descriptor() ([]byte, []int) {
 	return file_proto_user_user_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *AccountRequest) GetUserAccount() string {
+func (x *ForgotPasswordRequest) GetEmail() string {
 	if x != nil {
-		return x.UserAccount
+		return x.Email
 	}
 	return ""
 }
 
-// 更新密码请求
-type UpdatePasswordRequest struct {
+type ResetPasswordRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
 	NewPassword   string                 `protobuf:"bytes,2,opt,name=newPassword,proto3" json:"newPassword,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdatePasswordRequest) Reset() {
-	*x = UpdatePasswordRequest{}
+func (x *ResetPasswordRequest) Reset() {
+	*x = ResetPasswordRequest{}
 	mi := &file_proto_user_user_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdatePasswordRequest) String() string {
+func (x *ResetPasswordRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdatePasswordRequest) ProtoMessage() {}
+func (*ResetPasswordRequest) ProtoMessage() {}
 
-func (x *UpdatePasswordRequest) ProtoReflect() protoreflect.Message {
+func (x *ResetPasswordRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_user_user_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -365,48 +455,46 @@ func (x *UpdatePasswordRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdatePasswordRequest.ProtoReflect.Descriptor instead.
-func (*UpdatePasswordRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ResetPasswordRequest.ProtoReflect.Descriptor instead.
+func (*ResetPasswordRequest) Descriptor() ([]byte, []int) {
 	return file_proto_user_user_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *UpdatePasswordRequest) GetId() int64 {
+func (x *ResetPasswordRequest) GetToken() string {
 	if x != nil {
-		return x.Id
+		return x.Token
 	}
-	return 0
+	return ""
 }
 
-func (x *UpdatePasswordRequest) GetNewPassword() string {
+func (x *ResetPasswordRequest) GetNewPassword() string {
 	if x != nil {
 		return x.NewPassword
 	}
 	return ""
 }
 
-// 分页请求与用户列表响应
-type ListUsersRequest struct {
+type VerifyEmailRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
-	Size          int32                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUsersRequest) Reset() {
-	*x = ListUsersRequest{}
+func (x *VerifyEmailRequest) Reset() {
+	*x = VerifyEmailRequest{}
 	mi := &file_proto_user_user_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUsersRequest) String() string {
+func (x *VerifyEmailRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUsersRequest) ProtoMessage() {}
+func (*VerifyEmailRequest) ProtoMessage() {}
 
-func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+func (x *VerifyEmailRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_user_user_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -418,48 +506,43 @@ func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
-func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use VerifyEmailRequest.ProtoReflect.Descriptor instead.
+func (*VerifyEmailRequest) Descriptor() ([]byte, []int) {
 	return file_proto_user_user_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *ListUsersRequest) GetPage() int32 {
-	if x != nil {
-		return x.Page
-	}
-	return 0
-}
-
-func (x *ListUsersRequest) GetSize() int32 {
+func (x *VerifyEmailRequest) GetToken() string {
 	if x != nil {
-		return x.Size
+		return x.Token
 	}
-	return 0
+	return ""
 }
 
-type ListUsersResponse struct {
+// 访问令牌（JWT）与刷新令牌
+type TokenPair struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
-	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
-	Size          int32                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	AccessToken   string                 `protobuf:"bytes,1,opt,name=accessToken,proto3" json:"accessToken,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refreshToken,proto3" json:"refreshToken,omitempty"`
+	ExpiresIn     int64                  `protobuf:"varint,3,opt,name=expiresIn,proto3" json:"expiresIn,omitempty"` // 访问令牌剩余有效秒数
+	TokenType     string                 `protobuf:"bytes,4,opt,name=tokenType,proto3" json:"tokenType,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUsersResponse) Reset() {
-	*x = ListUsersResponse{}
+func (x *TokenPair) Reset() {
+	*x = TokenPair{}
 	mi := &file_proto_user_user_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUsersResponse) String() string {
+func (x *TokenPair) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUsersResponse) ProtoMessage() {}
+func (*TokenPair) ProtoMessage() {}
 
-func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+func (x *TokenPair) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_user_user_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -471,59 +554,60 @@ func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
-func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use TokenPair.ProtoReflect.Descriptor instead.
+func (*TokenPair) Descriptor() ([]byte, []int) {
 	return file_proto_user_user_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *ListUsersResponse) GetUsers() []*User {
+func (x *TokenPair) GetAccessToken() string {
 	if x != nil {
-		return x.Users
+		return x.AccessToken
 	}
-	return nil
+	return ""
 }
 
-func (x *ListUsersResponse) GetPage() int32 {
+func (x *TokenPair) GetRefreshToken() string {
 	if x != nil {
-		return x.Page
+		return x.RefreshToken
 	}
-	return 0
+	return ""
 }
 
-func (x *ListUsersResponse) GetSize() int32 {
+func (x *TokenPair) GetExpiresIn() int64 {
 	if x != nil {
-		return x.Size
+		return x.ExpiresIn
 	}
 	return 0
 }
 
-// 更新用户请求
-type UpdateUserRequest struct {
-	state         protoimpl.MessageState  `protogen:"open.v1"`
-	Id            int64                   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Username      *wrapperspb.StringValue `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
-	AvatarUrl     *wrapperspb.StringValue `protobuf:"bytes,5,opt,name=avatarUrl,proto3" json:"avatarUrl,omitempty"`
-	Gender        *wrapperspb.Int32Value  `protobuf:"bytes,6,opt,name=gender,proto3" json:"gender,omitempty"`
-	Phone         *wrapperspb.StringValue `protobuf:"bytes,7,opt,name=phone,proto3" json:"phone,omitempty"`
-	Email         *wrapperspb.StringValue `protobuf:"bytes,8,opt,name=email,proto3" json:"email,omitempty"`
+func (x *TokenPair) GetTokenType() string {
+	if x != nil {
+		return x.TokenType
+	}
+	return ""
+}
+
+type RefreshTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refreshToken,proto3" json:"refreshToken,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateUserRequest) Reset() {
-	*x = UpdateUserRequest{}
+func (x *RefreshTokenRequest) Reset() {
+	*x = RefreshTokenRequest{}
 	mi := &file_proto_user_user_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateUserRequest) String() string {
+func (x *RefreshTokenRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateUserRequest) ProtoMessage() {}
+func (*RefreshTokenRequest) ProtoMessage() {}
 
-func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
+func (x *RefreshTokenRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_user_user_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -535,77 +619,2791 @@ func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
-func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RefreshTokenRequest.ProtoReflect.Descriptor instead.
+func (*RefreshTokenRequest) Descriptor() ([]byte, []int) {
 	return file_proto_user_user_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *UpdateUserRequest) GetId() int64 {
+func (x *RefreshTokenRequest) GetRefreshToken() string {
 	if x != nil {
-		return x.Id
+		return x.RefreshToken
 	}
-	return 0
+	return ""
 }
 
-func (x *UpdateUserRequest) GetUsername() *wrapperspb.StringValue {
-	if x != nil {
-		return x.Username
-	}
-	return nil
+// ID 请求
+type IdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateUserRequest) GetAvatarUrl() *wrapperspb.StringValue {
-	if x != nil {
-		return x.AvatarUrl
-	}
-	return nil
+func (x *IdRequest) Reset() {
+	*x = IdRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateUserRequest) GetGender() *wrapperspb.Int32Value {
+func (x *IdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IdRequest) ProtoMessage() {}
+
+func (x *IdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[10]
 	if x != nil {
-		return x.Gender
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *UpdateUserRequest) GetPhone() *wrapperspb.StringValue {
+// Deprecated: Use IdRequest.ProtoReflect.Descriptor instead.
+func (*IdRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *IdRequest) GetId() int64 {
 	if x != nil {
-		return x.Phone
+		return x.Id
 	}
-	return nil
+	return 0
 }
 
-func (x *UpdateUserRequest) GetEmail() *wrapperspb.StringValue {
+// Account 请求
+type AccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserAccount   string                 `protobuf:"bytes,1,opt,name=userAccount,proto3" json:"userAccount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountRequest) Reset() {
+	*x = AccountRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountRequest) ProtoMessage() {}
+
+func (x *AccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[11]
 	if x != nil {
-		return x.Email
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-var File_proto_user_user_proto protoreflect.FileDescriptor
+// Deprecated: Use AccountRequest.ProtoReflect.Descriptor instead.
+func (*AccountRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{11}
+}
 
-const file_proto_user_user_proto_rawDesc = "" +
+func (x *AccountRequest) GetUserAccount() string {
+	if x != nil {
+		return x.UserAccount
+	}
+	return ""
+}
+
+// 本人修改密码请求
+type UpdatePasswordRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	NewPassword     string                 `protobuf:"bytes,2,opt,name=newPassword,proto3" json:"newPassword,omitempty"`
+	CurrentPassword string                 `protobuf:"bytes,3,opt,name=currentPassword,proto3" json:"currentPassword,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdatePasswordRequest) Reset() {
+	*x = UpdatePasswordRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePasswordRequest) ProtoMessage() {}
+
+func (x *UpdatePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePasswordRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdatePasswordRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdatePasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+func (x *UpdatePasswordRequest) GetCurrentPassword() string {
+	if x != nil {
+		return x.CurrentPassword
+	}
+	return ""
+}
+
+// 管理员重置密码请求
+type ForceResetPasswordRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	NewPassword   string                 `protobuf:"bytes,2,opt,name=newPassword,proto3" json:"newPassword,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceResetPasswordRequest) Reset() {
+	*x = ForceResetPasswordRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceResetPasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceResetPasswordRequest) ProtoMessage() {}
+
+func (x *ForceResetPasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceResetPasswordRequest.ProtoReflect.Descriptor instead.
+func (*ForceResetPasswordRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ForceResetPasswordRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ForceResetPasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+// 分页请求与用户列表响应
+type ListUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	Size          int32                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListUsersRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type ListUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Size          int32                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUsersResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListUsersResponse) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+// 更新用户请求
+type UpdateUserRequest struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Id            int64                   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username      *wrapperspb.StringValue `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	AvatarUrl     *wrapperspb.StringValue `protobuf:"bytes,5,opt,name=avatarUrl,proto3" json:"avatarUrl,omitempty"`
+	Gender        *wrapperspb.Int32Value  `protobuf:"bytes,6,opt,name=gender,proto3" json:"gender,omitempty"`
+	Phone         *wrapperspb.StringValue `protobuf:"bytes,7,opt,name=phone,proto3" json:"phone,omitempty"`
+	Email         *wrapperspb.StringValue `protobuf:"bytes,8,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserRequest) Reset() {
+	*x = UpdateUserRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserRequest) ProtoMessage() {}
+
+func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UpdateUserRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateUserRequest) GetUsername() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Username
+	}
+	return nil
+}
+
+func (x *UpdateUserRequest) GetAvatarUrl() *wrapperspb.StringValue {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return nil
+}
+
+func (x *UpdateUserRequest) GetGender() *wrapperspb.Int32Value {
+	if x != nil {
+		return x.Gender
+	}
+	return nil
+}
+
+func (x *UpdateUserRequest) GetPhone() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Phone
+	}
+	return nil
+}
+
+func (x *UpdateUserRequest) GetEmail() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Email
+	}
+	return nil
+}
+
+// 会话信息，id 为会话 ID 的摘要，不能用作 Cookie
+type Session struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserAgent     string                 `protobuf:"bytes,2,opt,name=userAgent,proto3" json:"userAgent,omitempty"`
+	Ip            string                 `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,4,opt,name=createdAt,proto3" json:"createdAt,omitempty"` // Unix 秒
+	LastSeen      int64                  `protobuf:"varint,5,opt,name=lastSeen,proto3" json:"lastSeen,omitempty"`   // Unix 秒
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_proto_user_user_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Session) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Session) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *Session) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Session) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Session) GetLastSeen() int64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return 0
+}
+
+type ListSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListSessionsResponse) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+// 撤销单个会话请求
+type RevokeSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=sessionId,proto3" json:"sessionId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionRequest) Reset() {
+	*x = RevokeSessionRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionRequest) ProtoMessage() {}
+
+func (x *RevokeSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionRequest.ProtoReflect.Descriptor instead.
+func (*RevokeSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RevokeSessionRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RevokeSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type RevokeSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revoked       int32                  `protobuf:"varint,1,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionsResponse) Reset() {
+	*x = RevokeSessionsResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionsResponse) ProtoMessage() {}
+
+func (x *RevokeSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionsResponse.ProtoReflect.Descriptor instead.
+func (*RevokeSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RevokeSessionsResponse) GetRevoked() int32 {
+	if x != nil {
+		return x.Revoked
+	}
+	return 0
+}
+
+// API Key，key 只在创建时返回
+type ApiKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Prefix        string                 `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Scopes        []string               `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,5,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`   // Unix 秒
+	LastUsedAt    int64                  `protobuf:"varint,6,opt,name=lastUsedAt,proto3" json:"lastUsedAt,omitempty"` // Unix 秒，从未使用为 0
+	CreatedAt     int64                  `protobuf:"varint,7,opt,name=createdAt,proto3" json:"createdAt,omitempty"`   // Unix 秒
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApiKey) Reset() {
+	*x = ApiKey{}
+	mi := &file_proto_user_user_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApiKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApiKey) ProtoMessage() {}
+
+func (x *ApiKey) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApiKey.ProtoReflect.Descriptor instead.
+func (*ApiKey) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ApiKey) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ApiKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ApiKey) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ApiKey) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *ApiKey) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *ApiKey) GetLastUsedAt() int64 {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return 0
+}
+
+func (x *ApiKey) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type CreateApiKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes        []string               `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	ExpiresInDays int32                  `protobuf:"varint,4,opt,name=expiresInDays,proto3" json:"expiresInDays,omitempty"` // 0 使用默认有效期
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateApiKeyRequest) Reset() {
+	*x = CreateApiKeyRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateApiKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateApiKeyRequest) ProtoMessage() {}
+
+func (x *CreateApiKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateApiKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateApiKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CreateApiKeyRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CreateApiKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateApiKeyRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *CreateApiKeyRequest) GetExpiresInDays() int32 {
+	if x != nil {
+		return x.ExpiresInDays
+	}
+	return 0
+}
+
+type CreateApiKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	ApiKey        *ApiKey                `protobuf:"bytes,2,opt,name=apiKey,proto3" json:"apiKey,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateApiKeyResponse) Reset() {
+	*x = CreateApiKeyResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateApiKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateApiKeyResponse) ProtoMessage() {}
+
+func (x *CreateApiKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateApiKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateApiKeyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CreateApiKeyResponse) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *CreateApiKeyResponse) GetApiKey() *ApiKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+type ListApiKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeys       []*ApiKey              `protobuf:"bytes,1,rep,name=apiKeys,proto3" json:"apiKeys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListApiKeysResponse) Reset() {
+	*x = ListApiKeysResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListApiKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListApiKeysResponse) ProtoMessage() {}
+
+func (x *ListApiKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListApiKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListApiKeysResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListApiKeysResponse) GetApiKeys() []*ApiKey {
+	if x != nil {
+		return x.ApiKeys
+	}
+	return nil
+}
+
+type RevokeApiKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	KeyId         int64                  `protobuf:"varint,2,opt,name=keyId,proto3" json:"keyId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeApiKeyRequest) Reset() {
+	*x = RevokeApiKeyRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeApiKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeApiKeyRequest) ProtoMessage() {}
+
+func (x *RevokeApiKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeApiKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeApiKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RevokeApiKeyRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RevokeApiKeyRequest) GetKeyId() int64 {
+	if x != nil {
+		return x.KeyId
+	}
+	return 0
+}
+
+// 审计事件，changes 为变更前后字段的 JSON，敏感字段已脱敏
+type AuditEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreateTime    int64                  `protobuf:"varint,2,opt,name=createTime,proto3" json:"createTime,omitempty"` // Unix 毫秒
+	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	ActorId       int64                  `protobuf:"varint,4,opt,name=actorId,proto3" json:"actorId,omitempty"`
+	ActorAccount  string                 `protobuf:"bytes,5,opt,name=actorAccount,proto3" json:"actorAccount,omitempty"`
+	TargetId      int64                  `protobuf:"varint,6,opt,name=targetId,proto3" json:"targetId,omitempty"`
+	TargetAccount string                 `protobuf:"bytes,7,opt,name=targetAccount,proto3" json:"targetAccount,omitempty"`
+	Transport     string                 `protobuf:"bytes,8,opt,name=transport,proto3" json:"transport,omitempty"` // http | grpc | system
+	Ip            string                 `protobuf:"bytes,9,opt,name=ip,proto3" json:"ip,omitempty"`
+	UserAgent     string                 `protobuf:"bytes,10,opt,name=userAgent,proto3" json:"userAgent,omitempty"`
+	RequestId     string                 `protobuf:"bytes,11,opt,name=requestId,proto3" json:"requestId,omitempty"`
+	Changes       string                 `protobuf:"bytes,12,opt,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	mi := &file_proto_user_user_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent) ProtoMessage() {}
+
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *AuditEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetCreateTime() int64 {
+	if x != nil {
+		return x.CreateTime
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetActorId() int64 {
+	if x != nil {
+		return x.ActorId
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetActorAccount() string {
+	if x != nil {
+		return x.ActorAccount
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetTargetAccount() string {
+	if x != nil {
+		return x.TargetAccount
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetChanges() string {
+	if x != nil {
+		return x.Changes
+	}
+	return ""
+}
+
+// 审计事件查询条件，零值表示不限制
+type ListAuditEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	Size          int32                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	ActorId       int64                  `protobuf:"varint,3,opt,name=actorId,proto3" json:"actorId,omitempty"`
+	TargetId      int64                  `protobuf:"varint,4,opt,name=targetId,proto3" json:"targetId,omitempty"`
+	Action        string                 `protobuf:"bytes,5,opt,name=action,proto3" json:"action,omitempty"` // 以 . 结尾时按前缀匹配，如 "user."
+	Transport     string                 `protobuf:"bytes,6,opt,name=transport,proto3" json:"transport,omitempty"`
+	RequestId     string                 `protobuf:"bytes,7,opt,name=requestId,proto3" json:"requestId,omitempty"`
+	From          int64                  `protobuf:"varint,8,opt,name=from,proto3" json:"from,omitempty"` // Unix 秒，包含
+	To            int64                  `protobuf:"varint,9,opt,name=to,proto3" json:"to,omitempty"`     // Unix 秒，不包含
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsRequest) Reset() {
+	*x = ListAuditEventsRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsRequest) ProtoMessage() {}
+
+func (x *ListAuditEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListAuditEventsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetActorId() int64 {
+	if x != nil {
+		return x.ActorId
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetTargetId() int64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+type ListAuditEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*AuditEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Size          int32                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Total         int64                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsResponse) Reset() {
+	*x = ListAuditEventsResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsResponse) ProtoMessage() {}
+
+func (x *ListAuditEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListAuditEventsResponse) GetEvents() []*AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListAuditEventsResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListAuditEventsResponse) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ListAuditEventsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// Webhook 接收领域事件的 HTTP 端点，签名密钥只在创建或轮换时返回
+type Webhook struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Events        []string               `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"` // 为空表示订阅全部事件
+	Enabled       bool                   `protobuf:"varint,4,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	CreateTime    int64                  `protobuf:"varint,6,opt,name=createTime,proto3" json:"createTime,omitempty"` // Unix 毫秒
+	UpdateTime    int64                  `protobuf:"varint,7,opt,name=updateTime,proto3" json:"updateTime,omitempty"` // Unix 毫秒
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_proto_user_user_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Webhook) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *Webhook) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *Webhook) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Webhook) GetCreateTime() int64 {
+	if x != nil {
+		return x.CreateTime
+	}
+	return 0
+}
+
+func (x *Webhook) GetUpdateTime() int64 {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return 0
+}
+
+// 创建或更新 Webhook，更新时未提供的字段保持不变；
+// 更新时 events 为空表示不修改，用 ["*"] 订阅全部事件
+type WebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"` // 仅更新时使用
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Events        []string               `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
+	Enabled       *wrapperspb.BoolValue  `protobuf:"bytes,4,opt,name=enabled,proto3" json:"enabled,omitempty"` // 创建时默认启用
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	RotateSecret  bool                   `protobuf:"varint,6,opt,name=rotateSecret,proto3" json:"rotateSecret,omitempty"` // 更新时生成新的签名密钥
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookRequest) Reset() {
+	*x = WebhookRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookRequest) ProtoMessage() {}
+
+func (x *WebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookRequest.ProtoReflect.Descriptor instead.
+func (*WebhookRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *WebhookRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *WebhookRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *WebhookRequest) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *WebhookRequest) GetEnabled() *wrapperspb.BoolValue {
+	if x != nil {
+		return x.Enabled
+	}
+	return nil
+}
+
+func (x *WebhookRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *WebhookRequest) GetRotateSecret() bool {
+	if x != nil {
+		return x.RotateSecret
+	}
+	return false
+}
+
+type WebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhook       *Webhook               `protobuf:"bytes,1,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"` // 仅创建或轮换时返回
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookResponse) Reset() {
+	*x = WebhookResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookResponse) ProtoMessage() {}
+
+func (x *WebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookResponse.ProtoReflect.Descriptor instead.
+func (*WebhookResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *WebhookResponse) GetWebhook() *Webhook {
+	if x != nil {
+		return x.Webhook
+	}
+	return nil
+}
+
+func (x *WebhookResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type ListWebhooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksRequest) Reset() {
+	*x = ListWebhooksRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksRequest) ProtoMessage() {}
+
+func (x *ListWebhooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhooksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{32}
+}
+
+type ListWebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*Webhook             `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksResponse) Reset() {
+	*x = ListWebhooksResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksResponse) ProtoMessage() {}
+
+func (x *ListWebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListWebhooksResponse) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+// Webhook 投递记录，每次发送一条，同一次投递的重试共用 deliveryId
+type WebhookDelivery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	WebhookId     int64                  `protobuf:"varint,2,opt,name=webhookId,proto3" json:"webhookId,omitempty"`
+	DeliveryId    string                 `protobuf:"bytes,3,opt,name=deliveryId,proto3" json:"deliveryId,omitempty"`
+	EventId       string                 `protobuf:"bytes,4,opt,name=eventId,proto3" json:"eventId,omitempty"`
+	EventType     string                 `protobuf:"bytes,5,opt,name=eventType,proto3" json:"eventType,omitempty"`
+	Attempt       int32                  `protobuf:"varint,6,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	Redelivery    bool                   `protobuf:"varint,7,opt,name=redelivery,proto3" json:"redelivery,omitempty"`
+	Status        string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"` // pending | succeeded | failed
+	ResponseCode  int32                  `protobuf:"varint,9,opt,name=responseCode,proto3" json:"responseCode,omitempty"`
+	ResponseBody  string                 `protobuf:"bytes,10,opt,name=responseBody,proto3" json:"responseBody,omitempty"`
+	Error         string                 `protobuf:"bytes,11,opt,name=error,proto3" json:"error,omitempty"`
+	DurationMs    int64                  `protobuf:"varint,12,opt,name=durationMs,proto3" json:"durationMs,omitempty"`
+	CreateTime    int64                  `protobuf:"varint,13,opt,name=createTime,proto3" json:"createTime,omitempty"` // Unix 毫秒
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_proto_user_user_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDelivery) ProtoMessage() {}
+
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *WebhookDelivery) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetWebhookId() int64 {
+	if x != nil {
+		return x.WebhookId
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetRedelivery() bool {
+	if x != nil {
+		return x.Redelivery
+	}
+	return false
+}
+
+func (x *WebhookDelivery) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetResponseCode() int32 {
+	if x != nil {
+		return x.ResponseCode
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetResponseBody() string {
+	if x != nil {
+		return x.ResponseBody
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetCreateTime() int64 {
+	if x != nil {
+		return x.CreateTime
+	}
+	return 0
+}
+
+type ListWebhookDeliveriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Size          int32                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesRequest) Reset() {
+	*x = ListWebhookDeliveriesRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesRequest) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ListWebhookDeliveriesRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ListWebhookDeliveriesRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListWebhookDeliveriesRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type ListWebhookDeliveriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*WebhookDelivery     `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Size          int32                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Total         int64                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesResponse) Reset() {
+	*x = ListWebhookDeliveriesResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesResponse) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ListWebhookDeliveriesResponse) GetDeliveries() []*WebhookDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+func (x *ListWebhookDeliveriesResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListWebhookDeliveriesResponse) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ListWebhookDeliveriesResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type RedeliverWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	DeliveryId    int64                  `protobuf:"varint,2,opt,name=deliveryId,proto3" json:"deliveryId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RedeliverWebhookRequest) Reset() {
+	*x = RedeliverWebhookRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RedeliverWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedeliverWebhookRequest) ProtoMessage() {}
+
+func (x *RedeliverWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedeliverWebhookRequest.ProtoReflect.Descriptor instead.
+func (*RedeliverWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *RedeliverWebhookRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RedeliverWebhookRequest) GetDeliveryId() int64 {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return 0
+}
+
+// 订阅用户变更的条件，为空表示不限制
+type WatchUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Types         []string               `protobuf:"bytes,1,rep,name=types,proto3" json:"types,omitempty"` // user.created | user.updated | user.deleted
+	UserIds       []int64                `protobuf:"varint,2,rep,packed,name=userIds,proto3" json:"userIds,omitempty"`
+	LastEventId   string                 `protobuf:"bytes,3,opt,name=lastEventId,proto3" json:"lastEventId,omitempty"` // 断线重连时传入最后收到的消息 id
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchUsersRequest) Reset() {
+	*x = WatchUsersRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchUsersRequest) ProtoMessage() {}
+
+func (x *WatchUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchUsersRequest.ProtoReflect.Descriptor instead.
+func (*WatchUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *WatchUsersRequest) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *WatchUsersRequest) GetUserIds() []int64 {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *WatchUsersRequest) GetLastEventId() string {
+	if x != nil {
+		return x.LastEventId
+	}
+	return ""
+}
+
+// 推送的消息：kind 为 change 时携带事件；heartbeat 定期发送；
+// reset 表示 lastEventId 已不可恢复，应重新拉取用户列表
+type UserChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"` // change | heartbeat | reset
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`     // 当前位置，重连时作为 lastEventId
+	EventId       string                 `protobuf:"bytes,3,opt,name=eventId,proto3" json:"eventId,omitempty"`
+	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	UserId        int64                  `protobuf:"varint,5,opt,name=userId,proto3" json:"userId,omitempty"`
+	OccurredAt    int64                  `protobuf:"varint,6,opt,name=occurredAt,proto3" json:"occurredAt,omitempty"` // Unix 毫秒
+	RequestId     string                 `protobuf:"bytes,7,opt,name=requestId,proto3" json:"requestId,omitempty"`
+	Data          string                 `protobuf:"bytes,8,opt,name=data,proto3" json:"data,omitempty"` // 事件内容（JSON）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserChange) Reset() {
+	*x = UserChange{}
+	mi := &file_proto_user_user_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserChange) ProtoMessage() {}
+
+func (x *UserChange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserChange.ProtoReflect.Descriptor instead.
+func (*UserChange) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *UserChange) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *UserChange) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UserChange) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *UserChange) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *UserChange) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *UserChange) GetOccurredAt() int64 {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return 0
+}
+
+func (x *UserChange) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *UserChange) GetData() string {
+	if x != nil {
+		return x.Data
+	}
+	return ""
+}
+
+// 批量导入的一行，dryRun 与 onDuplicate 以第一条消息为准
+type BulkCreateUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`               // 只使用账号、密码与资料字段
+	DryRun        bool                   `protobuf:"varint,2,opt,name=dryRun,proto3" json:"dryRun,omitempty"`          // 只校验并给出每行的预期结果，不写入
+	OnDuplicate   string                 `protobuf:"bytes,3,opt,name=onDuplicate,proto3" json:"onDuplicate,omitempty"` // 账号已存在时：skip | update | fail（默认）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkCreateUsersRequest) Reset() {
+	*x = BulkCreateUsersRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkCreateUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkCreateUsersRequest) ProtoMessage() {}
+
+func (x *BulkCreateUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkCreateUsersRequest.ProtoReflect.Descriptor instead.
+func (*BulkCreateUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *BulkCreateUsersRequest) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *BulkCreateUsersRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *BulkCreateUsersRequest) GetOnDuplicate() string {
+	if x != nil {
+		return x.OnDuplicate
+	}
+	return ""
+}
+
+type FieldViolation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldViolation) Reset() {
+	*x = FieldViolation{}
+	mi := &file_proto_user_user_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldViolation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldViolation) ProtoMessage() {}
+
+func (x *FieldViolation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldViolation.ProtoReflect.Descriptor instead.
+func (*FieldViolation) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *FieldViolation) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldViolation) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+// 一行的导入结果
+type ImportRowResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`   // 第几条消息或记录，从 1 开始
+	Line          int32                  `protobuf:"varint,2,opt,name=line,proto3" json:"line,omitempty"` // 文件中的行号，流式导入时为 0
+	UserAccount   string                 `protobuf:"bytes,3,opt,name=userAccount,proto3" json:"userAccount,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"` // created | updated | skipped | failed
+	UserId        int64                  `protobuf:"varint,5,opt,name=userId,proto3" json:"userId,omitempty"`
+	Reason        string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	Violations    []*FieldViolation      `protobuf:"bytes,7,rep,name=violations,proto3" json:"violations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportRowResult) Reset() {
+	*x = ImportRowResult{}
+	mi := &file_proto_user_user_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRowResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRowResult) ProtoMessage() {}
+
+func (x *ImportRowResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRowResult.ProtoReflect.Descriptor instead.
+func (*ImportRowResult) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ImportRowResult) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *ImportRowResult) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *ImportRowResult) GetUserAccount() string {
+	if x != nil {
+		return x.UserAccount
+	}
+	return ""
+}
+
+func (x *ImportRowResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ImportRowResult) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ImportRowResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ImportRowResult) GetViolations() []*FieldViolation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+type BulkCreateUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DryRun        bool                   `protobuf:"varint,1,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Created       int32                  `protobuf:"varint,3,opt,name=created,proto3" json:"created,omitempty"`
+	Updated       int32                  `protobuf:"varint,4,opt,name=updated,proto3" json:"updated,omitempty"`
+	Skipped       int32                  `protobuf:"varint,5,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	Failed        int32                  `protobuf:"varint,6,opt,name=failed,proto3" json:"failed,omitempty"`
+	Rows          []*ImportRowResult     `protobuf:"bytes,7,rep,name=rows,proto3" json:"rows,omitempty"`
+	Error         string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"` // 提前停止的原因（如超过行数上限），此前的行已处理
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkCreateUsersResponse) Reset() {
+	*x = BulkCreateUsersResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkCreateUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkCreateUsersResponse) ProtoMessage() {}
+
+func (x *BulkCreateUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkCreateUsersResponse.ProtoReflect.Descriptor instead.
+func (*BulkCreateUsersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *BulkCreateUsersResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *BulkCreateUsersResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *BulkCreateUsersResponse) GetCreated() int32 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *BulkCreateUsersResponse) GetUpdated() int32 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+func (x *BulkCreateUsersResponse) GetSkipped() int32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *BulkCreateUsersResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *BulkCreateUsersResponse) GetRows() []*ImportRowResult {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+func (x *BulkCreateUsersResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ImportJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportJobRequest) Reset() {
+	*x = ImportJobRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportJobRequest) ProtoMessage() {}
+
+func (x *ImportJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportJobRequest.ProtoReflect.Descriptor instead.
+func (*ImportJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ImportJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// 后台导入任务，结束后附带逐行结果
+type ImportJob struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // running | succeeded | failed
+	Format        string                 `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
+	DryRun        bool                   `protobuf:"varint,4,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+	OnDuplicate   string                 `protobuf:"bytes,5,opt,name=onDuplicate,proto3" json:"onDuplicate,omitempty"`
+	Total         int32                  `protobuf:"varint,6,opt,name=total,proto3" json:"total,omitempty"`
+	Created       int32                  `protobuf:"varint,7,opt,name=created,proto3" json:"created,omitempty"`
+	Updated       int32                  `protobuf:"varint,8,opt,name=updated,proto3" json:"updated,omitempty"`
+	Skipped       int32                  `protobuf:"varint,9,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	Failed        int32                  `protobuf:"varint,10,opt,name=failed,proto3" json:"failed,omitempty"`
+	Error         string                 `protobuf:"bytes,11,opt,name=error,proto3" json:"error,omitempty"`
+	CreateTime    int64                  `protobuf:"varint,12,opt,name=createTime,proto3" json:"createTime,omitempty"` // Unix 毫秒
+	FinishTime    int64                  `protobuf:"varint,13,opt,name=finishTime,proto3" json:"finishTime,omitempty"` // Unix 毫秒，未结束时为 0
+	Rows          []*ImportRowResult     `protobuf:"bytes,14,rep,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportJob) Reset() {
+	*x = ImportJob{}
+	mi := &file_proto_user_user_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportJob) ProtoMessage() {}
+
+func (x *ImportJob) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportJob.ProtoReflect.Descriptor instead.
+func (*ImportJob) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ImportJob) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ImportJob) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ImportJob) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ImportJob) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ImportJob) GetOnDuplicate() string {
+	if x != nil {
+		return x.OnDuplicate
+	}
+	return ""
+}
+
+func (x *ImportJob) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ImportJob) GetCreated() int32 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *ImportJob) GetUpdated() int32 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+func (x *ImportJob) GetSkipped() int32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *ImportJob) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *ImportJob) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ImportJob) GetCreateTime() int64 {
+	if x != nil {
+		return x.CreateTime
+	}
+	return 0
+}
+
+func (x *ImportJob) GetFinishTime() int64 {
+	if x != nil {
+		return x.FinishTime
+	}
+	return 0
+}
+
+func (x *ImportJob) GetRows() []*ImportRowResult {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+type ExportUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Columns       []string               `protobuf:"bytes,1,rep,name=columns,proto3" json:"columns,omitempty"` // 为空时导出全部列
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUsersRequest) Reset() {
+	*x = ExportUsersRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUsersRequest) ProtoMessage() {}
+
+func (x *ExportUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUsersRequest.ProtoReflect.Descriptor instead.
+func (*ExportUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ExportUsersRequest) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+// 导出的一个用户，未选择的列为零值
+type ExportedUser struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserAccount   string                 `protobuf:"bytes,2,opt,name=userAccount,proto3" json:"userAccount,omitempty"`
+	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	AvatarUrl     string                 `protobuf:"bytes,4,opt,name=avatarUrl,proto3" json:"avatarUrl,omitempty"`
+	Gender        int32                  `protobuf:"varint,5,opt,name=gender,proto3" json:"gender,omitempty"`
+	Phone         string                 `protobuf:"bytes,6,opt,name=phone,proto3" json:"phone,omitempty"`
+	Email         string                 `protobuf:"bytes,7,opt,name=email,proto3" json:"email,omitempty"`
+	EmailVerified bool                   `protobuf:"varint,8,opt,name=emailVerified,proto3" json:"emailVerified,omitempty"`
+	UserStatus    int32                  `protobuf:"varint,9,opt,name=userStatus,proto3" json:"userStatus,omitempty"`
+	UserRole      int32                  `protobuf:"varint,10,opt,name=userRole,proto3" json:"userRole,omitempty"`
+	PlanetCode    string                 `protobuf:"bytes,11,opt,name=planetCode,proto3" json:"planetCode,omitempty"`
+	CreateTime    int64                  `protobuf:"varint,12,opt,name=createTime,proto3" json:"createTime,omitempty"` // Unix 毫秒
+	UpdateTime    int64                  `protobuf:"varint,13,opt,name=updateTime,proto3" json:"updateTime,omitempty"` // Unix 毫秒
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportedUser) Reset() {
+	*x = ExportedUser{}
+	mi := &file_proto_user_user_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportedUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportedUser) ProtoMessage() {}
+
+func (x *ExportedUser) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportedUser.ProtoReflect.Descriptor instead.
+func (*ExportedUser) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ExportedUser) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ExportedUser) GetUserAccount() string {
+	if x != nil {
+		return x.UserAccount
+	}
+	return ""
+}
+
+func (x *ExportedUser) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ExportedUser) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *ExportedUser) GetGender() int32 {
+	if x != nil {
+		return x.Gender
+	}
+	return 0
+}
+
+func (x *ExportedUser) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *ExportedUser) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *ExportedUser) GetEmailVerified() bool {
+	if x != nil {
+		return x.EmailVerified
+	}
+	return false
+}
+
+func (x *ExportedUser) GetUserStatus() int32 {
+	if x != nil {
+		return x.UserStatus
+	}
+	return 0
+}
+
+func (x *ExportedUser) GetUserRole() int32 {
+	if x != nil {
+		return x.UserRole
+	}
+	return 0
+}
+
+func (x *ExportedUser) GetPlanetCode() string {
+	if x != nil {
+		return x.PlanetCode
+	}
+	return ""
+}
+
+func (x *ExportedUser) GetCreateTime() int64 {
+	if x != nil {
+		return x.CreateTime
+	}
+	return 0
+}
+
+func (x *ExportedUser) GetUpdateTime() int64 {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return 0
+}
+
+var File_proto_user_user_proto protoreflect.FileDescriptor
+
+const file_proto_user_user_proto_rawDesc = "" +
 	"\n" +
-	"\x15proto/user/user.proto\x12\x04user\x1a\x1egoogle/protobuf/wrappers.proto\"\\\n" +
+	"\x15proto/user/user.proto\x12\x04user\x1a\x1cgoogle/api/annotations.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\x80\x02\n" +
 	"\x04User\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12 \n" +
 	"\vuserAccount\x18\x02 \x01(\tR\vuserAccount\x12\"\n" +
-	"\fuserPassword\x18\x03 \x01(\tR\fuserPassword\"*\n" +
+	"\fuserPassword\x18\x03 \x01(\tR\fuserPassword\x12\x1a\n" +
+	"\busername\x18\x04 \x01(\tR\busername\x12\x1c\n" +
+	"\tavatarUrl\x18\x05 \x01(\tR\tavatarUrl\x12\x16\n" +
+	"\x06gender\x18\x06 \x01(\x05R\x06gender\x12\x14\n" +
+	"\x05phone\x18\a \x01(\tR\x05phone\x12\x14\n" +
+	"\x05email\x18\b \x01(\tR\x05email\x12$\n" +
+	"\remailVerified\x18\t \x01(\bR\remailVerified\"*\n" +
 	"\x0eCommonResponse\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessage\"T\n" +
 	"\fLoginRequest\x12 \n" +
 	"\vuserAccount\x18\x01 \x01(\tR\vuserAccount\x12\"\n" +
-	"\fuserPassword\x18\x02 \x01(\tR\fuserPassword\"c\n" +
+	"\fuserPassword\x18\x02 \x01(\tR\fuserPassword\"\x8c\x02\n" +
 	"\rLoginResponse\x12\x16\n" +
 	"\x06userId\x18\x01 \x01(\x03R\x06userId\x12 \n" +
 	"\vuserAccount\x18\x02 \x01(\tR\vuserAccount\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\"\x1b\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12'\n" +
+	"\x06tokens\x18\x04 \x01(\v2\x0f.user.TokenPairR\x06tokens\x12 \n" +
+	"\vmfaRequired\x18\x05 \x01(\bR\vmfaRequired\x12&\n" +
+	"\x0echallengeToken\x18\x06 \x01(\tR\x0echallengeToken\x124\n" +
+	"\x15mfaEnrollmentRequired\x18\a \x01(\bR\x15mfaEnrollmentRequired\"N\n" +
+	"\x10VerifyMfaRequest\x12&\n" +
+	"\x0echallengeToken\x18\x01 \x01(\tR\x0echallengeToken\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"-\n" +
+	"\x15ForgotPasswordRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"N\n" +
+	"\x14ResetPasswordRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12 \n" +
+	"\vnewPassword\x18\x02 \x01(\tR\vnewPassword\"*\n" +
+	"\x12VerifyEmailRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"\x8d\x01\n" +
+	"\tTokenPair\x12 \n" +
+	"\vaccessToken\x18\x01 \x01(\tR\vaccessToken\x12\"\n" +
+	"\frefreshToken\x18\x02 \x01(\tR\frefreshToken\x12\x1c\n" +
+	"\texpiresIn\x18\x03 \x01(\x03R\texpiresIn\x12\x1c\n" +
+	"\ttokenType\x18\x04 \x01(\tR\ttokenType\"9\n" +
+	"\x13RefreshTokenRequest\x12\"\n" +
+	"\frefreshToken\x18\x01 \x01(\tR\frefreshToken\"\x1b\n" +
 	"\tIdRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\"2\n" +
 	"\x0eAccountRequest\x12 \n" +
-	"\vuserAccount\x18\x01 \x01(\tR\vuserAccount\"I\n" +
+	"\vuserAccount\x18\x01 \x01(\tR\vuserAccount\"s\n" +
 	"\x15UpdatePasswordRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12 \n" +
+	"\vnewPassword\x18\x02 \x01(\tR\vnewPassword\x12(\n" +
+	"\x0fcurrentPassword\x18\x03 \x01(\tR\x0fcurrentPassword\"M\n" +
+	"\x19ForceResetPasswordRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12 \n" +
 	"\vnewPassword\x18\x02 \x01(\tR\vnewPassword\":\n" +
 	"\x10ListUsersRequest\x12\x12\n" +
 	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x12\n" +
@@ -621,22 +3419,274 @@ const file_proto_user_user_proto_rawDesc = "" +
 	"\tavatarUrl\x18\x05 \x01(\v2\x1c.google.protobuf.StringValueR\tavatarUrl\x123\n" +
 	"\x06gender\x18\x06 \x01(\v2\x1b.google.protobuf.Int32ValueR\x06gender\x122\n" +
 	"\x05phone\x18\a \x01(\v2\x1c.google.protobuf.StringValueR\x05phone\x122\n" +
-	"\x05email\x18\b \x01(\v2\x1c.google.protobuf.StringValueR\x05email2\xc6\x03\n" +
-	"\vUserService\x12.\n" +
+	"\x05email\x18\b \x01(\v2\x1c.google.protobuf.StringValueR\x05email\"\x81\x01\n" +
+	"\aSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\tuserAgent\x18\x02 \x01(\tR\tuserAgent\x12\x0e\n" +
+	"\x02ip\x18\x03 \x01(\tR\x02ip\x12\x1c\n" +
+	"\tcreatedAt\x18\x04 \x01(\x03R\tcreatedAt\x12\x1a\n" +
+	"\blastSeen\x18\x05 \x01(\x03R\blastSeen\"A\n" +
+	"\x14ListSessionsResponse\x12)\n" +
+	"\bsessions\x18\x01 \x03(\v2\r.user.SessionR\bsessions\"L\n" +
+	"\x14RevokeSessionRequest\x12\x16\n" +
+	"\x06userId\x18\x01 \x01(\x03R\x06userId\x12\x1c\n" +
+	"\tsessionId\x18\x02 \x01(\tR\tsessionId\"2\n" +
+	"\x16RevokeSessionsResponse\x12\x18\n" +
+	"\arevoked\x18\x01 \x01(\x05R\arevoked\"\xb8\x01\n" +
+	"\x06ApiKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06prefix\x18\x03 \x01(\tR\x06prefix\x12\x16\n" +
+	"\x06scopes\x18\x04 \x03(\tR\x06scopes\x12\x1c\n" +
+	"\texpiresAt\x18\x05 \x01(\x03R\texpiresAt\x12\x1e\n" +
+	"\n" +
+	"lastUsedAt\x18\x06 \x01(\x03R\n" +
+	"lastUsedAt\x12\x1c\n" +
+	"\tcreatedAt\x18\a \x01(\x03R\tcreatedAt\"\x7f\n" +
+	"\x13CreateApiKeyRequest\x12\x16\n" +
+	"\x06userId\x18\x01 \x01(\x03R\x06userId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x12$\n" +
+	"\rexpiresInDays\x18\x04 \x01(\x05R\rexpiresInDays\"N\n" +
+	"\x14CreateApiKeyResponse\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12$\n" +
+	"\x06apiKey\x18\x02 \x01(\v2\f.user.ApiKeyR\x06apiKey\"=\n" +
+	"\x13ListApiKeysResponse\x12&\n" +
+	"\aapiKeys\x18\x01 \x03(\v2\f.user.ApiKeyR\aapiKeys\"C\n" +
+	"\x13RevokeApiKeyRequest\x12\x16\n" +
+	"\x06userId\x18\x01 \x01(\x03R\x06userId\x12\x14\n" +
+	"\x05keyId\x18\x02 \x01(\x03R\x05keyId\"\xd8\x02\n" +
+	"\n" +
+	"AuditEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1e\n" +
+	"\n" +
+	"createTime\x18\x02 \x01(\x03R\n" +
+	"createTime\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x18\n" +
+	"\aactorId\x18\x04 \x01(\x03R\aactorId\x12\"\n" +
+	"\factorAccount\x18\x05 \x01(\tR\factorAccount\x12\x1a\n" +
+	"\btargetId\x18\x06 \x01(\x03R\btargetId\x12$\n" +
+	"\rtargetAccount\x18\a \x01(\tR\rtargetAccount\x12\x1c\n" +
+	"\ttransport\x18\b \x01(\tR\ttransport\x12\x0e\n" +
+	"\x02ip\x18\t \x01(\tR\x02ip\x12\x1c\n" +
+	"\tuserAgent\x18\n" +
+	" \x01(\tR\tuserAgent\x12\x1c\n" +
+	"\trequestId\x18\v \x01(\tR\trequestId\x12\x18\n" +
+	"\achanges\x18\f \x01(\tR\achanges\"\xee\x01\n" +
+	"\x16ListAuditEventsRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x05R\x04size\x12\x18\n" +
+	"\aactorId\x18\x03 \x01(\x03R\aactorId\x12\x1a\n" +
+	"\btargetId\x18\x04 \x01(\x03R\btargetId\x12\x16\n" +
+	"\x06action\x18\x05 \x01(\tR\x06action\x12\x1c\n" +
+	"\ttransport\x18\x06 \x01(\tR\ttransport\x12\x1c\n" +
+	"\trequestId\x18\a \x01(\tR\trequestId\x12\x12\n" +
+	"\x04from\x18\b \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\t \x01(\x03R\x02to\"\x81\x01\n" +
+	"\x17ListAuditEventsResponse\x12(\n" +
+	"\x06events\x18\x01 \x03(\v2\x10.user.AuditEventR\x06events\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x05R\x04size\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x03R\x05total\"\xbf\x01\n" +
+	"\aWebhook\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x16\n" +
+	"\x06events\x18\x03 \x03(\tR\x06events\x12\x18\n" +
+	"\aenabled\x18\x04 \x01(\bR\aenabled\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x1e\n" +
+	"\n" +
+	"createTime\x18\x06 \x01(\x03R\n" +
+	"createTime\x12\x1e\n" +
+	"\n" +
+	"updateTime\x18\a \x01(\x03R\n" +
+	"updateTime\"\xc6\x01\n" +
+	"\x0eWebhookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x16\n" +
+	"\x06events\x18\x03 \x03(\tR\x06events\x124\n" +
+	"\aenabled\x18\x04 \x01(\v2\x1a.google.protobuf.BoolValueR\aenabled\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\"\n" +
+	"\frotateSecret\x18\x06 \x01(\bR\frotateSecret\"R\n" +
+	"\x0fWebhookResponse\x12'\n" +
+	"\awebhook\x18\x01 \x01(\v2\r.user.WebhookR\awebhook\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\"\x15\n" +
+	"\x13ListWebhooksRequest\"A\n" +
+	"\x14ListWebhooksResponse\x12)\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\r.user.WebhookR\bwebhooks\"\x87\x03\n" +
+	"\x0fWebhookDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1c\n" +
+	"\twebhookId\x18\x02 \x01(\x03R\twebhookId\x12\x1e\n" +
+	"\n" +
+	"deliveryId\x18\x03 \x01(\tR\n" +
+	"deliveryId\x12\x18\n" +
+	"\aeventId\x18\x04 \x01(\tR\aeventId\x12\x1c\n" +
+	"\teventType\x18\x05 \x01(\tR\teventType\x12\x18\n" +
+	"\aattempt\x18\x06 \x01(\x05R\aattempt\x12\x1e\n" +
+	"\n" +
+	"redelivery\x18\a \x01(\bR\n" +
+	"redelivery\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12\"\n" +
+	"\fresponseCode\x18\t \x01(\x05R\fresponseCode\x12\"\n" +
+	"\fresponseBody\x18\n" +
+	" \x01(\tR\fresponseBody\x12\x14\n" +
+	"\x05error\x18\v \x01(\tR\x05error\x12\x1e\n" +
+	"\n" +
+	"durationMs\x18\f \x01(\x03R\n" +
+	"durationMs\x12\x1e\n" +
+	"\n" +
+	"createTime\x18\r \x01(\x03R\n" +
+	"createTime\"V\n" +
+	"\x1cListWebhookDeliveriesRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x05R\x04size\"\x94\x01\n" +
+	"\x1dListWebhookDeliveriesResponse\x125\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x15.user.WebhookDeliveryR\n" +
+	"deliveries\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x05R\x04size\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x03R\x05total\"I\n" +
+	"\x17RedeliverWebhookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1e\n" +
+	"\n" +
+	"deliveryId\x18\x02 \x01(\x03R\n" +
+	"deliveryId\"e\n" +
+	"\x11WatchUsersRequest\x12\x14\n" +
+	"\x05types\x18\x01 \x03(\tR\x05types\x12\x18\n" +
+	"\auserIds\x18\x02 \x03(\x03R\auserIds\x12 \n" +
+	"\vlastEventId\x18\x03 \x01(\tR\vlastEventId\"\xc8\x01\n" +
+	"\n" +
+	"UserChange\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x18\n" +
+	"\aeventId\x18\x03 \x01(\tR\aeventId\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12\x16\n" +
+	"\x06userId\x18\x05 \x01(\x03R\x06userId\x12\x1e\n" +
+	"\n" +
+	"occurredAt\x18\x06 \x01(\x03R\n" +
+	"occurredAt\x12\x1c\n" +
+	"\trequestId\x18\a \x01(\tR\trequestId\x12\x12\n" +
+	"\x04data\x18\b \x01(\tR\x04data\"r\n" +
+	"\x16BulkCreateUsersRequest\x12\x1e\n" +
+	"\x04user\x18\x01 \x01(\v2\n" +
+	".user.UserR\x04user\x12\x16\n" +
+	"\x06dryRun\x18\x02 \x01(\bR\x06dryRun\x12 \n" +
+	"\vonDuplicate\x18\x03 \x01(\tR\vonDuplicate\"H\n" +
+	"\x0eFieldViolation\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\"\xd7\x01\n" +
+	"\x0fImportRowResult\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x12\x12\n" +
+	"\x04line\x18\x02 \x01(\x05R\x04line\x12 \n" +
+	"\vuserAccount\x18\x03 \x01(\tR\vuserAccount\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x16\n" +
+	"\x06userId\x18\x05 \x01(\x03R\x06userId\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x124\n" +
+	"\n" +
+	"violations\x18\a \x03(\v2\x14.user.FieldViolationR\n" +
+	"violations\"\xee\x01\n" +
+	"\x17BulkCreateUsersResponse\x12\x16\n" +
+	"\x06dryRun\x18\x01 \x01(\bR\x06dryRun\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x18\n" +
+	"\acreated\x18\x03 \x01(\x05R\acreated\x12\x18\n" +
+	"\aupdated\x18\x04 \x01(\x05R\aupdated\x12\x18\n" +
+	"\askipped\x18\x05 \x01(\x05R\askipped\x12\x16\n" +
+	"\x06failed\x18\x06 \x01(\x05R\x06failed\x12)\n" +
+	"\x04rows\x18\a \x03(\v2\x15.user.ImportRowResultR\x04rows\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\"\"\n" +
+	"\x10ImportJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x82\x03\n" +
+	"\tImportJob\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
+	"\x06format\x18\x03 \x01(\tR\x06format\x12\x16\n" +
+	"\x06dryRun\x18\x04 \x01(\bR\x06dryRun\x12 \n" +
+	"\vonDuplicate\x18\x05 \x01(\tR\vonDuplicate\x12\x14\n" +
+	"\x05total\x18\x06 \x01(\x05R\x05total\x12\x18\n" +
+	"\acreated\x18\a \x01(\x05R\acreated\x12\x18\n" +
+	"\aupdated\x18\b \x01(\x05R\aupdated\x12\x18\n" +
+	"\askipped\x18\t \x01(\x05R\askipped\x12\x16\n" +
+	"\x06failed\x18\n" +
+	" \x01(\x05R\x06failed\x12\x14\n" +
+	"\x05error\x18\v \x01(\tR\x05error\x12\x1e\n" +
+	"\n" +
+	"createTime\x18\f \x01(\x03R\n" +
+	"createTime\x12\x1e\n" +
+	"\n" +
+	"finishTime\x18\r \x01(\x03R\n" +
+	"finishTime\x12)\n" +
+	"\x04rows\x18\x0e \x03(\v2\x15.user.ImportRowResultR\x04rows\".\n" +
+	"\x12ExportUsersRequest\x12\x18\n" +
+	"\acolumns\x18\x01 \x03(\tR\acolumns\"\x94\x03\n" +
+	"\fExportedUser\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12 \n" +
+	"\vuserAccount\x18\x02 \x01(\tR\vuserAccount\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\x12\x1c\n" +
+	"\tavatarUrl\x18\x04 \x01(\tR\tavatarUrl\x12\x16\n" +
+	"\x06gender\x18\x05 \x01(\x05R\x06gender\x12\x14\n" +
+	"\x05phone\x18\x06 \x01(\tR\x05phone\x12\x14\n" +
+	"\x05email\x18\a \x01(\tR\x05email\x12$\n" +
+	"\remailVerified\x18\b \x01(\bR\remailVerified\x12\x1e\n" +
+	"\n" +
+	"userStatus\x18\t \x01(\x05R\n" +
+	"userStatus\x12\x1a\n" +
+	"\buserRole\x18\n" +
+	" \x01(\x05R\buserRole\x12\x1e\n" +
+	"\n" +
+	"planetCode\x18\v \x01(\tR\n" +
+	"planetCode\x12\x1e\n" +
+	"\n" +
+	"createTime\x18\f \x01(\x03R\n" +
+	"createTime\x12\x1e\n" +
+	"\n" +
+	"updateTime\x18\r \x01(\x03R\n" +
+	"updateTimeJ\x04\b\x0e\x10\x0fR\fuserPassword2\xc5\x19\n" +
+	"\vUserService\x12D\n" +
 	"\n" +
 	"CreateUser\x12\n" +
-	".user.User\x1a\x14.user.CommonResponse\x120\n" +
-	"\x05Login\x12\x12.user.LoginRequest\x1a\x13.user.LoginResponse\x12*\n" +
+	".user.User\x1a\x14.user.CommonResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/users\x12L\n" +
+	"\x05Login\x12\x12.user.LoginRequest\x1a\x13.user.LoginResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/users/login\x12X\n" +
+	"\tVerifyMfa\x12\x16.user.VerifyMfaRequest\x1a\x13.user.LoginResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/users/login/mfa\x12i\n" +
+	"\x0eForgotPassword\x12\x1b.user.ForgotPasswordRequest\x1a\x14.user.CommonResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/users/password/forgot\x12f\n" +
+	"\rResetPassword\x12\x1a.user.ResetPasswordRequest\x1a\x14.user.CommonResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/users/password/reset\x12`\n" +
+	"\vVerifyEmail\x12\x18.user.VerifyEmailRequest\x1a\x14.user.CommonResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/users/email/verify\x12W\n" +
+	"\fRefreshToken\x12\x19.user.RefreshTokenRequest\x1a\x0f.user.TokenPair\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/auth/refresh\x12B\n" +
 	"\vGetUserByID\x12\x0f.user.IdRequest\x1a\n" +
-	".user.User\x124\n" +
+	".user.User\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/users/{id}\x12X\n" +
 	"\x10GetUserByAccount\x12\x14.user.AccountRequest\x1a\n" +
-	".user.User\x12C\n" +
-	"\x0eUpdatePassword\x12\x1b.user.UpdatePasswordRequest\x1a\x14.user.CommonResponse\x12<\n" +
-	"\tListUsers\x12\x16.user.ListUsersRequest\x1a\x17.user.ListUsersResponse\x123\n" +
+	".user.User\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/v1/accounts/{userAccount}\x12g\n" +
+	"\x0eUpdatePassword\x12\x1b.user.UpdatePasswordRequest\x1a\x14.user.CommonResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\x1a\x17/v1/users/{id}/password\x12{\n" +
+	"\x12ForceResetPassword\x12\x1f.user.ForceResetPasswordRequest\x1a\x14.user.CommonResponse\".\x82\xd3\xe4\x93\x02(:\x01*\"#/v1/users/{id}/password/force-reset\x12O\n" +
+	"\tListUsers\x12\x16.user.ListUsersRequest\x1a\x17.user.ListUsersResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v1/users\x129\n" +
+	"\n" +
+	"WatchUsers\x12\x17.user.WatchUsersRequest\x1a\x10.user.UserChange0\x01\x12=\n" +
+	"\vExportUsers\x12\x18.user.ExportUsersRequest\x1a\x12.user.ExportedUser0\x01\x12P\n" +
+	"\x0fBulkCreateUsers\x12\x1c.user.BulkCreateUsersRequest\x1a\x1d.user.BulkCreateUsersResponse(\x01\x12[\n" +
+	"\fGetImportJob\x12\x16.user.ImportJobRequest\x1a\x0f.user.ImportJob\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/v1/users/import/jobs/{id}\x12K\n" +
+	"\n" +
+	"DeleteUser\x12\x0f.user.IdRequest\x1a\x14.user.CommonResponse\"\x16\x82\xd3\xe4\x93\x02\x10*\x0e/v1/users/{id}\x12V\n" +
+	"\n" +
+	"UpdateUser\x12\x17.user.UpdateUserRequest\x1a\x14.user.CommonResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*2\x0e/v1/users/{id}\x12T\n" +
+	"\vSuspendUser\x12\x0f.user.IdRequest\x1a\x14.user.CommonResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\"\x16/v1/users/{id}/suspend\x12R\n" +
 	"\n" +
-	"DeleteUser\x12\x0f.user.IdRequest\x1a\x14.user.CommonResponse\x12;\n" +
+	"UnlockUser\x12\x0f.user.IdRequest\x1a\x14.user.CommonResponse\"\x1d\x82\xd3\xe4\x93\x02\x17\"\x15/v1/users/{id}/unlock\x12\\\n" +
+	"\fListSessions\x12\x0f.user.IdRequest\x1a\x1a.user.ListSessionsResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/users/{id}/sessions\x12r\n" +
+	"\rRevokeSession\x12\x1a.user.RevokeSessionRequest\x1a\x14.user.CommonResponse\"/\x82\xd3\xe4\x93\x02)*'/v1/users/{userId}/sessions/{sessionId}\x12d\n" +
+	"\x12RevokeUserSessions\x12\x0f.user.IdRequest\x1a\x1c.user.RevokeSessionsResponse\"\x1f\x82\xd3\xe4\x93\x02\x19*\x17/v1/users/{id}/sessions\x12m\n" +
+	"\fCreateApiKey\x12\x19.user.CreateApiKeyRequest\x1a\x1a.user.CreateApiKeyResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/users/{userId}/api-keys\x12Z\n" +
+	"\vListApiKeys\x12\x0f.user.IdRequest\x1a\x19.user.ListApiKeysResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/users/{id}/api-keys\x12l\n" +
+	"\fRevokeApiKey\x12\x19.user.RevokeApiKeyRequest\x1a\x14.user.CommonResponse\"+\x82\xd3\xe4\x93\x02%*#/v1/users/{userId}/api-keys/{keyId}\x12h\n" +
+	"\x0fListAuditEvents\x12\x1c.user.ListAuditEventsRequest\x1a\x1d.user.ListAuditEventsResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/audit/events\x12U\n" +
+	"\rCreateWebhook\x12\x14.user.WebhookRequest\x1a\x15.user.WebhookResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/webhooks\x12[\n" +
+	"\fListWebhooks\x12\x19.user.ListWebhooksRequest\x1a\x1a.user.ListWebhooksResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/webhooks\x12G\n" +
 	"\n" +
-	"UpdateUser\x12\x17.user.UpdateUserRequest\x1a\x14.user.CommonResponseB\x16Z\x14http_grpc/proto/userb\x06proto3"
+	"GetWebhook\x12\x0f.user.IdRequest\x1a\r.user.Webhook\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/webhooks/{id}\x12Z\n" +
+	"\rUpdateWebhook\x12\x14.user.WebhookRequest\x1a\x15.user.WebhookResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\x1a\x11/v1/webhooks/{id}\x12Q\n" +
+	"\rDeleteWebhook\x12\x0f.user.IdRequest\x1a\x14.user.CommonResponse\"\x19\x82\xd3\xe4\x93\x02\x13*\x11/v1/webhooks/{id}\x12U\n" +
+	"\vTestWebhook\x12\x0f.user.IdRequest\x1a\x15.user.WebhookDelivery\"\x1e\x82\xd3\xe4\x93\x02\x18\"\x16/v1/webhooks/{id}/test\x12\x86\x01\n" +
+	"\x15ListWebhookDeliveries\x12\".user.ListWebhookDeliveriesRequest\x1a#.user.ListWebhookDeliveriesResponse\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/v1/webhooks/{id}/deliveries\x12\x85\x01\n" +
+	"\x10RedeliverWebhook\x12\x1d.user.RedeliverWebhookRequest\x1a\x15.user.WebhookDelivery\";\x82\xd3\xe4\x93\x025\"3/v1/webhooks/{id}/deliveries/{deliveryId}/redeliverB\x16Z\x14http_grpc/proto/userb\x06proto3"
 
 var (
 	file_proto_user_user_proto_rawDescOnce sync.Once
@@ -650,49 +3700,155 @@ func file_proto_user_user_proto_rawDescGZIP() []byte {
 	return file_proto_user_user_proto_rawDescData
 }
 
-var file_proto_user_user_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_proto_user_user_proto_msgTypes = make([]protoimpl.MessageInfo, 48)
 var file_proto_user_user_proto_goTypes = []any{
-	(*User)(nil),                   // 0: user.User
-	(*CommonResponse)(nil),         // 1: user.CommonResponse
-	(*LoginRequest)(nil),           // 2: user.LoginRequest
-	(*LoginResponse)(nil),          // 3: user.LoginResponse
-	(*IdRequest)(nil),              // 4: user.IdRequest
-	(*AccountRequest)(nil),         // 5: user.AccountRequest
-	(*UpdatePasswordRequest)(nil),  // 6: user.UpdatePasswordRequest
-	(*ListUsersRequest)(nil),       // 7: user.ListUsersRequest
-	(*ListUsersResponse)(nil),      // 8: user.ListUsersResponse
-	(*UpdateUserRequest)(nil),      // 9: user.UpdateUserRequest
-	(*wrapperspb.StringValue)(nil), // 10: google.protobuf.StringValue
-	(*wrapperspb.Int32Value)(nil),  // 11: google.protobuf.Int32Value
+	(*User)(nil),                          // 0: user.User
+	(*CommonResponse)(nil),                // 1: user.CommonResponse
+	(*LoginRequest)(nil),                  // 2: user.LoginRequest
+	(*LoginResponse)(nil),                 // 3: user.LoginResponse
+	(*VerifyMfaRequest)(nil),              // 4: user.VerifyMfaRequest
+	(*ForgotPasswordRequest)(nil),         // 5: user.ForgotPasswordRequest
+	(*ResetPasswordRequest)(nil),          // 6: user.ResetPasswordRequest
+	(*VerifyEmailRequest)(nil),            // 7: user.VerifyEmailRequest
+	(*TokenPair)(nil),                     // 8: user.TokenPair
+	(*RefreshTokenRequest)(nil),           // 9: user.RefreshTokenRequest
+	(*IdRequest)(nil),                     // 10: user.IdRequest
+	(*AccountRequest)(nil),                // 11: user.AccountRequest
+	(*UpdatePasswordRequest)(nil),         // 12: user.UpdatePasswordRequest
+	(*ForceResetPasswordRequest)(nil),     // 13: user.ForceResetPasswordRequest
+	(*ListUsersRequest)(nil),              // 14: user.ListUsersRequest
+	(*ListUsersResponse)(nil),             // 15: user.ListUsersResponse
+	(*UpdateUserRequest)(nil),             // 16: user.UpdateUserRequest
+	(*Session)(nil),                       // 17: user.Session
+	(*ListSessionsResponse)(nil),          // 18: user.ListSessionsResponse
+	(*RevokeSessionRequest)(nil),          // 19: user.RevokeSessionRequest
+	(*RevokeSessionsResponse)(nil),        // 20: user.RevokeSessionsResponse
+	(*ApiKey)(nil),                        // 21: user.ApiKey
+	(*CreateApiKeyRequest)(nil),           // 22: user.CreateApiKeyRequest
+	(*CreateApiKeyResponse)(nil),          // 23: user.CreateApiKeyResponse
+	(*ListApiKeysResponse)(nil),           // 24: user.ListApiKeysResponse
+	(*RevokeApiKeyRequest)(nil),           // 25: user.RevokeApiKeyRequest
+	(*AuditEvent)(nil),                    // 26: user.AuditEvent
+	(*ListAuditEventsRequest)(nil),        // 27: user.ListAuditEventsRequest
+	(*ListAuditEventsResponse)(nil),       // 28: user.ListAuditEventsResponse
+	(*Webhook)(nil),                       // 29: user.Webhook
+	(*WebhookRequest)(nil),                // 30: user.WebhookRequest
+	(*WebhookResponse)(nil),               // 31: user.WebhookResponse
+	(*ListWebhooksRequest)(nil),           // 32: user.ListWebhooksRequest
+	(*ListWebhooksResponse)(nil),          // 33: user.ListWebhooksResponse
+	(*WebhookDelivery)(nil),               // 34: user.WebhookDelivery
+	(*ListWebhookDeliveriesRequest)(nil),  // 35: user.ListWebhookDeliveriesRequest
+	(*ListWebhookDeliveriesResponse)(nil), // 36: user.ListWebhookDeliveriesResponse
+	(*RedeliverWebhookRequest)(nil),       // 37: user.RedeliverWebhookRequest
+	(*WatchUsersRequest)(nil),             // 38: user.WatchUsersRequest
+	(*UserChange)(nil),                    // 39: user.UserChange
+	(*BulkCreateUsersRequest)(nil),        // 40: user.BulkCreateUsersRequest
+	(*FieldViolation)(nil),                // 41: user.FieldViolation
+	(*ImportRowResult)(nil),               // 42: user.ImportRowResult
+	(*BulkCreateUsersResponse)(nil),       // 43: user.BulkCreateUsersResponse
+	(*ImportJobRequest)(nil),              // 44: user.ImportJobRequest
+	(*ImportJob)(nil),                     // 45: user.ImportJob
+	(*ExportUsersRequest)(nil),            // 46: user.ExportUsersRequest
+	(*ExportedUser)(nil),                  // 47: user.ExportedUser
+	(*wrapperspb.StringValue)(nil),        // 48: google.protobuf.StringValue
+	(*wrapperspb.Int32Value)(nil),         // 49: google.protobuf.Int32Value
+	(*wrapperspb.BoolValue)(nil),          // 50: google.protobuf.BoolValue
 }
 var file_proto_user_user_proto_depIdxs = []int32{
-	0,  // 0: user.ListUsersResponse.users:type_name -> user.User
-	10, // 1: user.UpdateUserRequest.username:type_name -> google.protobuf.StringValue
-	10, // 2: user.UpdateUserRequest.avatarUrl:type_name -> google.protobuf.StringValue
-	11, // 3: user.UpdateUserRequest.gender:type_name -> google.protobuf.Int32Value
-	10, // 4: user.UpdateUserRequest.phone:type_name -> google.protobuf.StringValue
-	10, // 5: user.UpdateUserRequest.email:type_name -> google.protobuf.StringValue
-	0,  // 6: user.UserService.CreateUser:input_type -> user.User
-	2,  // 7: user.UserService.Login:input_type -> user.LoginRequest
-	4,  // 8: user.UserService.GetUserByID:input_type -> user.IdRequest
-	5,  // 9: user.UserService.GetUserByAccount:input_type -> user.AccountRequest
-	6,  // 10: user.UserService.UpdatePassword:input_type -> user.UpdatePasswordRequest
-	7,  // 11: user.UserService.ListUsers:input_type -> user.ListUsersRequest
-	4,  // 12: user.UserService.DeleteUser:input_type -> user.IdRequest
-	9,  // 13: user.UserService.UpdateUser:input_type -> user.UpdateUserRequest
-	1,  // 14: user.UserService.CreateUser:output_type -> user.CommonResponse
-	3,  // 15: user.UserService.Login:output_type -> user.LoginResponse
-	0,  // 16: user.UserService.GetUserByID:output_type -> user.User
-	0,  // 17: user.UserService.GetUserByAccount:output_type -> user.User
-	1,  // 18: user.UserService.UpdatePassword:output_type -> user.CommonResponse
-	8,  // 19: user.UserService.ListUsers:output_type -> user.ListUsersResponse
-	1,  // 20: user.UserService.DeleteUser:output_type -> user.CommonResponse
-	1,  // 21: user.UserService.UpdateUser:output_type -> user.CommonResponse
-	14, // [14:22] is the sub-list for method output_type
-	6,  // [6:14] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	8,  // 0: user.LoginResponse.tokens:type_name -> user.TokenPair
+	0,  // 1: user.ListUsersResponse.users:type_name -> user.User
+	48, // 2: user.UpdateUserRequest.username:type_name -> google.protobuf.StringValue
+	48, // 3: user.UpdateUserRequest.avatarUrl:type_name -> google.protobuf.StringValue
+	49, // 4: user.UpdateUserRequest.gender:type_name -> google.protobuf.Int32Value
+	48, // 5: user.UpdateUserRequest.phone:type_name -> google.protobuf.StringValue
+	48, // 6: user.UpdateUserRequest.email:type_name -> google.protobuf.StringValue
+	17, // 7: user.ListSessionsResponse.sessions:type_name -> user.Session
+	21, // 8: user.CreateApiKeyResponse.apiKey:type_name -> user.ApiKey
+	21, // 9: user.ListApiKeysResponse.apiKeys:type_name -> user.ApiKey
+	26, // 10: user.ListAuditEventsResponse.events:type_name -> user.AuditEvent
+	50, // 11: user.WebhookRequest.enabled:type_name -> google.protobuf.BoolValue
+	29, // 12: user.WebhookResponse.webhook:type_name -> user.Webhook
+	29, // 13: user.ListWebhooksResponse.webhooks:type_name -> user.Webhook
+	34, // 14: user.ListWebhookDeliveriesResponse.deliveries:type_name -> user.WebhookDelivery
+	0,  // 15: user.BulkCreateUsersRequest.user:type_name -> user.User
+	41, // 16: user.ImportRowResult.violations:type_name -> user.FieldViolation
+	42, // 17: user.BulkCreateUsersResponse.rows:type_name -> user.ImportRowResult
+	42, // 18: user.ImportJob.rows:type_name -> user.ImportRowResult
+	0,  // 19: user.UserService.CreateUser:input_type -> user.User
+	2,  // 20: user.UserService.Login:input_type -> user.LoginRequest
+	4,  // 21: user.UserService.VerifyMfa:input_type -> user.VerifyMfaRequest
+	5,  // 22: user.UserService.ForgotPassword:input_type -> user.ForgotPasswordRequest
+	6,  // 23: user.UserService.ResetPassword:input_type -> user.ResetPasswordRequest
+	7,  // 24: user.UserService.VerifyEmail:input_type -> user.VerifyEmailRequest
+	9,  // 25: user.UserService.RefreshToken:input_type -> user.RefreshTokenRequest
+	10, // 26: user.UserService.GetUserByID:input_type -> user.IdRequest
+	11, // 27: user.UserService.GetUserByAccount:input_type -> user.AccountRequest
+	12, // 28: user.UserService.UpdatePassword:input_type -> user.UpdatePasswordRequest
+	13, // 29: user.UserService.ForceResetPassword:input_type -> user.ForceResetPasswordRequest
+	14, // 30: user.UserService.ListUsers:input_type -> user.ListUsersRequest
+	38, // 31: user.UserService.WatchUsers:input_type -> user.WatchUsersRequest
+	46, // 32: user.UserService.ExportUsers:input_type -> user.ExportUsersRequest
+	40, // 33: user.UserService.BulkCreateUsers:input_type -> user.BulkCreateUsersRequest
+	44, // 34: user.UserService.GetImportJob:input_type -> user.ImportJobRequest
+	10, // 35: user.UserService.DeleteUser:input_type -> user.IdRequest
+	16, // 36: user.UserService.UpdateUser:input_type -> user.UpdateUserRequest
+	10, // 37: user.UserService.SuspendUser:input_type -> user.IdRequest
+	10, // 38: user.UserService.UnlockUser:input_type -> user.IdRequest
+	10, // 39: user.UserService.ListSessions:input_type -> user.IdRequest
+	19, // 40: user.UserService.RevokeSession:input_type -> user.RevokeSessionRequest
+	10, // 41: user.UserService.RevokeUserSessions:input_type -> user.IdRequest
+	22, // 42: user.UserService.CreateApiKey:input_type -> user.CreateApiKeyRequest
+	10, // 43: user.UserService.ListApiKeys:input_type -> user.IdRequest
+	25, // 44: user.UserService.RevokeApiKey:input_type -> user.RevokeApiKeyRequest
+	27, // 45: user.UserService.ListAuditEvents:input_type -> user.ListAuditEventsRequest
+	30, // 46: user.UserService.CreateWebhook:input_type -> user.WebhookRequest
+	32, // 47: user.UserService.ListWebhooks:input_type -> user.ListWebhooksRequest
+	10, // 48: user.UserService.GetWebhook:input_type -> user.IdRequest
+	30, // 49: user.UserService.UpdateWebhook:input_type -> user.WebhookRequest
+	10, // 50: user.UserService.DeleteWebhook:input_type -> user.IdRequest
+	10, // 51: user.UserService.TestWebhook:input_type -> user.IdRequest
+	35, // 52: user.UserService.ListWebhookDeliveries:input_type -> user.ListWebhookDeliveriesRequest
+	37, // 53: user.UserService.RedeliverWebhook:input_type -> user.RedeliverWebhookRequest
+	1,  // 54: user.UserService.CreateUser:output_type -> user.CommonResponse
+	3,  // 55: user.UserService.Login:output_type -> user.LoginResponse
+	3,  // 56: user.UserService.VerifyMfa:output_type -> user.LoginResponse
+	1,  // 57: user.UserService.ForgotPassword:output_type -> user.CommonResponse
+	1,  // 58: user.UserService.ResetPassword:output_type -> user.CommonResponse
+	1,  // 59: user.UserService.VerifyEmail:output_type -> user.CommonResponse
+	8,  // 60: user.UserService.RefreshToken:output_type -> user.TokenPair
+	0,  // 61: user.UserService.GetUserByID:output_type -> user.User
+	0,  // 62: user.UserService.GetUserByAccount:output_type -> user.User
+	1,  // 63: user.UserService.UpdatePassword:output_type -> user.CommonResponse
+	1,  // 64: user.UserService.ForceResetPassword:output_type -> user.CommonResponse
+	15, // 65: user.UserService.ListUsers:output_type -> user.ListUsersResponse
+	39, // 66: user.UserService.WatchUsers:output_type -> user.UserChange
+	47, // 67: user.UserService.ExportUsers:output_type -> user.ExportedUser
+	43, // 68: user.UserService.BulkCreateUsers:output_type -> user.BulkCreateUsersResponse
+	45, // 69: user.UserService.GetImportJob:output_type -> user.ImportJob
+	1,  // 70: user.UserService.DeleteUser:output_type -> user.CommonResponse
+	1,  // 71: user.UserService.UpdateUser:output_type -> user.CommonResponse
+	1,  // 72: user.UserService.SuspendUser:output_type -> user.CommonResponse
+	1,  // 73: user.UserService.UnlockUser:output_type -> user.CommonResponse
+	18, // 74: user.UserService.ListSessions:output_type -> user.ListSessionsResponse
+	1,  // 75: user.UserService.RevokeSession:output_type -> user.CommonResponse
+	20, // 76: user.UserService.RevokeUserSessions:output_type -> user.RevokeSessionsResponse
+	23, // 77: user.UserService.CreateApiKey:output_type -> user.CreateApiKeyResponse
+	24, // 78: user.UserService.ListApiKeys:output_type -> user.ListApiKeysResponse
+	1,  // 79: user.UserService.RevokeApiKey:output_type -> user.CommonResponse
+	28, // 80: user.UserService.ListAuditEvents:output_type -> user.ListAuditEventsResponse
+	31, // 81: user.UserService.CreateWebhook:output_type -> user.WebhookResponse
+	33, // 82: user.UserService.ListWebhooks:output_type -> user.ListWebhooksResponse
+	29, // 83: user.UserService.GetWebhook:output_type -> user.Webhook
+	31, // 84: user.UserService.UpdateWebhook:output_type -> user.WebhookResponse
+	1,  // 85: user.UserService.DeleteWebhook:output_type -> user.CommonResponse
+	34, // 86: user.UserService.TestWebhook:output_type -> user.WebhookDelivery
+	36, // 87: user.UserService.ListWebhookDeliveries:output_type -> user.ListWebhookDeliveriesResponse
+	34, // 88: user.UserService.RedeliverWebhook:output_type -> user.WebhookDelivery
+	54, // [54:89] is the sub-list for method output_type
+	19, // [19:54] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
 }
 
 func init() { file_proto_user_user_proto_init() }
@@ -706,7 +3862,7 @@ func file_proto_user_user_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_user_user_proto_rawDesc), len(file_proto_user_user_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   48,
 			NumExtensions: 0,
 			NumServices:   1,
 		},